@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// ArrayLiteral AST node for a list of expressions enclosed in brackets
+type ArrayLiteral struct {
+	Token    token.Token // the '[' token
+	Elements []Expression
+}
+
+func (array *ArrayLiteral) expressionNode() {}
+
+func (array *ArrayLiteral) TokenLiteral() string {
+	return array.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (array *ArrayLiteral) Pos() token.Position {
+	return array.Token.Position
+}
+
+// String Returns a string representation of an ArrayLiteral
+func (array *ArrayLiteral) String() string {
+	var out bytes.Buffer
+	elements := make([]string, 0, len(array.Elements))
+	for _, element := range array.Elements {
+		elements = append(elements, element.String())
+	}
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+// NewArrayLiteral Creates a new, empty ArrayLiteral and returns a reference to it
+func NewArrayLiteral(tok token.Token) *ArrayLiteral {
+	return &ArrayLiteral{
+		Token:    tok,
+		Elements: make([]Expression, 0),
+	}
+}
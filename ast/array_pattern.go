@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// ArrayPattern AST node binding the elements of an array to identifiers,
+// the optional Rest identifier is bound to an array of the remaining elements
+type ArrayPattern struct {
+	Token    token.Token // the '[' token
+	Elements []*Identifier
+	Rest     *Identifier
+}
+
+func (pattern *ArrayPattern) expressionNode() {}
+
+func (pattern *ArrayPattern) TokenLiteral() string {
+	return pattern.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (pattern *ArrayPattern) Pos() token.Position {
+	return pattern.Token.Position
+}
+
+// Names Returns the identifiers bound by the pattern, wildcards excluded
+func (pattern *ArrayPattern) Names() []*Identifier {
+	names := make([]*Identifier, 0, len(pattern.Elements)+1)
+	for _, element := range pattern.Elements {
+		if element.Value != WILDCARD {
+			names = append(names, element)
+		}
+	}
+	if pattern.Rest != nil && pattern.Rest.Value != WILDCARD {
+		names = append(names, pattern.Rest)
+	}
+	return names
+}
+
+// String Returns a string representation of an ArrayPattern
+func (pattern *ArrayPattern) String() string {
+	var out bytes.Buffer
+	elements := make([]string, 0, len(pattern.Elements)+1)
+	for _, element := range pattern.Elements {
+		elements = append(elements, element.String())
+	}
+	if pattern.Rest != nil {
+		elements = append(elements, "..."+pattern.Rest.String())
+	}
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+// NewArrayPattern Creates a new, empty ArrayPattern and returns a reference to it
+func NewArrayPattern(tok token.Token) *ArrayPattern {
+	return &ArrayPattern{
+		Token:    tok,
+		Elements: make([]*Identifier, 0),
+	}
+}
@@ -203,7 +203,7 @@ func TestBlockStatement(t *testing.T) {
 	block := getTestBlockStatment(t)
 	block.statementNode()
 	if len(block.Statements) != 1 {
-		t.Fatalf("Unexpected number of statements. Expected=1, Got=%s", len(block.Statements))
+		t.Fatalf("Unexpected number of statements. Expected=1, Got=%d", len(block.Statements))
 	}
 	exprectedString := "x"
 	if block.String() != exprectedString {
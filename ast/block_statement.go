@@ -18,6 +18,11 @@ func (block *BlockStatement) TokenLiteral() string {
 	return block.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (block *BlockStatement) Pos() token.Position {
+	return block.Token.Position
+}
+
 // String Returns string representation of a BlockStatement
 func (block *BlockStatement) String() string {
 	var out bytes.Buffer
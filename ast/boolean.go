@@ -14,6 +14,11 @@ func (boolean *Boolean) TokenLiteral() string {
 	return boolean.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (boolean *Boolean) Pos() token.Position {
+	return boolean.Token.Position
+}
+
 func (boolean *Boolean) String() string {
 	return boolean.TokenLiteral()
 }
@@ -20,6 +20,11 @@ func (call *CallExpression) TokenLiteral() string {
 	return call.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (call *CallExpression) Pos() token.Position {
+	return call.Token.Position
+}
+
 // String Retruns a string representation of a CallExpression
 func (call *CallExpression) String() string {
 	var out bytes.Buffer
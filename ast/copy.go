@@ -0,0 +1,204 @@
+package ast
+
+// Copy Returns a deep copy of an AST, so that the copy can be
+// rewritten without modifying the original nodes
+func Copy(node Node) Node {
+	switch n := node.(type) {
+	case *Program:
+		c := *n
+		c.Statements = copyStatements(n.Statements)
+		return &c
+	case *BlockStatement:
+		return copyBlock(n)
+	case *ExpressionStatement:
+		c := *n
+		c.Expression = copyExpression(n.Expression)
+		return &c
+	case *LetStatement:
+		return copyLet(n)
+	case *FunctionStatement:
+		c := *n
+		c.Name = copyIdentifier(n.Name)
+		if n.Function != nil {
+			c.Function = Copy(n.Function).(*FunctionLiteral)
+		}
+		return &c
+	case *ReturnStatement:
+		c := *n
+		c.ReturnValue = copyExpression(n.ReturnValue)
+		return &c
+	case *ThrowStatement:
+		c := *n
+		c.Value = copyExpression(n.Value)
+		return &c
+	case *ExportStatement:
+		c := *n
+		c.Statement = copyLet(n.Statement)
+		return &c
+	case *Identifier:
+		return copyIdentifier(n)
+	case *IntegerLiteral:
+		c := *n
+		return &c
+	case *Boolean:
+		c := *n
+		return &c
+	case *StringLiteral:
+		c := *n
+		return &c
+	case *PrefixExpression:
+		c := *n
+		c.Right = copyExpression(n.Right)
+		return &c
+	case *InfixExpression:
+		c := *n
+		c.Left = copyExpression(n.Left)
+		c.Right = copyExpression(n.Right)
+		return &c
+	case *IFExpression:
+		c := *n
+		c.Condition = copyExpression(n.Condition)
+		c.Consequence = copyBlock(n.Consequence)
+		c.Alternative = copyBlock(n.Alternative)
+		return &c
+	case *MatchExpression:
+		c := *n
+		c.Subject = copyExpression(n.Subject)
+		c.Arms = make([]MatchArm, len(n.Arms))
+		for i, arm := range n.Arms {
+			c.Arms[i] = MatchArm{
+				Pattern: copyExpression(arm.Pattern),
+				Guard:   copyExpression(arm.Guard),
+				Body:    copyExpression(arm.Body),
+			}
+		}
+		return &c
+	case *TryExpression:
+		c := *n
+		c.Body = copyBlock(n.Body)
+		c.CatchParam = copyIdentifier(n.CatchParam)
+		c.Catch = copyBlock(n.Catch)
+		c.Finally = copyBlock(n.Finally)
+		return &c
+	case *FunctionLiteral:
+		c := *n
+		c.Parameters = copyIdentifiers(n.Parameters)
+		c.Body = copyBlock(n.Body)
+		return &c
+	case *MacroLiteral:
+		c := *n
+		c.Parameters = copyIdentifiers(n.Parameters)
+		c.Body = copyBlock(n.Body)
+		return &c
+	case *CallExpression:
+		c := *n
+		c.Function = copyExpression(n.Function)
+		c.Arguments = copyExpressions(n.Arguments)
+		return &c
+	case *ArrayLiteral:
+		c := *n
+		c.Elements = copyExpressions(n.Elements)
+		return &c
+	case *HashLiteral:
+		c := *n
+		c.Pairs = make([]HashPair, len(n.Pairs))
+		for i, pair := range n.Pairs {
+			c.Pairs[i] = HashPair{Key: copyExpression(pair.Key), Value: copyExpression(pair.Value)}
+		}
+		return &c
+	case *IndexExpression:
+		c := *n
+		c.Left = copyExpression(n.Left)
+		c.Index = copyExpression(n.Index)
+		return &c
+	case *ImportExpression:
+		c := *n
+		c.Path = copyExpression(n.Path)
+		return &c
+	case *MemberExpression:
+		c := *n
+		c.Object = copyExpression(n.Object)
+		c.Property = copyIdentifier(n.Property)
+		return &c
+	case *ArrayPattern:
+		c := *n
+		c.Elements = copyIdentifiers(n.Elements)
+		c.Rest = copyIdentifier(n.Rest)
+		return &c
+	case *HashPattern:
+		c := *n
+		c.Keys = copyIdentifiers(n.Keys)
+		return &c
+	}
+	return node
+}
+
+// copyStatements Copies a list of statements
+func copyStatements(stmts []Statement) []Statement {
+	copied := make([]Statement, len(stmts))
+	for i, stmt := range stmts {
+		if stmt != nil {
+			copied[i] = Copy(stmt).(Statement)
+		}
+	}
+	return copied
+}
+
+// copyExpressions Copies a list of expressions
+func copyExpressions(exprs []Expression) []Expression {
+	copied := make([]Expression, len(exprs))
+	for i, expr := range exprs {
+		copied[i] = copyExpression(expr)
+	}
+	return copied
+}
+
+// copyExpression Copies an expression if it is not nil
+func copyExpression(expr Expression) Expression {
+	if expr == nil {
+		return nil
+	}
+	return Copy(expr).(Expression)
+}
+
+// copyBlock Copies a BlockStatement if it is not nil
+func copyBlock(block *BlockStatement) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	c := *block
+	c.Statements = copyStatements(block.Statements)
+	return &c
+}
+
+// copyLet Copies a LetStatement if it is not nil
+func copyLet(letStmt *LetStatement) *LetStatement {
+	if letStmt == nil {
+		return nil
+	}
+	c := *letStmt
+	c.Name = copyIdentifier(letStmt.Name)
+	if letStmt.Pattern != nil {
+		c.Pattern = Copy(letStmt.Pattern).(Pattern)
+	}
+	c.Value = copyExpression(letStmt.Value)
+	return &c
+}
+
+// copyIdentifiers Copies a list of identifiers
+func copyIdentifiers(ids []*Identifier) []*Identifier {
+	copied := make([]*Identifier, len(ids))
+	for i, id := range ids {
+		copied[i] = copyIdentifier(id)
+	}
+	return copied
+}
+
+// copyIdentifier Copies an Identifier if it is not nil
+func copyIdentifier(id *Identifier) *Identifier {
+	if id == nil {
+		return nil
+	}
+	c := *id
+	return &c
+}
@@ -0,0 +1,32 @@
+package ast
+
+import "github.com/CzarSimon/monkey/token"
+
+// ExportStatement AST node for a let binding made available to importers of a module
+type ExportStatement struct {
+	Token     token.Token // the 'export' token
+	Statement *LetStatement
+}
+
+func (export *ExportStatement) statementNode() {}
+
+func (export *ExportStatement) TokenLiteral() string {
+	return export.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (export *ExportStatement) Pos() token.Position {
+	return export.Token.Position
+}
+
+// String Returns a string representation of an ExportStatement
+func (export *ExportStatement) String() string {
+	return export.TokenLiteral() + " " + export.Statement.String()
+}
+
+// NewExportStatement Creates a new ExportStatement and returns a reference to it
+func NewExportStatement(tok token.Token) *ExportStatement {
+	return &ExportStatement{
+		Token: tok,
+	}
+}
@@ -15,6 +15,11 @@ func (stmt ExpressionStatement) TokenLiteral() string {
 	return stmt.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (stmt *ExpressionStatement) Pos() token.Position {
+	return stmt.Token.Position
+}
+
 // NewExpressionStatement Creates a new ExpressionStatement and retruns a reference to it
 func NewExpressionStatement(tok token.Token) *ExpressionStatement {
 	return &ExpressionStatement{
@@ -10,6 +10,7 @@ import (
 // FunctionLiteral AST node for representing function declaration
 type FunctionLiteral struct {
 	Token      token.Token
+	Name       string // name the function is bound to by a let or function statement, if any
 	Parameters []*Identifier
 	Body       *BlockStatement
 }
@@ -20,6 +21,11 @@ func (fn *FunctionLiteral) TokenLiteral() string {
 	return fn.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (fn *FunctionLiteral) Pos() token.Position {
+	return fn.Token.Position
+}
+
 // Stirng Retrurns a string representation of a FunctionLiteral
 func (fn *FunctionLiteral) String() string {
 	var out bytes.Buffer
@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// FunctionStatement AST node for declaring a named function,
+// the name is bound before the other statements of its block are evaluated
+type FunctionStatement struct {
+	Token    token.Token // the 'fn' token
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+func (fnStmt *FunctionStatement) statementNode() {}
+
+// TokenLiteral Retruns the node token literal
+func (fnStmt *FunctionStatement) TokenLiteral() string {
+	return fnStmt.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (fnStmt *FunctionStatement) Pos() token.Position {
+	return fnStmt.Token.Position
+}
+
+// String Returns a string representation of the FunctionStatement node
+func (fnStmt *FunctionStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(fnStmt.TokenLiteral() + " ")
+	out.WriteString(fnStmt.Name.String())
+	out.WriteString(strings.TrimPrefix(fnStmt.Function.String(), fnStmt.Function.TokenLiteral()))
+	return out.String()
+}
+
+// NewFunctionStatement Creates a new partially populated FunctionStatement and returns its reference
+func NewFunctionStatement(tok token.Token) *FunctionStatement {
+	return &FunctionStatement{
+		Token: tok,
+	}
+}
@@ -0,0 +1,52 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// HashPair Key and value expression of an entry in a HashLiteral
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+// HashLiteral AST node for a list of key: value pairs enclosed in braces
+type HashLiteral struct {
+	Token token.Token // the '{' token
+	Pairs []HashPair  // pairs in source order
+}
+
+func (hash *HashLiteral) expressionNode() {}
+
+func (hash *HashLiteral) TokenLiteral() string {
+	return hash.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (hash *HashLiteral) Pos() token.Position {
+	return hash.Token.Position
+}
+
+// String Returns a string representation of a HashLiteral
+func (hash *HashLiteral) String() string {
+	var out bytes.Buffer
+	pairs := make([]string, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// NewHashLiteral Creates a new, empty HashLiteral and returns a reference to it
+func NewHashLiteral(tok token.Token) *HashLiteral {
+	return &HashLiteral{
+		Token: tok,
+		Pairs: make([]HashPair, 0),
+	}
+}
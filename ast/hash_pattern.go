@@ -0,0 +1,52 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// HashPattern AST node binding the values of a hash stored under
+// string keys to identifiers named as the keys
+type HashPattern struct {
+	Token token.Token // the '{' token
+	Keys  []*Identifier
+}
+
+func (pattern *HashPattern) expressionNode() {}
+
+func (pattern *HashPattern) TokenLiteral() string {
+	return pattern.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (pattern *HashPattern) Pos() token.Position {
+	return pattern.Token.Position
+}
+
+// Names Returns the identifiers bound by the pattern
+func (pattern *HashPattern) Names() []*Identifier {
+	return pattern.Keys
+}
+
+// String Returns a string representation of a HashPattern
+func (pattern *HashPattern) String() string {
+	var out bytes.Buffer
+	keys := make([]string, 0, len(pattern.Keys))
+	for _, key := range pattern.Keys {
+		keys = append(keys, key.String())
+	}
+	out.WriteString("{")
+	out.WriteString(strings.Join(keys, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// NewHashPattern Creates a new, empty HashPattern and returns a reference to it
+func NewHashPattern(tok token.Token) *HashPattern {
+	return &HashPattern{
+		Token: tok,
+		Keys:  make([]*Identifier, 0),
+	}
+}
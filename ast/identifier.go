@@ -17,6 +17,11 @@ func (id Identifier) TokenLiteral() string {
 	return id.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (id *Identifier) Pos() token.Position {
+	return id.Token.Position
+}
+
 // NewIdentifier Creates a new identifier and returns its reference
 func NewIdentifier(tok token.Token, value string) *Identifier {
 	return &Identifier{
@@ -20,6 +20,11 @@ func (ifExpr *IFExpression) TokenLiteral() string {
 	return ifExpr.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (ifExpr *IFExpression) Pos() token.Position {
+	return ifExpr.Token.Position
+}
+
 // String Returns the string represtation of an IFExpression
 func (ifExpr *IFExpression) String() string {
 	var out bytes.Buffer
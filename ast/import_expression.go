@@ -0,0 +1,32 @@
+package ast
+
+import "github.com/CzarSimon/monkey/token"
+
+// ImportExpression AST node for importing the module stored in another file
+type ImportExpression struct {
+	Token token.Token // the 'import' token
+	Path  Expression
+}
+
+func (importExpr *ImportExpression) expressionNode() {}
+
+func (importExpr *ImportExpression) TokenLiteral() string {
+	return importExpr.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (importExpr *ImportExpression) Pos() token.Position {
+	return importExpr.Token.Position
+}
+
+// String Returns a string representation of an ImportExpression
+func (importExpr *ImportExpression) String() string {
+	return importExpr.TokenLiteral() + " " + importExpr.Path.String()
+}
+
+// NewImportExpression Creates a new ImportExpression and returns a reference to it
+func NewImportExpression(tok token.Token) *ImportExpression {
+	return &ImportExpression{
+		Token: tok,
+	}
+}
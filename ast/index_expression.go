@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// IndexExpression AST node for accessing an element of a collection
+type IndexExpression struct {
+	Token token.Token // the '[' token
+	Left  Expression
+	Index Expression
+}
+
+func (indexExpr *IndexExpression) expressionNode() {}
+
+func (indexExpr *IndexExpression) TokenLiteral() string {
+	return indexExpr.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (indexExpr *IndexExpression) Pos() token.Position {
+	return indexExpr.Token.Position
+}
+
+// String Returns a string representation of an IndexExpression
+func (indexExpr *IndexExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(indexExpr.Left.String())
+	out.WriteString("[")
+	out.WriteString(indexExpr.Index.String())
+	out.WriteString("])")
+	return out.String()
+}
+
+// NewIndexExpression Creates a new IndexExpression and returns a reference to it
+func NewIndexExpression(tok token.Token, left Expression) *IndexExpression {
+	return &IndexExpression{
+		Token: tok,
+		Left:  left,
+	}
+}
@@ -21,6 +21,11 @@ func (infixExpr *InfixExpression) TokenLiteral() string {
 	return infixExpr.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (infixExpr *InfixExpression) Pos() token.Position {
+	return infixExpr.Token.Position
+}
+
 // String Returns string representation of an InfixExpression
 func (infixExpr *InfixExpression) String() string {
 	var out bytes.Buffer
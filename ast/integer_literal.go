@@ -20,6 +20,11 @@ func (intLiteral *IntegerLiteral) TokenLiteral() string {
 	return intLiteral.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (intLiteral *IntegerLiteral) Pos() token.Position {
+	return intLiteral.Token.Position
+}
+
 // String Returns string representation of IntegerLiteral
 func (intLiteral *IntegerLiteral) String() string {
 	return intLiteral.TokenLiteral()
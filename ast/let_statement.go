@@ -6,11 +6,13 @@ import (
 	"github.com/CzarSimon/monkey/token"
 )
 
-// LetStatement AST node for variable assignement
+// LetStatement AST node for variable assignement, the value is either
+// bound to Name or destructured by Pattern
 type LetStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Pattern Pattern
+	Value   Expression
 }
 
 func (letStmt LetStatement) statementNode() {}
@@ -20,6 +22,24 @@ func (letStmt LetStatement) TokenLiteral() string {
 	return letStmt.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (letStmt *LetStatement) Pos() token.Position {
+	return letStmt.Token.Position
+}
+
+// IsConst Checks if the statement declares a constant binding
+func (letStmt *LetStatement) IsConst() bool {
+	return letStmt.Token.Type == token.CONST
+}
+
+// Names Returns the identifiers bound by the statement
+func (letStmt *LetStatement) Names() []*Identifier {
+	if letStmt.Pattern != nil {
+		return letStmt.Pattern.Names()
+	}
+	return []*Identifier{letStmt.Name}
+}
+
 // NewLetStatement Creates a new partially populated LetStatement and returns its reference
 func NewLetStatement(tok token.Token) *LetStatement {
 	return &LetStatement{
@@ -31,7 +51,11 @@ func NewLetStatement(tok token.Token) *LetStatement {
 func (letStmt *LetStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(letStmt.TokenLiteral() + " ")
-	out.WriteString(letStmt.Name.String() + " = ")
+	if letStmt.Pattern != nil {
+		out.WriteString(letStmt.Pattern.String() + " = ")
+	} else {
+		out.WriteString(letStmt.Name.String() + " = ")
+	}
 	if letStmt.Value != nil {
 		out.WriteString(letStmt.Value.String())
 	}
@@ -0,0 +1,50 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// MacroLiteral AST node for a macro, a function called with the unevaluated
+// AST of its arguments and returning the quoted code replacing the call
+type MacroLiteral struct {
+	Token      token.Token // the 'macro' token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (macro *MacroLiteral) expressionNode() {}
+
+func (macro *MacroLiteral) TokenLiteral() string {
+	return macro.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (macro *MacroLiteral) Pos() token.Position {
+	return macro.Token.Position
+}
+
+// String Returns a string representation of a MacroLiteral
+func (macro *MacroLiteral) String() string {
+	var out bytes.Buffer
+	params := make([]string, 0, len(macro.Parameters))
+	for _, param := range macro.Parameters {
+		params = append(params, param.String())
+	}
+	out.WriteString(macro.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	out.WriteString(macro.Body.String())
+	return out.String()
+}
+
+// NewMacroLiteral Creates a new MacroLiteral without parameters and returns a reference to it
+func NewMacroLiteral(tok token.Token) *MacroLiteral {
+	return &MacroLiteral{
+		Token:      tok,
+		Parameters: make([]*Identifier, 0),
+	}
+}
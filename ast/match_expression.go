@@ -0,0 +1,71 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// MatchArm Pattern, optional guard and body of an arm in a MatchExpression.
+// Patterns are expressions restricted to literals, identifiers binding the
+// matched value, the wildcard _ and array and hash literals of patterns
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression // optional condition that must be truthy for the arm to match
+	Body    Expression
+}
+
+// String Returns a string representation of a MatchArm
+func (arm MatchArm) String() string {
+	var out bytes.Buffer
+	out.WriteString(arm.Pattern.String())
+	if arm.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(arm.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(arm.Body.String())
+	return out.String()
+}
+
+// MatchExpression AST node for selecting the first arm whose pattern matches a value
+type MatchExpression struct {
+	Token   token.Token // the 'match' token
+	Subject Expression
+	Arms    []MatchArm
+}
+
+func (matchExpr *MatchExpression) expressionNode() {}
+
+func (matchExpr *MatchExpression) TokenLiteral() string {
+	return matchExpr.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (matchExpr *MatchExpression) Pos() token.Position {
+	return matchExpr.Token.Position
+}
+
+// String Returns the string represtation of a MatchExpression
+func (matchExpr *MatchExpression) String() string {
+	var out bytes.Buffer
+	arms := make([]string, 0, len(matchExpr.Arms))
+	for _, arm := range matchExpr.Arms {
+		arms = append(arms, arm.String())
+	}
+	out.WriteString("match (")
+	out.WriteString(matchExpr.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+// NewMatchExpression Creates a new MatchExpression without arms and returns a reference to it
+func NewMatchExpression(tok token.Token) *MatchExpression {
+	return &MatchExpression{
+		Token: tok,
+		Arms:  make([]MatchArm, 0),
+	}
+}
@@ -0,0 +1,34 @@
+package ast
+
+import "github.com/CzarSimon/monkey/token"
+
+// MemberExpression AST node for accessing a named member of an object, e.g. module.name
+type MemberExpression struct {
+	Token    token.Token // the '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+func (member *MemberExpression) expressionNode() {}
+
+func (member *MemberExpression) TokenLiteral() string {
+	return member.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (member *MemberExpression) Pos() token.Position {
+	return member.Token.Position
+}
+
+// String Returns a string representation of a MemberExpression
+func (member *MemberExpression) String() string {
+	return member.Object.String() + "." + member.Property.String()
+}
+
+// NewMemberExpression Creates a new MemberExpression and returns a reference to it
+func NewMemberExpression(tok token.Token, object Expression) *MemberExpression {
+	return &MemberExpression{
+		Token:  tok,
+		Object: object,
+	}
+}
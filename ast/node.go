@@ -1,7 +1,10 @@
 package ast
 
+import "github.com/CzarSimon/monkey/token"
+
 // Node Interface of types in the ast
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position
 }
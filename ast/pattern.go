@@ -0,0 +1,10 @@
+package ast
+
+// WILDCARD Identifier used in patterns to match a value without binding it
+const WILDCARD = "_"
+
+// Pattern A type of expression destructuring a value into bindings
+type Pattern interface {
+	Expression
+	Names() []*Identifier
+}
@@ -21,6 +21,11 @@ func (prefixExpr *PrefixExpression) TokenLiteral() string {
 	return prefixExpr.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (prefixExpr *PrefixExpression) Pos() token.Position {
+	return prefixExpr.Token.Position
+}
+
 // String Retruns a string representation of a PrefixExpression
 func (prefixExpr *PrefixExpression) String() string {
 	var out bytes.Buffer
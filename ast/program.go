@@ -1,6 +1,10 @@
 package ast
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/CzarSimon/monkey/token"
+)
 
 // Program Slice of statements representing a full program
 type Program struct {
@@ -28,6 +32,14 @@ func (program *Program) TokenLiteral() string {
 	}
 }
 
+// Pos Returns the position of the first statement in the program
+func (program *Program) Pos() token.Position {
+	if len(program.Statements) > 0 {
+		return program.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+
 // String Returns a string representation of the Program node
 func (program *Program) String() string {
 	var out bytes.Buffer
@@ -19,6 +19,11 @@ func (returnStmt ReturnStatement) TokenLiteral() string {
 	return returnStmt.Token.Literal
 }
 
+// Pos Returns the position of the node in the source code
+func (returnStmt *ReturnStatement) Pos() token.Position {
+	return returnStmt.Token.Position
+}
+
 // NewReturnStatement Creates a new ReturnStatement and retruns a reference to it
 func NewReturnStatement(tok token.Token) *ReturnStatement {
 	return &ReturnStatement{
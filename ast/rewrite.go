@@ -0,0 +1,162 @@
+package ast
+
+// RewriteFunc Function called by Rewrite with a node whose children have
+// already been rewritten, returns the node that should take its place
+type RewriteFunc func(Node) Node
+
+// Rewrite Traverses an AST depth first and replaces each node with the result
+// of calling fn on it, children are rewritten before their parents.
+// A statement rewritten to nil is removed from its Program or BlockStatement,
+// any other replacement that does not fit the place of the original node
+// (e.g. a Statement where an Expression is expected) is ignored
+func Rewrite(node Node, fn RewriteFunc) Node {
+	switch n := node.(type) {
+	case *Program:
+		n.Statements = rewriteStatements(n.Statements, fn)
+	case *BlockStatement:
+		n.Statements = rewriteStatements(n.Statements, fn)
+	case *ExpressionStatement:
+		n.Expression = rewriteExpression(n.Expression, fn)
+	case *LetStatement:
+		n.Name = rewriteIdentifier(n.Name, fn)
+		if n.Pattern != nil {
+			if pattern, ok := Rewrite(n.Pattern, fn).(Pattern); ok && pattern != nil {
+				n.Pattern = pattern
+			}
+		}
+		n.Value = rewriteExpression(n.Value, fn)
+	case *FunctionStatement:
+		n.Name = rewriteIdentifier(n.Name, fn)
+		if n.Function != nil {
+			if fnLit, ok := Rewrite(n.Function, fn).(*FunctionLiteral); ok && fnLit != nil {
+				n.Function = fnLit
+			}
+		}
+	case *ReturnStatement:
+		n.ReturnValue = rewriteExpression(n.ReturnValue, fn)
+	case *ThrowStatement:
+		n.Value = rewriteExpression(n.Value, fn)
+	case *PrefixExpression:
+		n.Right = rewriteExpression(n.Right, fn)
+	case *InfixExpression:
+		n.Left = rewriteExpression(n.Left, fn)
+		n.Right = rewriteExpression(n.Right, fn)
+	case *IFExpression:
+		n.Condition = rewriteExpression(n.Condition, fn)
+		n.Consequence = rewriteBlock(n.Consequence, fn)
+		n.Alternative = rewriteBlock(n.Alternative, fn)
+	case *MatchExpression:
+		n.Subject = rewriteExpression(n.Subject, fn)
+		for i, arm := range n.Arms {
+			n.Arms[i].Pattern = rewriteExpression(arm.Pattern, fn)
+			n.Arms[i].Guard = rewriteExpression(arm.Guard, fn)
+			n.Arms[i].Body = rewriteExpression(arm.Body, fn)
+		}
+	case *TryExpression:
+		n.Body = rewriteBlock(n.Body, fn)
+		n.CatchParam = rewriteIdentifier(n.CatchParam, fn)
+		n.Catch = rewriteBlock(n.Catch, fn)
+		n.Finally = rewriteBlock(n.Finally, fn)
+	case *FunctionLiteral:
+		for i, param := range n.Parameters {
+			n.Parameters[i] = rewriteIdentifier(param, fn)
+		}
+		n.Body = rewriteBlock(n.Body, fn)
+	case *MacroLiteral:
+		for i, param := range n.Parameters {
+			n.Parameters[i] = rewriteIdentifier(param, fn)
+		}
+		n.Body = rewriteBlock(n.Body, fn)
+	case *CallExpression:
+		n.Function = rewriteExpression(n.Function, fn)
+		for i, arg := range n.Arguments {
+			n.Arguments[i] = rewriteExpression(arg, fn)
+		}
+	case *ArrayLiteral:
+		for i, element := range n.Elements {
+			n.Elements[i] = rewriteExpression(element, fn)
+		}
+	case *HashLiteral:
+		for i, pair := range n.Pairs {
+			n.Pairs[i].Key = rewriteExpression(pair.Key, fn)
+			n.Pairs[i].Value = rewriteExpression(pair.Value, fn)
+		}
+	case *ArrayPattern:
+		for i, element := range n.Elements {
+			n.Elements[i] = rewriteIdentifier(element, fn)
+		}
+		n.Rest = rewriteIdentifier(n.Rest, fn)
+	case *HashPattern:
+		for i, key := range n.Keys {
+			n.Keys[i] = rewriteIdentifier(key, fn)
+		}
+	case *IndexExpression:
+		n.Left = rewriteExpression(n.Left, fn)
+		n.Index = rewriteExpression(n.Index, fn)
+	case *ImportExpression:
+		n.Path = rewriteExpression(n.Path, fn)
+	case *MemberExpression:
+		n.Object = rewriteExpression(n.Object, fn)
+		n.Property = rewriteIdentifier(n.Property, fn)
+	case *ExportStatement:
+		if n.Statement != nil {
+			if letStmt, ok := Rewrite(n.Statement, fn).(*LetStatement); ok && letStmt != nil {
+				n.Statement = letStmt
+			}
+		}
+	}
+	return fn(node)
+}
+
+// rewriteStatements Rewrites a list of statements, dropping the ones rewritten to nil
+func rewriteStatements(stmts []Statement, fn RewriteFunc) []Statement {
+	rewritten := make([]Statement, 0, len(stmts))
+	for _, stmt := range stmts {
+		if stmt == nil {
+			continue
+		}
+		newNode := Rewrite(stmt, fn)
+		if newNode == nil {
+			continue
+		}
+		if newStmt, ok := newNode.(Statement); ok {
+			rewritten = append(rewritten, newStmt)
+		} else {
+			rewritten = append(rewritten, stmt)
+		}
+	}
+	return rewritten
+}
+
+// rewriteExpression Rewrites an expression, keeping the original if the replacement is not an Expression
+func rewriteExpression(expr Expression, fn RewriteFunc) Expression {
+	if expr == nil {
+		return nil
+	}
+	if newExpr, ok := Rewrite(expr, fn).(Expression); ok && newExpr != nil {
+		return newExpr
+	}
+	return expr
+}
+
+// rewriteBlock Rewrites a BlockStatement, keeping the original if the replacement is not a BlockStatement
+func rewriteBlock(block *BlockStatement, fn RewriteFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	if newBlock, ok := Rewrite(block, fn).(*BlockStatement); ok && newBlock != nil {
+		return newBlock
+	}
+	return block
+}
+
+// rewriteIdentifier Rewrites an Identifier, keeping the original if the replacement is not an Identifier
+func rewriteIdentifier(id *Identifier, fn RewriteFunc) *Identifier {
+	if id == nil {
+		return nil
+	}
+	if newID, ok := Rewrite(id, fn).(*Identifier); ok && newID != nil {
+		return newID
+	}
+	return id
+}
@@ -0,0 +1,38 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// StringLiteral AST node for string values
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (str *StringLiteral) expressionNode() {}
+
+// TokenLiteral Returns the unquoted string value
+func (str *StringLiteral) TokenLiteral() string {
+	return str.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (str *StringLiteral) Pos() token.Position {
+	return str.Token.Position
+}
+
+// String Returns the quoted string value
+func (str *StringLiteral) String() string {
+	return strconv.Quote(str.Value)
+}
+
+// NewStringLiteral Creates a new StringLiteral and returns a reference to it
+func NewStringLiteral(tok token.Token) *StringLiteral {
+	return &StringLiteral{
+		Token: tok,
+		Value: tok.Literal,
+	}
+}
@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// ThrowStatement AST node for raising an error
+type ThrowStatement struct {
+	Token token.Token // the 'throw' token
+	Value Expression
+}
+
+func (throwStmt *ThrowStatement) statementNode() {}
+
+// TokenLiteral Retruns the node token literal
+func (throwStmt *ThrowStatement) TokenLiteral() string {
+	return throwStmt.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (throwStmt *ThrowStatement) Pos() token.Position {
+	return throwStmt.Token.Position
+}
+
+// String Returns a string representation of the ThrowStatement node
+func (throwStmt *ThrowStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(throwStmt.TokenLiteral() + " ")
+	if throwStmt.Value != nil {
+		out.WriteString(throwStmt.Value.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
+// NewThrowStatement Creates a new ThrowStatement and retruns a reference to it
+func NewThrowStatement(tok token.Token) *ThrowStatement {
+	return &ThrowStatement{
+		Token: tok,
+	}
+}
@@ -0,0 +1,52 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// TryExpression AST node for evaluating a block and handling the errors it raises
+type TryExpression struct {
+	Token      token.Token // the 'try' token
+	Body       *BlockStatement
+	CatchParam *Identifier // name the caught error is bound to in the catch block
+	Catch      *BlockStatement
+	Finally    *BlockStatement // optional, evaluated after the body and catch block
+}
+
+func (tryExpr *TryExpression) expressionNode() {}
+
+func (tryExpr *TryExpression) TokenLiteral() string {
+	return tryExpr.Token.Literal
+}
+
+// Pos Returns the position of the node in the source code
+func (tryExpr *TryExpression) Pos() token.Position {
+	return tryExpr.Token.Position
+}
+
+// String Returns the string represtation of a TryExpression
+func (tryExpr *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try { ")
+	out.WriteString(tryExpr.Body.String())
+	out.WriteString(" } catch (")
+	out.WriteString(tryExpr.CatchParam.String())
+	out.WriteString(") { ")
+	out.WriteString(tryExpr.Catch.String())
+	out.WriteString(" }")
+	if tryExpr.Finally != nil {
+		out.WriteString(" finally { ")
+		out.WriteString(tryExpr.Finally.String())
+		out.WriteString(" }")
+	}
+	return out.String()
+}
+
+// NewTryExpression Creates a new TryExpression and returns a reference to it
+func NewTryExpression(tok token.Token) *TryExpression {
+	return &TryExpression{
+		Token: tok,
+	}
+}
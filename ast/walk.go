@@ -0,0 +1,165 @@
+package ast
+
+// Visitor Interface for types that can be used to traverse an AST with Walk.
+// Visit is called for each node encountered, if the returned Visitor w is
+// not nil Walk visits each of the children of node with w, followed by
+// a call of w.Visit(nil)
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk Traverses an AST in depth-first order, starting with a call to
+// v.Visit(node) and then walking the children of node
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Statements)
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+	case *ExpressionStatement:
+		walkExpression(v, n.Expression)
+	case *LetStatement:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Pattern != nil {
+			Walk(v, n.Pattern)
+		}
+		walkExpression(v, n.Value)
+	case *FunctionStatement:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Function != nil {
+			Walk(v, n.Function)
+		}
+	case *ReturnStatement:
+		walkExpression(v, n.ReturnValue)
+	case *ThrowStatement:
+		walkExpression(v, n.Value)
+	case *PrefixExpression:
+		walkExpression(v, n.Right)
+	case *InfixExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Right)
+	case *IFExpression:
+		walkExpression(v, n.Condition)
+		if n.Consequence != nil {
+			Walk(v, n.Consequence)
+		}
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+	case *MatchExpression:
+		walkExpression(v, n.Subject)
+		for _, arm := range n.Arms {
+			walkExpression(v, arm.Pattern)
+			walkExpression(v, arm.Guard)
+			walkExpression(v, arm.Body)
+		}
+	case *TryExpression:
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+		if n.CatchParam != nil {
+			Walk(v, n.CatchParam)
+		}
+		if n.Catch != nil {
+			Walk(v, n.Catch)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+	case *FunctionLiteral:
+		for _, param := range n.Parameters {
+			Walk(v, param)
+		}
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+	case *MacroLiteral:
+		for _, param := range n.Parameters {
+			Walk(v, param)
+		}
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+	case *CallExpression:
+		walkExpression(v, n.Function)
+		for _, arg := range n.Arguments {
+			walkExpression(v, arg)
+		}
+	case *ArrayLiteral:
+		for _, element := range n.Elements {
+			walkExpression(v, element)
+		}
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			walkExpression(v, pair.Key)
+			walkExpression(v, pair.Value)
+		}
+	case *ArrayPattern:
+		for _, element := range n.Elements {
+			Walk(v, element)
+		}
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+	case *HashPattern:
+		for _, key := range n.Keys {
+			Walk(v, key)
+		}
+	case *IndexExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Index)
+	case *ImportExpression:
+		walkExpression(v, n.Path)
+	case *MemberExpression:
+		walkExpression(v, n.Object)
+		if n.Property != nil {
+			Walk(v, n.Property)
+		}
+	case *ExportStatement:
+		if n.Statement != nil {
+			Walk(v, n.Statement)
+		}
+	}
+	v.Visit(nil)
+}
+
+// walkStatements Walks each non nil statement in a list
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, stmt := range stmts {
+		if stmt != nil {
+			Walk(v, stmt)
+		}
+	}
+}
+
+// walkExpression Walks an expression if it is not nil
+func walkExpression(v Visitor, expr Expression) {
+	if expr != nil {
+		Walk(v, expr)
+	}
+}
+
+// inspector Adapter allowing a plain function to be used as a Visitor
+type inspector func(Node) bool
+
+// Visit Calls the inspector function and continues the walk if it returned true
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect Traverses an AST in depth-first order calling f(node) for each node.
+// If f returns true Inspect is called recursively on the children of node
+// followed by a call of f(nil)
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
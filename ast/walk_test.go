@@ -0,0 +1,217 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+func TestInspect(t *testing.T) {
+	program := getTestWalkProgram()
+	visited := make([]string, 0)
+	Inspect(program, func(node Node) bool {
+		if node != nil {
+			visited = append(visited, node.String())
+		}
+		return true
+	})
+	expected := []string{
+		"let f = fn(x) if x x else 1;f(2)",
+		"let f = fn(x) if x x else 1;",
+		"f",
+		"fn(x) if x x else 1",
+		"x",
+		"if x x else 1",
+		"if x x else 1",
+		"if x x else 1",
+		"x",
+		"x",
+		"x",
+		"x",
+		"1",
+		"1",
+		"1",
+		"f(2)",
+		"f(2)",
+		"f",
+		"2",
+	}
+	if len(visited) != len(expected) {
+		t.Fatalf("Wrong number of visited nodes Expected=%d Got=%d - %v",
+			len(expected), len(visited), visited)
+	}
+	for i, str := range expected {
+		if visited[i] != str {
+			t.Errorf("%d - Wrong node visited Expected=[ %s ] Got=[ %s ]", i, str, visited[i])
+		}
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	program := getTestWalkProgram()
+	count := 0
+	Inspect(program, func(node Node) bool {
+		if node == nil {
+			return false
+		}
+		count++
+		_, isFn := node.(*FunctionLiteral)
+		return !isFn
+	})
+	expectedCount := 8
+	if count != expectedCount {
+		t.Fatalf("Wrong number of visited nodes Expected=%d Got=%d", expectedCount, count)
+	}
+}
+
+type countingVisitor struct {
+	enter int
+	leave int
+}
+
+func (v *countingVisitor) Visit(node Node) Visitor {
+	if node == nil {
+		v.leave++
+	} else {
+		v.enter++
+	}
+	return v
+}
+
+func TestWalk(t *testing.T) {
+	v := &countingVisitor{}
+	Walk(v, getTestWalkProgram())
+	if v.enter != 19 {
+		t.Errorf("Wrong number of entered nodes Expected=19 Got=%d", v.enter)
+	}
+	if v.enter != v.leave {
+		t.Errorf("Unbalanced walk enter=%d leave=%d", v.enter, v.leave)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	program := getTestWalkProgram()
+	rewritten := Rewrite(program, func(node Node) Node {
+		id, ok := node.(*Identifier)
+		if ok && id.Value == "x" {
+			return NewIdentifier(token.New(token.IDENT, "y"), "y")
+		}
+		intLit, ok := node.(*IntegerLiteral)
+		if ok && intLit.Value == 2 {
+			newLit, _ := NewIntegerLiteral(token.New(token.INT, "3"))
+			return newLit
+		}
+		return node
+	})
+	expectedStr := "let f = fn(y) if y y else 1;f(3)"
+	if rewritten.String() != expectedStr {
+		t.Fatalf("Rewrite wrong Expected=[ %s ] Got=[ %s ]", expectedStr, rewritten.String())
+	}
+}
+
+func TestRewriteRemovesStatements(t *testing.T) {
+	program := getTestWalkProgram()
+	rewritten := Rewrite(program, func(node Node) Node {
+		if _, ok := node.(*LetStatement); ok {
+			return nil
+		}
+		return node
+	})
+	expectedStr := "f(2)"
+	if rewritten.String() != expectedStr {
+		t.Fatalf("Rewrite wrong Expected=[ %s ] Got=[ %s ]", expectedStr, rewritten.String())
+	}
+}
+
+func TestRewriteIgnoresMisplacedNodes(t *testing.T) {
+	program := getTestWalkProgram()
+	rewritten := Rewrite(program, func(node Node) Node {
+		if _, ok := node.(*IntegerLiteral); ok {
+			return NewReturnStatement(token.New(token.RETRUN, "return"))
+		}
+		return node
+	})
+	expectedStr := "let f = fn(x) if x x else 1;f(2)"
+	if rewritten.String() != expectedStr {
+		t.Fatalf("Rewrite wrong Expected=[ %s ] Got=[ %s ]", expectedStr, rewritten.String())
+	}
+}
+
+func TestCopy(t *testing.T) {
+	program := getTestWalkProgram()
+	copied := Copy(program)
+	original := make(map[Node]bool)
+	Inspect(program, func(node Node) bool {
+		if node != nil {
+			original[node] = true
+		}
+		return true
+	})
+	shared := 0
+	Inspect(copied, func(node Node) bool {
+		if original[node] {
+			shared++
+		}
+		return true
+	})
+	if shared != 0 {
+		t.Errorf("Copy shares %d nodes with the original", shared)
+	}
+	Rewrite(copied, func(node Node) Node {
+		if id, ok := node.(*Identifier); ok && id.Value == "x" {
+			return NewIdentifier(token.New(token.IDENT, "y"), "y")
+		}
+		return node
+	})
+	if program.String() != "let f = fn(x) if x x else 1;f(2)" {
+		t.Errorf("Rewriting the copy modified the original Got=[ %s ]", program.String())
+	}
+	if copied.String() != "let f = fn(y) if y y else 1;f(2)" {
+		t.Errorf("Wrong rewritten copy Got=[ %s ]", copied.String())
+	}
+}
+
+// getTestWalkProgram Builds the AST for: let f = fn(x) { if (x) { x } else { 1 } }; f(2);
+func getTestWalkProgram() *Program {
+	x := func() *Identifier {
+		return NewIdentifier(token.New(token.IDENT, "x"), "x")
+	}
+	one, _ := NewIntegerLiteral(token.New(token.INT, "1"))
+	two, _ := NewIntegerLiteral(token.New(token.INT, "2"))
+
+	consequence := NewBlockStatement(token.New(token.LBRACE, "{"))
+	consequenceStmt := NewExpressionStatement(token.New(token.IDENT, "x"))
+	consequenceStmt.Expression = x()
+	consequence.AddStatements(consequenceStmt)
+	alternative := NewBlockStatement(token.New(token.LBRACE, "{"))
+	alternativeStmt := NewExpressionStatement(token.New(token.INT, "1"))
+	alternativeStmt.Expression = one
+	alternative.AddStatements(alternativeStmt)
+
+	ifExpr := NewIFExpression(token.New(token.IF, "if"))
+	ifExpr.Condition = x()
+	ifExpr.Consequence = consequence
+	ifExpr.Alternative = alternative
+	body := NewBlockStatement(token.New(token.LBRACE, "{"))
+	ifStmt := NewExpressionStatement(token.New(token.IF, "if"))
+	ifStmt.Expression = ifExpr
+	body.AddStatements(ifStmt)
+
+	fn := NewFunctionLiteral(token.New(token.FUNCTION, "fn"))
+	fn.AddParam(x())
+	fn.Body = body
+	letStmt := NewLetStatement(token.New(token.LET, "let"))
+	letStmt.Name = NewIdentifier(token.New(token.IDENT, "f"), "f")
+	letStmt.Value = fn
+
+	call := NewCallExpression(
+		token.New(token.LPAREN, "("),
+		NewIdentifier(token.New(token.IDENT, "f"), "f"))
+	call.Arguments = []Expression{two}
+	callStmt := NewExpressionStatement(token.New(token.IDENT, "f"))
+	callStmt.Expression = call
+
+	program := NewProgram()
+	program.AddStatements(letStmt, callStmt)
+	return program
+}
@@ -0,0 +1,188 @@
+package coverage
+
+import (
+	"sort"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// Statement Number of times a statement was evaluated
+type Statement struct {
+	Position token.Position
+	Count    int
+}
+
+// Branch Number of times each arm of an IFExpression was taken, the
+// alternative is counted even if the expression has none
+type Branch struct {
+	Position    token.Position
+	Consequence int
+	Alternative int
+}
+
+// Evaluated Checks if the IFExpression of the Branch was evaluated
+func (branch *Branch) Evaluated() bool {
+	return branch.Consequence+branch.Alternative > 0
+}
+
+// File Coverage of the statements and branches of a source file
+type File struct {
+	Name       string
+	Statements []*Statement // in source order
+	Branches   []*Branch    // in source order
+}
+
+// StatementsCovered Returns the number of statements evaluated and the total number of statements
+func (file *File) StatementsCovered() (int, int) {
+	covered := 0
+	for _, stmt := range file.Statements {
+		if stmt.Count > 0 {
+			covered++
+		}
+	}
+	return covered, len(file.Statements)
+}
+
+// BranchesCovered Returns the number of branch arms taken and the total number of arms
+func (file *File) BranchesCovered() (int, int) {
+	covered := 0
+	for _, branch := range file.Branches {
+		if branch.Consequence > 0 {
+			covered++
+		}
+		if branch.Alternative > 0 {
+			covered++
+		}
+	}
+	return covered, 2 * len(file.Branches)
+}
+
+// Coverage evaluator.Hook counting the statements evaluated and the branches
+// taken. Programs are measured from the first time they are evaluated, which
+// includes imported modules
+type Coverage struct {
+	Exclude    func(filename string) bool // files that are not measured, nil measures every file
+	files      map[string]*File
+	names      []string // in the order the files were first evaluated
+	programs   map[*ast.Program]bool
+	statements map[ast.Statement]*Statement
+	branches   map[*ast.IFExpression]*Branch
+}
+
+// New Creates a Coverage measuring every file
+func New() *Coverage {
+	return &Coverage{
+		files:      make(map[string]*File),
+		names:      make([]string, 0),
+		programs:   make(map[*ast.Program]bool),
+		statements: make(map[ast.Statement]*Statement),
+		branches:   make(map[*ast.IFExpression]*Branch),
+	}
+}
+
+// Run Evaluates a program in an environment while measuring its coverage
+func (coverage *Coverage) Run(program ast.Node, env *object.Environment) object.Object {
+	defer evaluator.SetHook(evaluator.SetHook(coverage))
+	return evaluator.Eval(program, env)
+}
+
+// Add Registers the statements and branches of a program read from filename,
+// programs are also registered when they are first evaluated
+func (coverage *Coverage) Add(filename string, program *ast.Program) {
+	if coverage.programs[program] {
+		return
+	}
+	coverage.programs[program] = true
+	if coverage.Exclude != nil && coverage.Exclude(filename) {
+		return
+	}
+	file, ok := coverage.files[filename]
+	if !ok {
+		file = &File{Name: filename, Statements: make([]*Statement, 0), Branches: make([]*Branch, 0)}
+		coverage.files[filename] = file
+		coverage.names = append(coverage.names, filename)
+	}
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Program:
+			coverage.addStatements(file, node.Statements)
+		case *ast.BlockStatement:
+			coverage.addStatements(file, node.Statements)
+		case *ast.IFExpression:
+			branch := &Branch{Position: node.Pos()}
+			coverage.branches[node] = branch
+			file.Branches = append(file.Branches, branch)
+		}
+		return true
+	})
+	sort.SliceStable(file.Statements, func(i, j int) bool {
+		return before(file.Statements[i].Position, file.Statements[j].Position)
+	})
+	sort.SliceStable(file.Branches, func(i, j int) bool {
+		return before(file.Branches[i].Position, file.Branches[j].Position)
+	})
+}
+
+// addStatements Registers the statements of a Program or BlockStatement,
+// which are the statements the evaluator notifies its hook of
+func (coverage *Coverage) addStatements(file *File, stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if stmt == nil {
+			continue
+		}
+		statement := &Statement{Position: stmt.Pos()}
+		coverage.statements[stmt] = statement
+		file.Statements = append(file.Statements, statement)
+	}
+}
+
+// Files Returns the coverage of each measured file in the order they were first evaluated
+func (coverage *Coverage) Files() []*File {
+	files := make([]*File, 0, len(coverage.names))
+	for _, name := range coverage.names {
+		files = append(files, coverage.files[name])
+	}
+	return files
+}
+
+// BeforeNode Registers programs the first time they are evaluated
+func (coverage *Coverage) BeforeNode(node ast.Node) {
+	if program, ok := node.(*ast.Program); ok && !coverage.programs[program] {
+		coverage.Add(program.Pos().Filename, program)
+	}
+}
+
+// BeforeStatement Counts an evaluated statement
+func (coverage *Coverage) BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	if statement, ok := coverage.statements[stmt]; ok {
+		statement.Count++
+	}
+	return nil
+}
+
+// Branch Counts the arm taken by an IFExpression
+func (coverage *Coverage) Branch(ifExpr *ast.IFExpression, consequence bool) {
+	branch, ok := coverage.branches[ifExpr]
+	if !ok {
+		return
+	}
+	if consequence {
+		branch.Consequence++
+	} else {
+		branch.Alternative++
+	}
+}
+
+// BeforeCall Does nothing, calls are measured by the statements of the function
+func (coverage *Coverage) BeforeCall(fn object.Object, args []object.Object, pos token.Position) {}
+
+// AfterCall Does nothing, calls are measured by the statements of the function
+func (coverage *Coverage) AfterCall(fn object.Object, result object.Object) {}
+
+// before Checks if position a comes before position b in a file
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
@@ -0,0 +1,116 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+const testSource = `let sign = fn(x) {
+  if (x < 0) {
+    return -1;
+  }
+  if (x == 0) { 0 } else { 1 }
+};
+let unused = fn() { if (true) { 1 } };
+sign(2);
+sign(-2);`
+
+// parse Parses source read from filename
+func parse(t *testing.T, source, filename string) *ast.Program {
+	p := parser.New(lexer.NewWithFilename(source, filename))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors: %v", p.Errors())
+	}
+	return program
+}
+
+func TestCoverage(t *testing.T) {
+	coverage := New()
+	result := coverage.Run(parse(t, testSource, "sign.monkey"), object.NewEnvironment())
+	if result.Inspect() != "-1" {
+		t.Fatalf("Wrong result Expected=-1 Got=%s", result.Inspect())
+	}
+	files := coverage.Files()
+	if len(files) != 1 || files[0].Name != "sign.monkey" {
+		t.Fatalf("Expected sign.monkey to be measured Got=%+v", files)
+	}
+	counts := make([]string, 0)
+	for _, stmt := range files[0].Statements {
+		counts = append(counts, fmt.Sprintf("%d:%d=%d", stmt.Position.Line, stmt.Position.Column, stmt.Count))
+	}
+	expected := "1:1=1 2:3=2 3:5=1 5:3=1 5:17=0 5:28=1 7:1=1 7:21=0 7:33=0 8:1=1 9:1=1"
+	if strings.Join(counts, " ") != expected {
+		t.Errorf("Wrong statement counts Expected=%q Got=%q", expected, strings.Join(counts, " "))
+	}
+	expectedBranches := []Branch{{Consequence: 1, Alternative: 1}, {Consequence: 0, Alternative: 1}, {}}
+	for i, branch := range files[0].Branches {
+		if branch.Consequence != expectedBranches[i].Consequence || branch.Alternative != expectedBranches[i].Alternative {
+			t.Errorf("%d - Wrong branch counts Expected=%+v Got=%+v", i, expectedBranches[i], branch)
+		}
+	}
+	if covered, total := files[0].StatementsCovered(); covered != 8 || total != 11 {
+		t.Errorf("Wrong statement coverage Expected=8/11 Got=%d/%d", covered, total)
+	}
+	if covered, total := files[0].BranchesCovered(); covered != 3 || total != 6 {
+		t.Errorf("Wrong branch coverage Expected=3/6 Got=%d/%d", covered, total)
+	}
+}
+
+func TestImportedModules(t *testing.T) {
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.monkey")
+	os.WriteFile(lib, []byte("export let abs = fn(x) { if (x < 0) { -x } else { x } };\n"), 0600)
+	main := filepath.Join(dir, "main_test.monkey")
+	coverage := New()
+	coverage.Exclude = func(filename string) bool {
+		return strings.HasSuffix(filename, "_test.monkey")
+	}
+	program := parse(t, "let lib = import(\"lib.monkey\");\nlib.abs(-1)", main)
+	if result := coverage.Run(program, object.NewEnvironment()); result.Inspect() != "1" {
+		t.Fatalf("Wrong result Expected=1 Got=%s", result.Inspect())
+	}
+	files := coverage.Files()
+	if len(files) != 1 || files[0].Name != lib {
+		t.Fatalf("Expected only %s to be measured Got=%+v", lib, files)
+	}
+	if covered, total := files[0].BranchesCovered(); covered != 1 || total != 2 {
+		t.Errorf("Wrong branch coverage Expected=1/2 Got=%d/%d", covered, total)
+	}
+}
+
+func TestReports(t *testing.T) {
+	coverage := New()
+	coverage.Run(parse(t, testSource, "sign.monkey"), object.NewEnvironment())
+	coverage.Add("empty.monkey", parse(t, "", "empty.monkey"))
+
+	var summary bytes.Buffer
+	coverage.WriteSummary(&summary)
+	expectedSummary := "sign.monkey: statements 72.7% (8/11), branches 50.0% (3/6)\n" +
+		"empty.monkey: statements - (0/0), branches - (0/0)\n" +
+		"total: statements 72.7% (8/11), branches 50.0% (3/6)\n"
+	if summary.String() != expectedSummary {
+		t.Errorf("Wrong summary Expected=%q Got=%q", expectedSummary, summary.String())
+	}
+
+	var lcov bytes.Buffer
+	if err := coverage.WriteLCOV(&lcov, "unit"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expectedLCOV := "TN:unit\nSF:sign.monkey\n" +
+		"BRDA:2,0,0,1\nBRDA:2,0,1,1\nBRDA:5,1,0,0\nBRDA:5,1,1,1\nBRDA:7,2,0,-\nBRDA:7,2,1,-\nBRF:6\nBRH:3\n" +
+		"DA:1,1\nDA:2,2\nDA:3,1\nDA:5,1\nDA:7,1\nDA:8,1\nDA:9,1\nLF:7\nLH:7\nend_of_record\n" +
+		"TN:unit\nSF:empty.monkey\nBRF:0\nBRH:0\nLF:0\nLH:0\nend_of_record\n"
+	if lcov.String() != expectedLCOV {
+		t.Errorf("Wrong LCOV Expected=%q Got=%q", expectedLCOV, lcov.String())
+	}
+}
@@ -0,0 +1,78 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteSummary Writes the statement and branch coverage of each file followed by the total
+func (coverage *Coverage) WriteSummary(w io.Writer) {
+	total := &File{Name: "total"}
+	for _, file := range coverage.Files() {
+		writeFileSummary(w, file)
+		total.Statements = append(total.Statements, file.Statements...)
+		total.Branches = append(total.Branches, file.Branches...)
+	}
+	writeFileSummary(w, total)
+}
+
+// writeFileSummary Writes the statement and branch coverage of a file on a line
+func writeFileSummary(w io.Writer, file *File) {
+	statementsCovered, statements := file.StatementsCovered()
+	branchesCovered, branches := file.BranchesCovered()
+	fmt.Fprintf(w, "%s: statements %s (%d/%d), branches %s (%d/%d)\n", file.Name,
+		percentage(statementsCovered, statements), statementsCovered, statements,
+		percentage(branchesCovered, branches), branchesCovered, branches)
+}
+
+// WriteLCOV Writes the coverage of each file as an LCOV tracefile record for
+// the named test. Lines are reported with the count of the statement
+// evaluated the most times on them, each arm of an IFExpression is a branch
+func (coverage *Coverage) WriteLCOV(w io.Writer, testName string) error {
+	for _, file := range coverage.Files() {
+		fmt.Fprintf(w, "TN:%s\nSF:%s\n", testName, file.Name)
+		branchesCovered, branches := file.BranchesCovered()
+		for block, branch := range file.Branches {
+			consequence, alternative := "-", "-"
+			if branch.Evaluated() {
+				consequence, alternative = strconv.Itoa(branch.Consequence), strconv.Itoa(branch.Alternative)
+			}
+			fmt.Fprintf(w, "BRDA:%d,%d,0,%s\n", branch.Position.Line, block, consequence)
+			fmt.Fprintf(w, "BRDA:%d,%d,1,%s\n", branch.Position.Line, block, alternative)
+		}
+		fmt.Fprintf(w, "BRF:%d\nBRH:%d\n", branches, branchesCovered)
+		lines, linesHit := 0, 0
+		for i, stmt := range file.Statements {
+			if i > 0 && file.Statements[i-1].Position.Line == stmt.Position.Line {
+				continue
+			}
+			count := stmt.Count
+			for _, other := range file.Statements[i+1:] {
+				if other.Position.Line != stmt.Position.Line {
+					break
+				}
+				if other.Count > count {
+					count = other.Count
+				}
+			}
+			lines++
+			if count > 0 {
+				linesHit++
+			}
+			fmt.Fprintf(w, "DA:%d,%d\n", stmt.Position.Line, count)
+		}
+		if _, err := fmt.Fprintf(w, "LF:%d\nLH:%d\nend_of_record\n", lines, linesHit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// percentage Formats the share of covered items, - if there are none
+func percentage(covered, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(covered)/float64(total))
+}
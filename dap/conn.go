@@ -0,0 +1,103 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// Conn Connection exchanging debug adapter protocol messages framed
+// by a Content-Length header
+type Conn struct {
+	reader *bufio.Reader
+	writer io.Writer
+	mutex  sync.Mutex // serializes writes and guards seq
+	seq    int        // sequence number of the last message written
+}
+
+// NewConn Creates a connection reading messages from in and writing them to out
+func NewConn(in io.Reader, out io.Writer) *Conn {
+	return &Conn{
+		reader: bufio.NewReader(in),
+		writer: out,
+	}
+}
+
+// Read Reads the next message, io.EOF is returned when the input is closed
+func (conn *Conn) Read() (*Message, error) {
+	headers, err := textproto.NewReader(conn.reader).ReadMIMEHeader()
+	if err != nil {
+		if len(headers) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("Invalid Content-Length: %q", headers.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(conn.reader, body); err != nil {
+		return nil, err
+	}
+	msg := &Message{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, fmt.Errorf("Invalid message: %s", err)
+	}
+	return msg, nil
+}
+
+// write Numbers a message and writes it preceded by its Content-Length header
+func (conn *Conn) write(msg sequenced) error {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.seq++
+	msg.setSeq(conn.seq)
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn.writer, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = conn.writer.Write(body)
+	return err
+}
+
+// Request Sends a request with the supplied arguments, returns its sequence number
+func (conn *Conn) Request(command string, arguments interface{}) (int, error) {
+	req := &request{Type: REQUEST, Command: command, Arguments: arguments}
+	err := conn.write(req)
+	return req.Seq, err
+}
+
+// Respond Sends a successful response to a request
+func (conn *Conn) Respond(req *Message, body interface{}) error {
+	return conn.write(&response{
+		Type:       RESPONSE,
+		RequestSeq: req.Seq,
+		Success:    true,
+		Command:    req.Command,
+		Body:       body,
+	})
+}
+
+// RespondError Sends a failed response to a request
+func (conn *Conn) RespondError(req *Message, message string) error {
+	return conn.write(&response{
+		Type:       RESPONSE,
+		RequestSeq: req.Seq,
+		Success:    false,
+		Command:    req.Command,
+		Message:    message,
+	})
+}
+
+// Event Sends an event
+func (conn *Conn) Event(event string, body interface{}) error {
+	return conn.write(&eventMessage{Type: EVENT, Event: event, Body: body})
+}
@@ -0,0 +1,217 @@
+package dap
+
+import "encoding/json"
+
+// Message types
+const (
+	REQUEST  = "request"
+	RESPONSE = "response"
+	EVENT    = "event"
+)
+
+// THREAD_ID Id of the only thread a monkey program runs in
+const THREAD_ID = 1
+
+// Message Request, response or event read from a connection, the
+// fields that do not belong to its type are left empty
+type Message struct {
+	Seq        int             `json:"seq"`
+	Type       string          `json:"type"`
+	Command    string          `json:"command,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	RequestSeq int             `json:"request_seq,omitempty"`
+	Success    bool            `json:"success,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Event      string          `json:"event,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// sequenced Message written to a connection, numbered when written
+type sequenced interface {
+	setSeq(seq int)
+}
+
+// request Message asking the other end to run a command
+type request struct {
+	Seq       int         `json:"seq"`
+	Type      string      `json:"type"`
+	Command   string      `json:"command"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+func (req *request) setSeq(seq int) {
+	req.Seq = seq
+}
+
+// response Result of a request
+type response struct {
+	Seq        int         `json:"seq"`
+	Type       string      `json:"type"`
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+func (resp *response) setSeq(seq int) {
+	resp.Seq = seq
+}
+
+// eventMessage Notification sent by the debug adapter
+type eventMessage struct {
+	Seq   int         `json:"seq"`
+	Type  string      `json:"type"`
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+func (event *eventMessage) setSeq(seq int) {
+	event.Seq = seq
+}
+
+// Capabilities Features supported by the debug adapter
+type Capabilities struct {
+	SupportsConfigurationDoneRequest bool `json:"supportsConfigurationDoneRequest"`
+	SupportsEvaluateForHovers        bool `json:"supportsEvaluateForHovers"`
+	SupportsTerminateRequest         bool `json:"supportsTerminateRequest"`
+}
+
+// LaunchArguments Arguments of the launch request
+type LaunchArguments struct {
+	Program     string `json:"program"`
+	StopOnEntry bool   `json:"stopOnEntry"`
+}
+
+// Source Reference to a source file
+type Source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// SourceBreakpoint Breakpoint requested by the client
+type SourceBreakpoint struct {
+	Line int `json:"line"`
+}
+
+// SetBreakpointsArguments Arguments of the setBreakpoints request, replacing
+// every breakpoint of a source
+type SetBreakpointsArguments struct {
+	Source      Source             `json:"source"`
+	Breakpoints []SourceBreakpoint `json:"breakpoints"`
+}
+
+// Breakpoint Breakpoint set by the debug adapter
+type Breakpoint struct {
+	Verified bool    `json:"verified"`
+	Line     int     `json:"line"`
+	Source   *Source `json:"source,omitempty"`
+}
+
+// SetBreakpointsResponseBody Body of the response to setBreakpoints
+type SetBreakpointsResponseBody struct {
+	Breakpoints []Breakpoint `json:"breakpoints"`
+}
+
+// Thread Thread of the debugged program
+type Thread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ThreadsResponseBody Body of the response to threads
+type ThreadsResponseBody struct {
+	Threads []Thread `json:"threads"`
+}
+
+// StackTraceArguments Arguments of the stackTrace request
+type StackTraceArguments struct {
+	ThreadID int `json:"threadId"`
+}
+
+// StackFrame Function being executed by the paused program
+type StackFrame struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Source *Source `json:"source,omitempty"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+}
+
+// StackTraceResponseBody Body of the response to stackTrace
+type StackTraceResponseBody struct {
+	StackFrames []StackFrame `json:"stackFrames"`
+	TotalFrames int          `json:"totalFrames"`
+}
+
+// ScopesArguments Arguments of the scopes request
+type ScopesArguments struct {
+	FrameID int `json:"frameId"`
+}
+
+// Scope Named container of variables, one for each environment of a frame
+type Scope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+// ScopesResponseBody Body of the response to scopes
+type ScopesResponseBody struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// VariablesArguments Arguments of the variables request
+type VariablesArguments struct {
+	VariablesReference int `json:"variablesReference"`
+}
+
+// Variable Named value, expandable if its variablesReference is not 0
+type Variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// VariablesResponseBody Body of the response to variables
+type VariablesResponseBody struct {
+	Variables []Variable `json:"variables"`
+}
+
+// EvaluateArguments Arguments of the evaluate request
+type EvaluateArguments struct {
+	Expression string `json:"expression"`
+	FrameID    int    `json:"frameId"`
+	Context    string `json:"context"`
+}
+
+// EvaluateResponseBody Body of the response to evaluate
+type EvaluateResponseBody struct {
+	Result             string `json:"result"`
+	Type               string `json:"type"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// ContinueResponseBody Body of the response to continue
+type ContinueResponseBody struct {
+	AllThreadsContinued bool `json:"allThreadsContinued"`
+}
+
+// StoppedEventBody Body of the stopped event
+type StoppedEventBody struct {
+	Reason            string `json:"reason"`
+	ThreadID          int    `json:"threadId"`
+	AllThreadsStopped bool   `json:"allThreadsStopped"`
+}
+
+// OutputEventBody Body of the output event
+type OutputEventBody struct {
+	Category string `json:"category"`
+	Output   string `json:"output"`
+}
+
+// ExitedEventBody Body of the exited event
+type ExitedEventBody struct {
+	ExitCode int `json:"exitCode"`
+}
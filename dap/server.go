@@ -0,0 +1,465 @@
+package dap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/debugger"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+// errNotPaused Returned by requests that inspect or resume a paused program
+var errNotPaused = errors.New("Program is not paused")
+
+// handler Handles the arguments of a request and returns the body of its response
+type handler func(server *Server, args json.RawMessage) (interface{}, error)
+
+// handlers Requests supported by the server
+var handlers = map[string]handler{
+	"initialize":        (*Server).initialize,
+	"launch":            (*Server).launch,
+	"setBreakpoints":    (*Server).setBreakpoints,
+	"configurationDone": (*Server).ignore,
+	"threads":           (*Server).threads,
+	"stackTrace":        (*Server).stackTrace,
+	"scopes":            (*Server).scopes,
+	"variables":         (*Server).variables,
+	"evaluate":          (*Server).evaluate,
+	"continue":          resume(debugger.CONTINUE),
+	"next":              resume(debugger.STEP_OVER),
+	"stepIn":            resume(debugger.STEP_IN),
+	"stepOut":           resume(debugger.STEP_OUT),
+	"pause":             (*Server).pause,
+	"terminate":         (*Server).terminate,
+	"disconnect":        (*Server).terminate,
+}
+
+// Server Debug adapter running a monkey program under a debugger.Debugger.
+// The program is evaluated in a goroutine of its own which hands its pauses
+// to the goroutine serving requests and waits for the action resuming it
+type Server struct {
+	conn        *Conn
+	debugger    *debugger.Debugger
+	program     ast.Node
+	path        string
+	stopOnEntry bool
+	breakpoints map[string][]int // lines of the breakpoints set for each file
+	configured  bool
+	running     bool // the program has been started and has not finished
+	paused      bool
+	handles     handles
+	pauses      chan debugger.Stop
+	actions     chan debugger.Action
+	done        chan object.Object
+	output      io.Writer // evaluator.Output replaced while the program runs
+}
+
+// NewServer Creates a server reading messages from in and writing them to out
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		conn:        NewConn(in, out),
+		breakpoints: make(map[string][]int),
+		pauses:      make(chan debugger.Stop),
+		actions:     make(chan debugger.Action),
+		done:        make(chan object.Object),
+	}
+}
+
+// Serve Handles requests until the client disconnects or closes the input,
+// a program still running at that point is stopped
+func (server *Server) Serve() error {
+	messages := make(chan *Message)
+	readErr := make(chan error, 1)
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		for {
+			msg, err := server.conn.Read()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-closed:
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case msg := <-messages:
+			if server.handle(msg) {
+				return nil
+			}
+		case stop := <-server.pauses:
+			server.stopped(stop)
+		case result := <-server.done:
+			server.finished(result)
+		case err := <-readErr:
+			server.stopProgram()
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Paused Hands a pause of the program to the serving goroutine and waits
+// for the action resuming it, called from the goroutine evaluating the program
+func (server *Server) Paused(d *debugger.Debugger, stop debugger.Stop) debugger.Action {
+	server.pauses <- stop
+	return <-server.actions
+}
+
+// handle Dispatches a request to its handler and responds to it, returns
+// true if the client disconnected
+func (server *Server) handle(msg *Message) bool {
+	if msg.Type != REQUEST {
+		return false
+	}
+	fn, ok := handlers[msg.Command]
+	if !ok {
+		server.conn.RespondError(msg, "Unknown command: "+msg.Command)
+		return false
+	}
+	body, err := fn(server, msg.Arguments)
+	if err != nil {
+		server.conn.RespondError(msg, err.Error())
+		return false
+	}
+	server.conn.Respond(msg, body)
+	switch msg.Command {
+	case "initialize":
+		server.conn.Event("initialized", nil)
+	case "configurationDone":
+		server.configured = true
+		server.start()
+	case "launch":
+		server.start()
+	case "disconnect":
+		return true
+	}
+	return false
+}
+
+// initialize Returns the capabilities of the server
+func (server *Server) initialize(args json.RawMessage) (interface{}, error) {
+	return Capabilities{
+		SupportsConfigurationDoneRequest: true,
+		SupportsEvaluateForHovers:        true,
+		SupportsTerminateRequest:         true,
+	}, nil
+}
+
+// ignore Responds to a request without doing anything
+func (server *Server) ignore(args json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+// launch Loads the program to debug, which is started once the client is configured
+func (server *Server) launch(args json.RawMessage) (interface{}, error) {
+	var launchArgs LaunchArguments
+	if err := unmarshalArgs(args, &launchArgs); err != nil {
+		return nil, err
+	}
+	if server.program != nil {
+		return nil, errors.New("A program has already been launched")
+	}
+	path, err := filepath.Abs(launchArgs.Program)
+	if err != nil {
+		return nil, err
+	}
+	program, err := loadProgram(path)
+	if err != nil {
+		return nil, err
+	}
+	server.program, server.path, server.stopOnEntry = program, path, launchArgs.StopOnEntry
+	return nil, nil
+}
+
+// setBreakpoints Replaces the breakpoints of a source file
+func (server *Server) setBreakpoints(args json.RawMessage) (interface{}, error) {
+	var breakpointArgs SetBreakpointsArguments
+	if err := unmarshalArgs(args, &breakpointArgs); err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(breakpointArgs.Source.Path)
+	if err != nil {
+		return nil, err
+	}
+	if server.debugger != nil {
+		for _, line := range server.breakpoints[path] {
+			server.debugger.ClearBreakpoint(debugger.Breakpoint{Filename: path, Line: line})
+		}
+	}
+	lines := make([]int, 0, len(breakpointArgs.Breakpoints))
+	breakpoints := make([]Breakpoint, 0, len(breakpointArgs.Breakpoints))
+	for _, breakpoint := range breakpointArgs.Breakpoints {
+		lines = append(lines, breakpoint.Line)
+		breakpoints = append(breakpoints, Breakpoint{
+			Verified: true,
+			Line:     breakpoint.Line,
+			Source:   &Source{Name: filepath.Base(path), Path: path},
+		})
+		if server.debugger != nil {
+			server.debugger.SetBreakpoint(debugger.Breakpoint{Filename: path, Line: breakpoint.Line})
+		}
+	}
+	server.breakpoints[path] = lines
+	return SetBreakpointsResponseBody{Breakpoints: breakpoints}, nil
+}
+
+// threads Returns the single thread programs run in
+func (server *Server) threads(args json.RawMessage) (interface{}, error) {
+	return ThreadsResponseBody{Threads: []Thread{{ID: THREAD_ID, Name: "main"}}}, nil
+}
+
+// stackTrace Returns the frames of the paused program, innermost first
+func (server *Server) stackTrace(args json.RawMessage) (interface{}, error) {
+	if !server.paused {
+		return nil, errNotPaused
+	}
+	frames := server.debugger.Frames()
+	stackFrames := make([]StackFrame, 0, len(frames))
+	for i, frame := range frames {
+		stackFrame := StackFrame{ID: i + 1, Name: frame.Function}
+		if frame.Position.IsValid() {
+			stackFrame.Source = &Source{Name: filepath.Base(frame.Position.Filename), Path: frame.Position.Filename}
+			stackFrame.Line, stackFrame.Column = frame.Position.Line, frame.Position.Column
+		}
+		stackFrames = append(stackFrames, stackFrame)
+	}
+	return StackTraceResponseBody{StackFrames: stackFrames, TotalFrames: len(stackFrames)}, nil
+}
+
+// scopes Returns a scope for each environment of a frame
+func (server *Server) scopes(args json.RawMessage) (interface{}, error) {
+	var scopesArgs ScopesArguments
+	if err := unmarshalArgs(args, &scopesArgs); err != nil {
+		return nil, err
+	}
+	frame, err := server.frame(scopesArgs.FrameID)
+	if err != nil {
+		return nil, err
+	}
+	return ScopesResponseBody{Scopes: server.handles.scopes(frame.Env)}, nil
+}
+
+// variables Returns the variables of a scope or an expandable variable
+func (server *Server) variables(args json.RawMessage) (interface{}, error) {
+	var variablesArgs VariablesArguments
+	if err := unmarshalArgs(args, &variablesArgs); err != nil {
+		return nil, err
+	}
+	if !server.paused {
+		return nil, errNotPaused
+	}
+	value, ok := server.handles.get(variablesArgs.VariablesReference)
+	if !ok {
+		return nil, fmt.Errorf("Invalid variablesReference: %d", variablesArgs.VariablesReference)
+	}
+	return VariablesResponseBody{Variables: server.handles.variables(value)}, nil
+}
+
+// evaluate Evaluates an expression in the environment of a frame,
+// the innermost one if no frame is given
+func (server *Server) evaluate(args json.RawMessage) (interface{}, error) {
+	var evaluateArgs EvaluateArguments
+	if err := unmarshalArgs(args, &evaluateArgs); err != nil {
+		return nil, err
+	}
+	if !server.paused {
+		return nil, errNotPaused
+	}
+	frame := 0
+	if evaluateArgs.FrameID != 0 {
+		frame = evaluateArgs.FrameID - 1
+	}
+	result := server.debugger.Evaluate(evaluateArgs.Expression, frame)
+	switch result := result.(type) {
+	case nil:
+		return EvaluateResponseBody{Result: ""}, nil
+	case *object.Error:
+		return nil, fmt.Errorf("%s: %s", result.Kind, result.Message)
+	default:
+		variable := server.handles.variable("", result)
+		return EvaluateResponseBody{
+			Result:             variable.Value,
+			Type:               variable.Type,
+			VariablesReference: variable.VariablesReference,
+		}, nil
+	}
+}
+
+// resume Returns a handler resuming the paused program with an action
+func resume(action debugger.Action) handler {
+	return func(server *Server, args json.RawMessage) (interface{}, error) {
+		if !server.paused {
+			return nil, errNotPaused
+		}
+		server.resume(action)
+		if action == debugger.CONTINUE {
+			return ContinueResponseBody{AllThreadsContinued: true}, nil
+		}
+		return nil, nil
+	}
+}
+
+// pause Requests the running program to pause before its next line
+func (server *Server) pause(args json.RawMessage) (interface{}, error) {
+	if !server.running {
+		return nil, errors.New("Program is not running")
+	}
+	if !server.paused {
+		server.debugger.Pause()
+	}
+	return nil, nil
+}
+
+// terminate Stops the program if it is running
+func (server *Server) terminate(args json.RawMessage) (interface{}, error) {
+	server.stopProgram()
+	return nil, nil
+}
+
+// frame Returns a frame of the paused program by its id
+func (server *Server) frame(id int) (*debugger.Frame, error) {
+	if !server.paused {
+		return nil, errNotPaused
+	}
+	frames := server.debugger.Frames()
+	if id < 1 || id > len(frames) {
+		return nil, fmt.Errorf("Invalid frameId: %d", id)
+	}
+	return frames[id-1], nil
+}
+
+// start Starts evaluating the launched program once the client is configured
+func (server *Server) start() {
+	if server.program == nil || !server.configured || server.debugger != nil {
+		return
+	}
+	server.debugger = debugger.New(server, server.stopOnEntry)
+	for path, lines := range server.breakpoints {
+		for _, line := range lines {
+			server.debugger.SetBreakpoint(debugger.Breakpoint{Filename: path, Line: line})
+		}
+	}
+	server.output = evaluator.Output
+	evaluator.Output = &outputWriter{conn: server.conn, category: "stdout"}
+	server.running = true
+	program, d := server.program, server.debugger
+	go func() {
+		server.done <- d.Run(program, object.NewEnvironment())
+	}()
+}
+
+// stopped Notifies the client that the program paused
+func (server *Server) stopped(stop debugger.Stop) {
+	server.paused = true
+	server.handles.reset()
+	reason := string(stop.Reason)
+	if stop.Reason == debugger.RETURN_STOP {
+		reason = string(debugger.STEP_STOP)
+	}
+	server.conn.Event("stopped", StoppedEventBody{
+		Reason:            reason,
+		ThreadID:          THREAD_ID,
+		AllThreadsStopped: true,
+	})
+}
+
+// resume Resumes the paused program
+func (server *Server) resume(action debugger.Action) {
+	server.paused = false
+	server.handles.reset()
+	server.actions <- action
+}
+
+// finished Reports the result of the program and notifies the client that it exited
+func (server *Server) finished(result object.Object) {
+	server.running, server.paused = false, false
+	evaluator.Output = server.output
+	exitCode := 0
+	if err, ok := result.(*object.Error); ok && !server.debugger.Quit() {
+		server.conn.Event("output", OutputEventBody{Category: "stderr", Output: err.Traceback() + "\n"})
+		exitCode = 1
+	}
+	server.conn.Event("exited", ExitedEventBody{ExitCode: exitCode})
+	server.conn.Event("terminated", nil)
+}
+
+// stopProgram Stops the running program and waits for it to finish
+func (server *Server) stopProgram() {
+	if !server.running {
+		return
+	}
+	if server.paused {
+		server.resume(debugger.QUIT)
+	} else {
+		server.debugger.Pause()
+	}
+	for {
+		select {
+		case <-server.pauses:
+			server.actions <- debugger.QUIT
+		case result := <-server.done:
+			server.finished(result)
+			return
+		}
+	}
+}
+
+// outputWriter Writer sending what the program prints to the client as output events
+type outputWriter struct {
+	conn     *Conn
+	category string
+}
+
+func (writer *outputWriter) Write(p []byte) (int, error) {
+	if err := writer.conn.Event("output", OutputEventBody{Category: writer.category, Output: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// loadProgram Reads and parses a monkey file and expands its macros
+func loadProgram(path string) (ast.Node, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := parser.New(lexer.NewWithFilename(string(source), path))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, p.Errors()[0]
+	}
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, expandErr := evaluator.ExpandMacros(program, macroEnv)
+	if expandErr != nil {
+		return nil, errors.New(expandErr.Traceback())
+	}
+	return expanded, nil
+}
+
+// unmarshalArgs Decodes the arguments of a request
+func unmarshalArgs(args json.RawMessage, v interface{}) error {
+	if len(args) == 0 {
+		return errors.New("Missing arguments")
+	}
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("Invalid arguments: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package dap
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSource = `let double = fn(x) {
+  let y = x * 2;
+  y
+};
+let nums = [1, 2];
+let info = {"name": "monkey"};
+let a = double(3);
+puts(a);
+a`
+
+// testClient In-process client connected to a Server through pipes,
+// events read while waiting for a response are queued
+type testClient struct {
+	t      *testing.T
+	conn   *Conn
+	events []*Message
+	done   chan error
+}
+
+func newTestClient(t *testing.T) *testClient {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(serverIn, serverOut).Serve()
+		serverOut.Close()
+	}()
+	return &testClient{t: t, conn: NewConn(clientIn, clientOut), done: done}
+}
+
+// request Sends a request and returns its response, decoding its body into body
+func (client *testClient) request(command string, arguments, body interface{}) *Message {
+	seq, err := client.conn.Request(command, arguments)
+	if err != nil {
+		client.t.Fatalf("Failed to send %s: %s", command, err)
+	}
+	for {
+		msg := client.read()
+		if msg.Type == EVENT {
+			client.events = append(client.events, msg)
+			continue
+		}
+		if msg.RequestSeq != seq || msg.Command != command {
+			client.t.Fatalf("Expected response to %s Got=%+v", command, msg)
+		}
+		if body != nil && msg.Success {
+			if err := json.Unmarshal(msg.Body, body); err != nil {
+				client.t.Fatalf("Failed to decode body of %s: %s", command, err)
+			}
+		}
+		return msg
+	}
+}
+
+// mustRequest Sends a request that must succeed
+func (client *testClient) mustRequest(command string, arguments, body interface{}) {
+	if resp := client.request(command, arguments, body); !resp.Success {
+		client.t.Fatalf("%s failed: %s", command, resp.Message)
+	}
+}
+
+// event Returns the next event skipping output events, decoding its body into body
+func (client *testClient) event(name string, body interface{}) {
+	for {
+		var msg *Message
+		if len(client.events) > 0 {
+			msg, client.events = client.events[0], client.events[1:]
+		} else {
+			msg = client.read()
+		}
+		if msg.Type == EVENT && msg.Event == "output" && name != "output" {
+			continue
+		}
+		if msg.Type != EVENT || msg.Event != name {
+			client.t.Fatalf("Expected %s event Got=%+v", name, msg)
+		}
+		if body != nil {
+			if err := json.Unmarshal(msg.Body, body); err != nil {
+				client.t.Fatalf("Failed to decode body of %s: %s", name, err)
+			}
+		}
+		return
+	}
+}
+
+func (client *testClient) read() *Message {
+	msg, err := client.conn.Read()
+	if err != nil {
+		client.t.Fatalf("Failed to read message: %s", err)
+	}
+	return msg
+}
+
+// expectStop Waits for the program to stop and returns its innermost frame
+func (client *testClient) expectStop(reason string) StackFrame {
+	var stopped StoppedEventBody
+	client.event("stopped", &stopped)
+	if stopped.Reason != reason || stopped.ThreadID != THREAD_ID {
+		client.t.Fatalf("Expected stop reason %s Got=%+v", reason, stopped)
+	}
+	var trace StackTraceResponseBody
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: THREAD_ID}, &trace)
+	return trace.StackFrames[0]
+}
+
+func writeTestProgram(t *testing.T, source string) string {
+	path := filepath.Join(t.TempDir(), "test.monkey")
+	if err := os.WriteFile(path, []byte(source), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDebugSession(t *testing.T) {
+	path := writeTestProgram(t, testSource)
+	client := newTestClient(t)
+	var capabilities Capabilities
+	client.mustRequest("initialize", map[string]string{"adapterID": "monkey"}, &capabilities)
+	if !capabilities.SupportsConfigurationDoneRequest {
+		t.Errorf("Expected configurationDone support Got=%+v", capabilities)
+	}
+	client.event("initialized", nil)
+	client.mustRequest("launch", LaunchArguments{Program: path}, nil)
+	var breakpoints SetBreakpointsResponseBody
+	client.mustRequest("setBreakpoints", SetBreakpointsArguments{
+		Source:      Source{Path: path},
+		Breakpoints: []SourceBreakpoint{{Line: 2}},
+	}, &breakpoints)
+	if len(breakpoints.Breakpoints) != 1 || !breakpoints.Breakpoints[0].Verified {
+		t.Fatalf("Expected a verified breakpoint Got=%+v", breakpoints)
+	}
+	client.mustRequest("configurationDone", nil, nil)
+
+	client.event("stopped", nil)
+	var trace StackTraceResponseBody
+	client.mustRequest("stackTrace", StackTraceArguments{ThreadID: THREAD_ID}, &trace)
+	expectedFrames := []StackFrame{
+		{ID: 1, Name: "double", Line: 2, Column: 3},
+		{ID: 2, Name: "<module>", Line: 7, Column: 15},
+	}
+	if len(trace.StackFrames) != len(expectedFrames) {
+		t.Fatalf("Wrong number of frames Got=%+v", trace.StackFrames)
+	}
+	for i, expected := range expectedFrames {
+		frame := trace.StackFrames[i]
+		if frame.ID != expected.ID || frame.Name != expected.Name || frame.Line != expected.Line ||
+			frame.Column != expected.Column || frame.Source == nil || frame.Source.Path != path {
+			t.Errorf("%d - Wrong frame Expected=%+v Got=%+v", i, expected, frame)
+		}
+	}
+
+	var scopes ScopesResponseBody
+	client.mustRequest("scopes", ScopesArguments{FrameID: 1}, &scopes)
+	if len(scopes.Scopes) != 2 || scopes.Scopes[0].Name != "Locals" || scopes.Scopes[1].Name != "Globals" {
+		t.Fatalf("Wrong scopes Got=%+v", scopes.Scopes)
+	}
+	variableTests := []struct {
+		reference int
+		expected  []string
+	}{
+		{scopes.Scopes[0].VariablesReference, []string{"x=3:INTEGER"}},
+		{scopes.Scopes[1].VariablesReference, []string{
+			"double=fn double(x):FUNCTION", "info={name: monkey}:HASH+", "nums=[1, 2]:ARRAY+"}},
+	}
+	var globals []Variable
+	for i, test := range variableTests {
+		var variables VariablesResponseBody
+		client.mustRequest("variables", VariablesArguments{VariablesReference: test.reference}, &variables)
+		if describeVariables(variables.Variables) != strings.Join(test.expected, ", ") {
+			t.Errorf("%d - Wrong variables Expected=%v Got=%s", i, test.expected, describeVariables(variables.Variables))
+		}
+		globals = variables.Variables
+	}
+	expandTests := map[string]string{
+		"info": "name=monkey:STRING",
+		"nums": "[0]=1:INTEGER, [1]=2:INTEGER",
+	}
+	for _, variable := range globals {
+		expected, ok := expandTests[variable.Name]
+		if !ok {
+			continue
+		}
+		var variables VariablesResponseBody
+		client.mustRequest("variables", VariablesArguments{VariablesReference: variable.VariablesReference}, &variables)
+		if describeVariables(variables.Variables) != expected {
+			t.Errorf("Wrong contents of %s Expected=%s Got=%s", variable.Name, expected, describeVariables(variables.Variables))
+		}
+	}
+
+	var evaluated EvaluateResponseBody
+	client.mustRequest("evaluate", EvaluateArguments{Expression: "x * 10", FrameID: 1}, &evaluated)
+	if evaluated.Result != "30" || evaluated.Type != "INTEGER" {
+		t.Errorf("Wrong evaluation Got=%+v", evaluated)
+	}
+	if resp := client.request("evaluate", EvaluateArguments{Expression: "nope", FrameID: 2}, nil); resp.Success ||
+		resp.Message != "NameError: Identifier not found: nope" {
+		t.Errorf("Expected evaluation to fail Got=%+v", resp)
+	}
+
+	client.mustRequest("next", nil, nil)
+	if frame := client.expectStop("step"); frame.Name != "double" || frame.Line != 3 {
+		t.Errorf("Wrong frame after next Got=%+v", frame)
+	}
+	client.mustRequest("stepOut", nil, nil)
+	if frame := client.expectStop("step"); frame.Name != "<module>" || frame.Line != 7 {
+		t.Errorf("Wrong frame after stepOut Got=%+v", frame)
+	}
+	client.mustRequest("stepIn", nil, nil)
+	if frame := client.expectStop("step"); frame.Name != "<module>" || frame.Line != 8 {
+		t.Errorf("Wrong frame after stepIn Got=%+v", frame)
+	}
+
+	client.mustRequest("continue", nil, nil)
+	var output OutputEventBody
+	client.event("output", &output)
+	if output.Category != "stdout" || output.Output != "6\n" {
+		t.Errorf("Wrong output Got=%+v", output)
+	}
+	var exited ExitedEventBody
+	client.event("exited", &exited)
+	if exited.ExitCode != 0 {
+		t.Errorf("Expected exit code 0 Got=%d", exited.ExitCode)
+	}
+	client.event("terminated", nil)
+	if resp := client.request("stackTrace", StackTraceArguments{ThreadID: THREAD_ID}, nil); resp.Success ||
+		resp.Message != errNotPaused.Error() {
+		t.Errorf("Expected stackTrace to fail after exit Got=%+v", resp)
+	}
+	client.mustRequest("disconnect", nil, nil)
+	if err := <-client.done; err != nil {
+		t.Fatalf("Expected clean exit Got=%s", err)
+	}
+}
+
+func TestDisconnectWhilePaused(t *testing.T) {
+	path := writeTestProgram(t, testSource)
+	client := newTestClient(t)
+	client.mustRequest("initialize", map[string]string{}, nil)
+	client.event("initialized", nil)
+	client.mustRequest("configurationDone", nil, nil)
+	client.mustRequest("launch", LaunchArguments{Program: path, StopOnEntry: true}, nil)
+	if frame := client.expectStop("entry"); frame.Line != 1 {
+		t.Errorf("Expected to stop on the first line Got=%+v", frame)
+	}
+	client.mustRequest("disconnect", nil, nil)
+	var exited ExitedEventBody
+	client.event("exited", &exited)
+	client.event("terminated", nil)
+	if err := <-client.done; err != nil {
+		t.Fatalf("Expected clean exit Got=%s", err)
+	}
+}
+
+func TestLaunchErrors(t *testing.T) {
+	client := newTestClient(t)
+	resp := client.request("launch", LaunchArguments{Program: writeTestProgram(t, "let = 1;")}, nil)
+	if resp.Success || !strings.HasSuffix(resp.Message, "test.monkey:1:5: peekToken: Expected type=IDENT Got==") {
+		t.Errorf("Expected launch to fail with a parse error Got=%+v", resp)
+	}
+	if resp := client.request("restart", nil, nil); resp.Success || resp.Message != "Unknown command: restart" {
+		t.Errorf("Expected unknown command Got=%+v", resp)
+	}
+	client.conn.writer.(io.Closer).Close()
+	if err := <-client.done; err != nil {
+		t.Fatalf("Expected clean exit on EOF Got=%s", err)
+	}
+}
+
+// describeVariables Returns a name=value:type list of variables, expandable ones marked with +
+func describeVariables(variables []Variable) string {
+	descriptions := make([]string, 0, len(variables))
+	for _, variable := range variables {
+		description := variable.Name + "=" + variable.Value + ":" + variable.Type
+		if variable.VariablesReference != 0 {
+			description += "+"
+		}
+		descriptions = append(descriptions, description)
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,97 @@
+package dap
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+// handles Environments and values the client can expand while the program
+// is paused, referenced by their variablesReference. References are only
+// valid until the program is resumed
+type handles struct {
+	values []interface{}
+}
+
+// add Stores an environment or value and returns its reference, references start at 1
+func (h *handles) add(value interface{}) int {
+	h.values = append(h.values, value)
+	return len(h.values)
+}
+
+// get Returns the environment or value stored under a reference
+func (h *handles) get(reference int) (interface{}, bool) {
+	if reference < 1 || reference > len(h.values) {
+		return nil, false
+	}
+	return h.values[reference-1], true
+}
+
+// reset Invalidates every reference
+func (h *handles) reset() {
+	h.values = nil
+}
+
+// scopes Returns a scope for each environment in the chain starting at env
+func (h *handles) scopes(env *object.Environment) []Scope {
+	scopes := make([]Scope, 0)
+	for level := 0; env != nil; level++ {
+		name := "Closure " + strconv.Itoa(level)
+		switch {
+		case env.Outer() == nil:
+			name = "Globals"
+		case level == 0:
+			name = "Locals"
+		}
+		scopes = append(scopes, Scope{Name: name, VariablesReference: h.add(env)})
+		env = env.Outer()
+	}
+	return scopes
+}
+
+// variables Returns the bindings of an environment or the contents of an array or hash
+func (h *handles) variables(value interface{}) []Variable {
+	variables := make([]Variable, 0)
+	switch value := value.(type) {
+	case *object.Environment:
+		bindings := value.Bindings()
+		names := make([]string, 0, len(bindings))
+		for name := range bindings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			variables = append(variables, h.variable(name, bindings[name]))
+		}
+	case *object.Array:
+		for i, element := range value.Elements {
+			variables = append(variables, h.variable("["+strconv.Itoa(i)+"]", element))
+		}
+	case *object.Hash:
+		for _, pair := range value.Entries() {
+			variables = append(variables, h.variable(pair.Key.Inspect(), pair.Value))
+		}
+	}
+	return variables
+}
+
+// variable Returns a Variable holding a value, non empty arrays and hashes are expandable
+func (h *handles) variable(name string, value object.Object) Variable {
+	variable := Variable{Name: name, Value: value.Inspect(), Type: string(value.Type())}
+	if isExpandable(value) {
+		variable.VariablesReference = h.add(value)
+	}
+	return variable
+}
+
+// isExpandable Checks if a value has contents the client can expand
+func isExpandable(value object.Object) bool {
+	switch value := value.(type) {
+	case *object.Array:
+		return len(value.Elements) > 0
+	case *object.Hash:
+		return len(value.Pairs) > 0
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/CzarSimon/monkey/debugger"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/object"
+)
+
+// debugCommand Evaluates a monkey file under an interactive debugger reading
+// commands from stdin, the program is paused before its first line
+func debugCommand(args []string) int {
+	flags := flag.NewFlagSet("debug", flag.ExitOnError)
+	searchPath := flags.String("path", "", "list of directories searched for imported modules")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: monkey debug [-path dirs] file.monkey")
+		return 2
+	}
+	if *searchPath != "" {
+		evaluator.Modules.SearchPath = append(filepath.SplitList(*searchPath), evaluator.Modules.SearchPath...)
+	}
+	filename := flags.Arg(0)
+	program, ok := loadProgram(filename)
+	if !ok {
+		return 1
+	}
+	console := debugger.NewConsole(os.Stdin, os.Stdout, filename)
+	d := debugger.New(console, true)
+	result := d.Run(program, object.NewEnvironment())
+	if d.Quit() {
+		fmt.Println("Program stopped")
+		return 0
+	}
+	if err, ok := result.(*object.Error); ok {
+		fmt.Fprintln(os.Stderr, err.Traceback())
+		return 1
+	}
+	if result != nil && result != evaluator.NULL {
+		fmt.Println(result.Inspect())
+	}
+	fmt.Println("Program finished")
+	return 0
+}
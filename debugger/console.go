@@ -0,0 +1,323 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+// PROMPT Prompt printed when the console waits for a command
+const PROMPT = "(mdb) "
+
+// LIST_CONTEXT Number of lines listed before and after the current line
+const LIST_CONTEXT = 5
+
+// consoleCommand A command inspecting or resuming a paused program, commands
+// resuming the program return the action to resume it with and true
+type consoleCommand struct {
+	usage       string
+	description string
+	run         func(console *Console, arg string) (Action, bool)
+}
+
+// consoleCommands Commands available in the console mapped by name
+var consoleCommands map[string]consoleCommand
+
+// commandAliases Short names of the console commands
+var commandAliases = map[string]string{
+	"b":  "break",
+	"c":  "continue",
+	"s":  "step",
+	"n":  "next",
+	"o":  "out",
+	"p":  "print",
+	"e":  "env",
+	"bt": "backtrace",
+	"f":  "frame",
+	"l":  "list",
+	"q":  "quit",
+	"h":  "help",
+}
+
+func init() {
+	consoleCommands = map[string]consoleCommand{
+		"break":       {"break [file:]line", "Set a breakpoint", (*Console).setBreakpoint},
+		"clear":       {"clear [file:]line", "Remove a breakpoint", (*Console).clearBreakpoint},
+		"breakpoints": {"breakpoints", "List the breakpoints", (*Console).listBreakpoints},
+		"continue":    {"continue", "Run until a breakpoint is reached", resume(CONTINUE)},
+		"step":        {"step", "Run to the next line, stepping into calls", resume(STEP_IN)},
+		"next":        {"next", "Run to the next line, stepping over calls", resume(STEP_OVER)},
+		"out":         {"out", "Run until the current function returns", resume(STEP_OUT)},
+		"print":       {"print expr", "Evaluate an expression in the selected frame", (*Console).print},
+		"env":         {"env", "Print the environments of the selected frame", (*Console).printEnv},
+		"backtrace":   {"backtrace", "Print the functions being executed", (*Console).backtrace},
+		"frame":       {"frame n", "Select the frame to inspect, 0 is the innermost", (*Console).selectFrame},
+		"list":        {"list", "Print the source around the current line", (*Console).list},
+		"quit":        {"quit", "Stop the program", resume(QUIT)},
+		"help":        {"help", "List the available commands", (*Console).printHelp},
+	}
+}
+
+// resume Returns a command resuming the program with an action
+func resume(action Action) func(console *Console, arg string) (Action, bool) {
+	return func(console *Console, arg string) (Action, bool) {
+		return action, true
+	}
+}
+
+// Console Frontend reading commands from an input and writing
+// the pauses of the program and the output of commands to an output
+type Console struct {
+	in       *bufio.Scanner
+	out      io.Writer
+	filename string // file of breakpoints set without a file name
+	debugger *Debugger
+	frame    int    // selected frame, counted from the innermost one
+	last     string // last command, repeated on empty input
+	sources  map[string][]string
+}
+
+// NewConsole Creates a Console for debugging a file
+func NewConsole(in io.Reader, out io.Writer, filename string) *Console {
+	return &Console{
+		in:       bufio.NewScanner(in),
+		out:      out,
+		filename: filename,
+		sources:  make(map[string][]string),
+	}
+}
+
+// Paused Prints where the program paused and runs commands until one
+// resumes the program, the program is stopped when the input ends
+func (console *Console) Paused(debugger *Debugger, stop Stop) Action {
+	console.debugger, console.frame = debugger, 0
+	console.printStop(stop)
+	for {
+		fmt.Fprint(console.out, PROMPT)
+		if !console.in.Scan() {
+			fmt.Fprintln(console.out)
+			return QUIT
+		}
+		input := strings.TrimSpace(console.in.Text())
+		if input == "" {
+			input = console.last
+		}
+		console.last = input
+		if action, resumed := console.runCommand(input); resumed {
+			return action
+		}
+	}
+}
+
+// runCommand Parses the command name and argument from input and runs the command
+func (console *Console) runCommand(input string) (Action, bool) {
+	if input == "" {
+		return CONTINUE, false
+	}
+	name, arg := input, ""
+	if i := strings.IndexAny(input, " \t"); i != -1 {
+		name, arg = input[:i], strings.TrimSpace(input[i:])
+	}
+	if alias, ok := commandAliases[name]; ok {
+		name = alias
+	}
+	command, ok := consoleCommands[name]
+	if !ok {
+		fmt.Fprintf(console.out, "Unknown command: %s, type help for a list of commands\n", name)
+		return CONTINUE, false
+	}
+	return command.run(console, arg)
+}
+
+// printStop Prints why and where the program paused
+func (console *Console) printStop(stop Stop) {
+	switch stop.Reason {
+	case BREAKPOINT_STOP:
+		fmt.Fprint(console.out, "Breakpoint at ")
+	case RETURN_STOP:
+		fmt.Fprintf(console.out, "Returned from %s: %s\n", stop.Function, stop.Value.Inspect())
+		fmt.Fprint(console.out, "Stopped at ")
+	default:
+		fmt.Fprint(console.out, "Stopped at ")
+	}
+	frame := console.debugger.Frames()[0]
+	fmt.Fprintln(console.out, object.Frame{Function: frame.Function, Position: frame.Position})
+	if line, ok := console.sourceLine(frame.Position.Filename, frame.Position.Line); ok {
+		fmt.Fprintf(console.out, "%4d  %s\n", frame.Position.Line, line)
+	}
+}
+
+// setBreakpoint Sets a breakpoint at a line
+func (console *Console) setBreakpoint(arg string) (Action, bool) {
+	if breakpoint, ok := console.parseBreakpoint(arg, "break"); ok {
+		console.debugger.SetBreakpoint(breakpoint)
+		fmt.Fprintf(console.out, "Breakpoint set at %s:%d\n", breakpoint.Filename, breakpoint.Line)
+	}
+	return CONTINUE, false
+}
+
+// clearBreakpoint Removes a breakpoint from a line
+func (console *Console) clearBreakpoint(arg string) (Action, bool) {
+	breakpoint, ok := console.parseBreakpoint(arg, "clear")
+	if !ok {
+		return CONTINUE, false
+	}
+	if console.debugger.ClearBreakpoint(breakpoint) {
+		fmt.Fprintf(console.out, "Breakpoint cleared at %s:%d\n", breakpoint.Filename, breakpoint.Line)
+	} else {
+		fmt.Fprintf(console.out, "No breakpoint at %s:%d\n", breakpoint.Filename, breakpoint.Line)
+	}
+	return CONTINUE, false
+}
+
+// parseBreakpoint Parses a [file:]line argument, printing the usage of the command if invalid
+func (console *Console) parseBreakpoint(arg, command string) (Breakpoint, bool) {
+	filename, lineArg := console.filename, arg
+	if i := strings.LastIndex(arg, ":"); i != -1 {
+		filename, lineArg = arg[:i], arg[i+1:]
+	}
+	line, err := strconv.Atoi(lineArg)
+	if err != nil || line < 1 || filename == "" {
+		fmt.Fprintf(console.out, "Usage: %s\n", consoleCommands[command].usage)
+		return Breakpoint{}, false
+	}
+	return Breakpoint{Filename: filename, Line: line}, true
+}
+
+// listBreakpoints Prints the breakpoints that are set
+func (console *Console) listBreakpoints(arg string) (Action, bool) {
+	breakpoints := console.debugger.Breakpoints()
+	if len(breakpoints) == 0 {
+		fmt.Fprintln(console.out, "No breakpoints")
+	}
+	for _, breakpoint := range breakpoints {
+		fmt.Fprintf(console.out, "%s:%d\n", breakpoint.Filename, breakpoint.Line)
+	}
+	return CONTINUE, false
+}
+
+// print Evaluates an expression in the selected frame and prints the result
+func (console *Console) print(arg string) (Action, bool) {
+	if arg == "" {
+		fmt.Fprintf(console.out, "Usage: %s\n", consoleCommands["print"].usage)
+		return CONTINUE, false
+	}
+	result := console.debugger.Evaluate(arg, console.frame)
+	switch result := result.(type) {
+	case nil:
+	case *object.Error:
+		fmt.Fprintf(console.out, "%s: %s\n", result.Kind, result.Message)
+	default:
+		fmt.Fprintln(console.out, result.Inspect())
+	}
+	return CONTINUE, false
+}
+
+// printEnv Prints the bindings of each environment of the selected frame, innermost first
+func (console *Console) printEnv(arg string) (Action, bool) {
+	env := console.debugger.Frames()[console.frame].Env
+	if env == nil {
+		fmt.Fprintln(console.out, "No environment")
+	}
+	for level := 0; env != nil; level++ {
+		fmt.Fprintf(console.out, "Environment %d:\n", level)
+		bindings := env.Bindings()
+		names := make([]string, 0, len(bindings))
+		for name := range bindings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(console.out, "  %s = %s\n", name, bindings[name].Inspect())
+		}
+		env = env.Outer()
+	}
+	return CONTINUE, false
+}
+
+// backtrace Prints the frames being executed, innermost first, marking the selected one
+func (console *Console) backtrace(arg string) (Action, bool) {
+	for i, frame := range console.debugger.Frames() {
+		marker := " "
+		if i == console.frame {
+			marker = "*"
+		}
+		fmt.Fprintf(console.out, "%s #%d %s\n", marker, i,
+			object.Frame{Function: frame.Function, Position: frame.Position})
+	}
+	return CONTINUE, false
+}
+
+// selectFrame Selects the frame inspected by print, env and list
+func (console *Console) selectFrame(arg string) (Action, bool) {
+	frames := console.debugger.Frames()
+	frame, err := strconv.Atoi(arg)
+	if err != nil || frame < 0 || frame >= len(frames) {
+		fmt.Fprintf(console.out, "Usage: %s, where 0 <= n < %d\n", consoleCommands["frame"].usage, len(frames))
+		return CONTINUE, false
+	}
+	console.frame = frame
+	fmt.Fprintf(console.out, "#%d %s\n", frame,
+		object.Frame{Function: frames[frame].Function, Position: frames[frame].Position})
+	return CONTINUE, false
+}
+
+// list Prints the lines around the current line of the selected frame
+func (console *Console) list(arg string) (Action, bool) {
+	pos := console.debugger.Frames()[console.frame].Position
+	if _, ok := console.sourceLine(pos.Filename, pos.Line); !ok {
+		fmt.Fprintln(console.out, "No source available")
+		return CONTINUE, false
+	}
+	for line := pos.Line - LIST_CONTEXT; line <= pos.Line+LIST_CONTEXT; line++ {
+		text, ok := console.sourceLine(pos.Filename, line)
+		if !ok {
+			continue
+		}
+		marker := " "
+		if line == pos.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(console.out, "%s%3d  %s\n", marker, line, text)
+	}
+	return CONTINUE, false
+}
+
+// printHelp Prints the usage and description of every command
+func (console *Console) printHelp(arg string) (Action, bool) {
+	names := make([]string, 0, len(consoleCommands))
+	for name := range consoleCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		command := consoleCommands[name]
+		fmt.Fprintf(console.out, "%-20s %s\n", command.usage, command.description)
+	}
+	return CONTINUE, false
+}
+
+// sourceLine Returns a line of a source file, files are read once when first needed
+func (console *Console) sourceLine(filename string, line int) (string, bool) {
+	if filename == "" {
+		return "", false
+	}
+	lines, ok := console.sources[filename]
+	if !ok {
+		source, err := os.ReadFile(filename)
+		if err == nil {
+			lines = strings.Split(string(source), "\n")
+		}
+		console.sources[filename] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+	return lines[line-1], true
+}
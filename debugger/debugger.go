@@ -0,0 +1,260 @@
+package debugger
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// Action How a paused program is resumed
+type Action int
+
+const (
+	CONTINUE  Action = iota // run until a breakpoint is reached
+	STEP_IN                 // stop at the next line, entering called functions
+	STEP_OVER               // stop at the next line of the current function or a caller
+	STEP_OUT                // stop when the current function returns
+	QUIT                    // stop evaluating the program
+)
+
+// StopReason Why a program was paused
+type StopReason string
+
+const (
+	ENTRY_STOP      StopReason = "entry"
+	BREAKPOINT_STOP StopReason = "breakpoint"
+	STEP_STOP       StopReason = "step"
+	RETURN_STOP     StopReason = "return"
+	PAUSE_STOP      StopReason = "pause"
+)
+
+// Stop Description of a pause of a program
+type Stop struct {
+	Reason   StopReason
+	Function string        // function returned from on a RETURN_STOP
+	Value    object.Object // value returned on a RETURN_STOP
+}
+
+// Frame A function being executed
+type Frame struct {
+	Function string
+	Position token.Position      // position of the statement or call being evaluated
+	Env      *object.Environment // environment of the statement, nil until the first statement of the frame
+}
+
+// Frontend Receives the pauses of a program and decides how to resume it
+type Frontend interface {
+	Paused(debugger *Debugger, stop Stop) Action
+}
+
+// Breakpoint A line of a source file at which programs are paused
+type Breakpoint struct {
+	Filename string
+	Line     int
+}
+
+// Debugger evaluator.Hook pausing programs at breakpoints and after steps.
+// A program pauses at most once per line, when the first statement on the
+// line is about to be evaluated. Breakpoints may be changed and pauses
+// requested from other goroutines while the program runs
+type Debugger struct {
+	frontend       Frontend
+	mutex          sync.Mutex // guards breakpoints and pauseRequested
+	breakpoints    map[Breakpoint]bool
+	pauseRequested bool
+	frames         []*Frame // outermost first
+	stopOnEntry    bool
+	action         Action
+	depth          int  // number of frames when the program was last resumed
+	evaluating     bool // set while Evaluate runs, when notifications are ignored
+	quit           bool
+}
+
+// New Creates a Debugger reporting pauses to a frontend, if stopOnEntry is
+// set the program is paused before its first line is evaluated
+func New(frontend Frontend, stopOnEntry bool) *Debugger {
+	return &Debugger{
+		frontend:    frontend,
+		breakpoints: make(map[Breakpoint]bool),
+		frames:      []*Frame{{Function: object.MODULE_FRAME}},
+		stopOnEntry: stopOnEntry,
+		action:      CONTINUE,
+	}
+}
+
+// Run Evaluates a program in an environment while debugging it
+func (debugger *Debugger) Run(program ast.Node, env *object.Environment) object.Object {
+	defer evaluator.SetHook(evaluator.SetHook(debugger))
+	return evaluator.Eval(program, env)
+}
+
+// SetBreakpoint Pauses programs before the line is evaluated
+func (debugger *Debugger) SetBreakpoint(breakpoint Breakpoint) {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	debugger.breakpoints[breakpoint] = true
+}
+
+// ClearBreakpoint Removes a breakpoint, returns false if it was not set
+func (debugger *Debugger) ClearBreakpoint(breakpoint Breakpoint) bool {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	if !debugger.breakpoints[breakpoint] {
+		return false
+	}
+	delete(debugger.breakpoints, breakpoint)
+	return true
+}
+
+// Breakpoints Returns the breakpoints that are set sorted by file and line
+func (debugger *Debugger) Breakpoints() []Breakpoint {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	breakpoints := make([]Breakpoint, 0, len(debugger.breakpoints))
+	for breakpoint := range debugger.breakpoints {
+		breakpoints = append(breakpoints, breakpoint)
+	}
+	sort.Slice(breakpoints, func(i, j int) bool {
+		a, b := breakpoints[i], breakpoints[j]
+		return a.Filename < b.Filename || (a.Filename == b.Filename && a.Line < b.Line)
+	})
+	return breakpoints
+}
+
+// Pause Requests the running program to pause before its next line
+func (debugger *Debugger) Pause() {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	debugger.pauseRequested = true
+}
+
+// Frames Returns the functions being executed, innermost first
+func (debugger *Debugger) Frames() []*Frame {
+	frames := make([]*Frame, 0, len(debugger.frames))
+	for i := len(debugger.frames) - 1; i >= 0; i-- {
+		frames = append(frames, debugger.frames[i])
+	}
+	return frames
+}
+
+// Quit Checks if the frontend stopped the evaluation of the program
+func (debugger *Debugger) Quit() bool {
+	return debugger.quit
+}
+
+// Evaluate Evaluates source code in the environment of a frame, counted
+// from the innermost one, without pausing in the code evaluated
+func (debugger *Debugger) Evaluate(source string, frame int) object.Object {
+	if frame < 0 || frame >= len(debugger.frames) {
+		return object.NewKindErrorf(object.VALUE_ERROR, "No frame %d", frame)
+	}
+	env := debugger.frames[len(debugger.frames)-1-frame].Env
+	if env == nil {
+		return object.NewErrorf("Frame %d has no environment", frame)
+	}
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return object.NewErrorf("Could not parse %s: %s", source, p.Errors()[0])
+	}
+	debugger.evaluating = true
+	defer func() { debugger.evaluating = false }()
+	return evaluator.Eval(program, env)
+}
+
+// BeforeStatement Pauses the program if the statement starts a line that
+// has a breakpoint or completes a step
+func (debugger *Debugger) BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	if debugger.evaluating {
+		return nil
+	}
+	if debugger.quit {
+		return quitError()
+	}
+	frame := debugger.frames[len(debugger.frames)-1]
+	pos := stmt.Pos()
+	newLine := frame.Env == nil || frame.Position.Line != pos.Line || frame.Position.Filename != pos.Filename
+	frame.Position, frame.Env = pos, env
+	if !newLine {
+		return nil
+	}
+	reason, stop := debugger.stopReason(pos)
+	if !stop {
+		return nil
+	}
+	if debugger.pause(Stop{Reason: reason}) == QUIT {
+		return quitError()
+	}
+	return nil
+}
+
+// BeforeCall Pushes a frame for the called function
+func (debugger *Debugger) BeforeCall(fn object.Object, args []object.Object, pos token.Position) {
+	if debugger.evaluating {
+		return
+	}
+	if pos.IsValid() {
+		debugger.frames[len(debugger.frames)-1].Position = pos
+	}
+	debugger.frames = append(debugger.frames, &Frame{Function: evaluator.FunctionName(fn)})
+}
+
+// AfterCall Pops the frame of the called function and pauses the program
+// if stepping out of the function
+func (debugger *Debugger) AfterCall(fn object.Object, result object.Object) {
+	if debugger.evaluating {
+		return
+	}
+	debugger.frames = debugger.frames[:len(debugger.frames)-1]
+	if _, ok := fn.(*object.Function); !ok || debugger.quit {
+		return
+	}
+	if debugger.action == STEP_OUT && len(debugger.frames) < debugger.depth {
+		debugger.pause(Stop{Reason: RETURN_STOP, Function: evaluator.FunctionName(fn), Value: result})
+	}
+}
+
+// stopReason Checks if the program should pause at the start of a line
+func (debugger *Debugger) stopReason(pos token.Position) (StopReason, bool) {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	switch {
+	case debugger.stopOnEntry:
+		debugger.stopOnEntry = false
+		return ENTRY_STOP, true
+	case debugger.breakpoints[Breakpoint{Filename: pos.Filename, Line: pos.Line}]:
+		return BREAKPOINT_STOP, true
+	case debugger.pauseRequested:
+		debugger.pauseRequested = false
+		return PAUSE_STOP, true
+	case debugger.action == STEP_IN:
+		return STEP_STOP, true
+	case debugger.action == STEP_OVER && len(debugger.frames) <= debugger.depth:
+		return STEP_STOP, true
+	}
+	return "", false
+}
+
+// pause Lets the frontend inspect the paused program and records how to resume it
+func (debugger *Debugger) pause(stop Stop) Action {
+	debugger.mutex.Lock()
+	debugger.pauseRequested = false
+	debugger.mutex.Unlock()
+	action := debugger.frontend.Paused(debugger, stop)
+	debugger.action, debugger.depth = action, len(debugger.frames)
+	if action == QUIT {
+		debugger.quit = true
+	}
+	return action
+}
+
+// quitError Returns the error stopping the evaluation of a program the frontend quit
+func quitError() *object.Error {
+	return object.NewKindError(object.INTERRUPT_ERROR, "Program stopped by the debugger")
+}
@@ -0,0 +1,200 @@
+package debugger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+const testSource = `let double = fn(x) {
+  let y = x * 2;
+  y
+};
+let nums = [1, 2];
+let a = double(3);
+let b = map(nums, double);
+a + b[1]`
+
+// scriptedFrontend Frontend resuming with a list of actions and
+// recording where the program paused
+type scriptedFrontend struct {
+	actions []Action
+	stops   []string
+}
+
+func (frontend *scriptedFrontend) Paused(debugger *Debugger, stop Stop) Action {
+	frame := debugger.Frames()[0]
+	frontend.stops = append(frontend.stops, fmt.Sprintf("%s %s %d:%d depth=%d",
+		stop.Reason, frame.Function, frame.Position.Line, frame.Position.Column, len(debugger.Frames())))
+	if len(frontend.actions) == 0 {
+		return CONTINUE
+	}
+	action := frontend.actions[0]
+	frontend.actions = frontend.actions[1:]
+	return action
+}
+
+func parseTestProgram(t *testing.T, filename, source string) ast.Node {
+	p := parser.New(lexer.NewWithFilename(source, filename))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors: %v", p.Errors())
+	}
+	return program
+}
+
+func TestStepping(t *testing.T) {
+	tests := []struct {
+		actions     []Action
+		breakpoints []int
+		expected    []string
+	}{
+		{
+			actions: []Action{STEP_IN, STEP_IN, STEP_IN, STEP_IN, STEP_IN},
+			expected: []string{
+				"entry <module> 1:1 depth=1",
+				"step <module> 5:1 depth=1",
+				"step <module> 6:1 depth=1",
+				"step double 2:3 depth=2",
+				"step double 3:3 depth=2",
+				"step <module> 7:1 depth=1",
+			},
+		},
+		{
+			actions: []Action{STEP_OVER, STEP_OVER, STEP_OVER, STEP_OVER},
+			expected: []string{
+				"entry <module> 1:1 depth=1",
+				"step <module> 5:1 depth=1",
+				"step <module> 6:1 depth=1",
+				"step <module> 7:1 depth=1",
+				"step <module> 8:1 depth=1",
+			},
+		},
+		{
+			actions:     []Action{CONTINUE, STEP_OUT, STEP_OVER, CONTINUE, STEP_OUT},
+			breakpoints: []int{2},
+			expected: []string{
+				"entry <module> 1:1 depth=1",
+				"breakpoint double 2:3 depth=2",
+				"return <module> 6:15 depth=1",
+				"step <module> 7:1 depth=1",
+				"breakpoint double 2:3 depth=3",
+				"return map 0:0 depth=2",
+				"breakpoint double 2:3 depth=3",
+			},
+		},
+	}
+	for i, test := range tests {
+		frontend := &scriptedFrontend{actions: test.actions}
+		debugger := New(frontend, true)
+		for _, line := range test.breakpoints {
+			debugger.SetBreakpoint(Breakpoint{Filename: "test.monkey", Line: line})
+		}
+		result := debugger.Run(parseTestProgram(t, "test.monkey", testSource), object.NewEnvironment())
+		if result.Inspect() != "10" {
+			t.Errorf("%d - Wrong result Expected=10 Got=%s", i, result.Inspect())
+		}
+		if strings.Join(frontend.stops, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("%d - Wrong stops\nExpected=%q\nGot=%q", i, test.expected, frontend.stops)
+		}
+	}
+}
+
+func TestQuit(t *testing.T) {
+	source := "let r = try {\n  1\n} catch (e) {\n  2\n} finally {\n  3\n};\nr"
+	frontend := &scriptedFrontend{actions: []Action{STEP_IN, QUIT}}
+	debugger := New(frontend, true)
+	result := debugger.Run(parseTestProgram(t, "test.monkey", source), object.NewEnvironment())
+	err, ok := result.(*object.Error)
+	if !ok || err.Kind != object.INTERRUPT_ERROR || err.Message != "Program stopped by the debugger" {
+		t.Fatalf("Expected the program to be stopped Got=%v", result)
+	}
+	if err.Position.String() != "test.monkey:2:3" {
+		t.Errorf("Expected the error not to be caught Got=%s", err.Position)
+	}
+	if !debugger.Quit() || len(frontend.stops) != 2 {
+		t.Errorf("Expected to quit after two stops Got=%v", frontend.stops)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	var results []string
+	frontend := frontendFunc(func(debugger *Debugger, stop Stop) Action {
+		for _, input := range []string{"x * 10", "a", "let z = 1; z", "nope", "let",
+			"let f = fn(y) { y + 1 }; f(1)", "try { spawn(fn() { 1 }) } catch (e) { error_kind(e) }"} {
+			result := debugger.Evaluate(input, 0)
+			results = append(results, result.Inspect())
+		}
+		results = append(results, debugger.Evaluate("nums", 1).Inspect())
+		results = append(results, debugger.Evaluate("nums", 2).Inspect())
+		return CONTINUE
+	})
+	debugger := New(frontend, false)
+	debugger.SetBreakpoint(Breakpoint{Filename: "test.monkey", Line: 2})
+	debugger.SetBreakpoint(Breakpoint{Filename: "test.monkey", Line: 6})
+	debugger.ClearBreakpoint(Breakpoint{Filename: "test.monkey", Line: 6})
+	debugger.Run(parseTestProgram(t, "test.monkey", testSource), object.NewEnvironment())
+	expected := []string{
+		"30", "ERROR: Identifier not found: a", "1", "ERROR: Identifier not found: nope",
+		"ERROR: Could not parse let: 1:4: peekToken: Expected type=IDENT Got=EOF", "2", "RuntimeError",
+		"[1, 2]", "ERROR: No frame 2",
+	}
+	if strings.Join(results[:len(expected)], "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Wrong results\nExpected=%q\nGot=%q", expected, results[:len(expected)])
+	}
+}
+
+// frontendFunc Adapter allowing a function to be used as a Frontend
+type frontendFunc func(debugger *Debugger, stop Stop) Action
+
+func (f frontendFunc) Paused(debugger *Debugger, stop Stop) Action {
+	return f(debugger, stop)
+}
+
+func TestConsole(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.monkey")
+	if err := os.WriteFile(filename, []byte(testSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	commands := "b 2\nbreakpoints\nc\nbt\nenv\np x + 1\nn\n\nout\nf 1\nf 9\nclear 2\nclear 2\nnope\nc\n"
+	var out bytes.Buffer
+	debugger := New(NewConsole(strings.NewReader(commands), &out, filename), true)
+	result := debugger.Run(parseTestProgram(t, filename, testSource), object.NewEnvironment())
+	if result.Inspect() != "10" {
+		t.Errorf("Wrong result Expected=10 Got=%s", result.Inspect())
+	}
+	expected := "Stopped at " + filename + ":1:1 in <module>\n" +
+		"   1  let double = fn(x) {\n" +
+		"Breakpoint set at " + filename + ":2\n" +
+		filename + ":2\n" +
+		"Breakpoint at " + filename + ":2:3 in double\n" +
+		"   2    let y = x * 2;\n" +
+		"* #0 " + filename + ":2:3 in double\n" +
+		"  #1 " + filename + ":6:15 in <module>\n" +
+		"Environment 0:\n  x = 3\n" +
+		"Environment 1:\n  double = fn double(x)\n  nums = [1, 2]\n" +
+		"4\n" +
+		"Stopped at " + filename + ":3:3 in double\n" +
+		"   3    y\n" +
+		"Stopped at " + filename + ":7:1 in <module>\n" +
+		"   7  let b = map(nums, double);\n" +
+		"Breakpoint at " + filename + ":2:3 in double\n" +
+		"   2    let y = x * 2;\n" +
+		"#1 <builtin> in map\n" +
+		"Usage: frame n, where 0 <= n < 3\n" +
+		"Breakpoint cleared at " + filename + ":2\n" +
+		"No breakpoint at " + filename + ":2\n" +
+		"Unknown command: nope, type help for a list of commands\n"
+	output := strings.Replace(out.String(), PROMPT, "", -1)
+	if output != expected {
+		t.Fatalf("Wrong output\nExpected=%q\nGot=%q", expected, output)
+	}
+}
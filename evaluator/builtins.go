@@ -0,0 +1,132 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// Output Writer that builtins printing values, e.g. puts, write to
+var Output io.Writer = os.Stdout
+
+// outputMutex Serializes the writes to Output made by spawned tasks
+var outputMutex sync.Mutex
+
+// builtins Functions implemented in Go that are available in every environment,
+// populated in init functions to allow builtins to call back into the evaluator
+var builtins = make(map[string]*object.Builtin)
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"len":  builtinLen,
+		"puts": builtinPuts,
+	})
+}
+
+// registerBuiltins Adds a set of functions to the builtins
+func registerBuiltins(fns map[string]object.BuiltinFunction) {
+	for name, fn := range fns {
+		builtins[name] = object.NewBuiltin(name, fn)
+	}
+}
+
+// Builtin Returns the builtin function with a name, nil if there is none
+func Builtin(name string) *object.Builtin {
+	return builtins[name]
+}
+
+// BuiltinNames Returns the sorted names of all builtin functions
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinLen Returns the number of characters in a string or elements in an array or hash
+func builtinLen(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCount("len", args, 1); err != nil {
+		return err
+	}
+	switch arg := args[0].(type) {
+	case *object.String:
+		return object.NewInteger(int64(utf8.RuneCountInString(arg.Value)))
+	case *object.Array:
+		return object.NewInteger(int64(len(arg.Elements)))
+	case *object.Hash:
+		return object.NewInteger(int64(len(arg.Pairs)))
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR, "Argument 1 to len not supported Got=%s",
+			arg.Type())
+	}
+}
+
+// builtinPuts Prints each argument on a separate line
+func builtinPuts(env *object.Environment, args ...object.Object) object.Object {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	for _, arg := range args {
+		fmt.Fprintln(Output, arg.Inspect())
+	}
+	return NULL
+}
+
+// callFunction Calls a function passed to a builtin called from env, the call
+// gets a frame without position since it is made from native code
+func callFunction(env *object.Environment, fn object.Object, args []object.Object) object.Object {
+	return applyObserved(env, fn, args, token.Position{})
+}
+
+// Call Calls a function or builtin from outside of a program, e.g. a test
+// runner, as the task evaluating code in env
+func Call(env *object.Environment, fn object.Object, args []object.Object) object.Object {
+	return callFunction(env, fn, args)
+}
+
+// checkArgs Checks that a builtin was called with arguments of the supplied types
+func checkArgs(name string, args []object.Object, types ...object.ObjectType) *object.Error {
+	if err := checkArgCount(name, args, len(types)); err != nil {
+		return err
+	}
+	for i, expected := range types {
+		if err := checkArgType(name, args, i, expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkArgCount Checks that a builtin was called with the expected number of arguments
+func checkArgCount(name string, args []object.Object, expected int) *object.Error {
+	if len(args) != expected {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to %s Expected=%d Got=%d",
+			name, expected, len(args))
+	}
+	return nil
+}
+
+// checkArgCountRange Checks that a builtin was called with between min and max arguments
+func checkArgCountRange(name string, args []object.Object, min, max int) *object.Error {
+	if len(args) < min || len(args) > max {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to %s Expected=%d-%d Got=%d",
+			name, min, max, len(args))
+	}
+	return nil
+}
+
+// checkArgType Checks that the argument at index i has the expected type
+func checkArgType(name string, args []object.Object, i int, expected object.ObjectType) *object.Error {
+	if args[i].Type() != expected {
+		return object.NewKindErrorf(object.TYPE_ERROR, "Argument %d to %s must be %s Got=%s",
+			i+1, name, expected, args[i].Type())
+	}
+	return nil
+}
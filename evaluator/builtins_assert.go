@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"assert":       builtinAssert,
+		"assert_eq":    builtinAssertEq,
+		"assert_error": builtinAssertError,
+	})
+}
+
+// builtinAssert Raises an AssertionError with an optional message unless its argument is truthy
+func builtinAssert(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkAssertArgs("assert", args, 1); err != nil {
+		return err
+	}
+	if isTruthy(args[0]) {
+		return NULL
+	}
+	return assertionError(args, 1, "Assertion failed")
+}
+
+// builtinAssertEq Raises an AssertionError with an optional message unless its
+// first argument equals the expected value passed as its second argument
+func builtinAssertEq(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkAssertArgs("assert_eq", args, 2); err != nil {
+		return err
+	}
+	actual, expected := args[0], args[1]
+	if objectsEqual(actual, expected) {
+		return NULL
+	}
+	if actual.Type() != expected.Type() {
+		return assertionError(args, 2, "Expected=%s (%s) Got=%s (%s)",
+			expected.Inspect(), expected.Type(), actual.Inspect(), actual.Type())
+	}
+	return assertionError(args, 2, "Expected=%s Got=%s", expected.Inspect(), actual.Inspect())
+}
+
+// builtinAssertError Calls a function without arguments and raises an AssertionError
+// unless it raises an error, whose kind or message must match the optional second
+// argument. The raised error is returned as a value
+func builtinAssertError(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("assert_error", args, 1, 2); err != nil {
+		return err
+	}
+	if err := checkCallable("assert_error", args, 0); err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		if err := checkArgType("assert_error", args, 1, object.STRING_OBJ); err != nil {
+			return err
+		}
+	}
+	result := callFunction(env, args[0], []object.Object{})
+	err, ok := result.(*object.Error)
+	if !ok {
+		return object.NewKindErrorf(object.ASSERTION_ERROR, "Expected an error Got=%s", result.Inspect())
+	}
+	if !err.Catchable() {
+		return err
+	}
+	if len(args) == 2 {
+		expected := stringValue(args[1])
+		if expected != string(err.Kind) && expected != err.Message {
+			return object.NewKindErrorf(object.ASSERTION_ERROR, "Expected error %s Got=%s: %s",
+				expected, err.Kind, err.Message)
+		}
+	}
+	return object.NewErrorValue(err)
+}
+
+// checkAssertArgs Checks the arguments of an assertion taking a number of
+// values followed by an optional message
+func checkAssertArgs(name string, args []object.Object, values int) *object.Error {
+	if err := checkArgCountRange(name, args, values, values+1); err != nil {
+		return err
+	}
+	if len(args) > values {
+		return checkArgType(name, args, values, object.STRING_OBJ)
+	}
+	return nil
+}
+
+// assertionError Creates an AssertionError, prefixing its message with the
+// message passed to the assertion at index i if there is one
+func assertionError(args []object.Object, i int, format string, a ...interface{}) *object.Error {
+	message := fmt.Sprintf(format, a...)
+	if len(args) > i {
+		message = stringValue(args[i]) + ": " + message
+	}
+	return object.NewKindError(object.ASSERTION_ERROR, message)
+}
@@ -0,0 +1,354 @@
+package evaluator
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+// RANGE_CAPACITY Most elements allocated up front for the array built by range,
+// larger ranges grow as they are built instead of failing to allocate at once
+const RANGE_CAPACITY = 1 << 20
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"map":      builtinMap,
+		"filter":   builtinFilter,
+		"reduce":   builtinReduce,
+		"range":    builtinRange,
+		"sort":     builtinSort,
+		"zip":      builtinZip,
+		"keys":     builtinKeys,
+		"values":   builtinValues,
+		"contains": builtinContains,
+	})
+}
+
+// builtinMap Returns an array of the results of calling a function on each element of an array
+func builtinMap(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkCallbackArgs("map", args); err != nil {
+		return err
+	}
+	elements := args[0].(*object.Array).Elements
+	mapped := make([]object.Object, 0, len(elements))
+	for _, element := range elements {
+		result := callFunction(env, args[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		mapped = append(mapped, result)
+	}
+	return object.NewArray(mapped)
+}
+
+// builtinFilter Returns an array of the elements of an array for which a function returns a truthy value
+func builtinFilter(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkCallbackArgs("filter", args); err != nil {
+		return err
+	}
+	filtered := make([]object.Object, 0)
+	for _, element := range args[0].(*object.Array).Elements {
+		result := callFunction(env, args[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			filtered = append(filtered, element)
+		}
+	}
+	return object.NewArray(filtered)
+}
+
+// builtinReduce Combines the elements of an array into a single value by calling a
+// function with the accumulated value and each element, starting from an initial
+// value or, if none is supplied, the first element
+func builtinReduce(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("reduce", args, 2, 3); err != nil {
+		return err
+	}
+	if err := checkCallbackArgs("reduce", args[:2]); err != nil {
+		return err
+	}
+	elements := args[0].(*object.Array).Elements
+	var accumulated object.Object
+	if len(args) == 3 {
+		accumulated = args[2]
+	} else if len(elements) == 0 {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Cannot reduce an empty array without an initial value")
+	} else {
+		accumulated, elements = elements[0], elements[1:]
+	}
+	for _, element := range elements {
+		accumulated = callFunction(env, args[1], []object.Object{accumulated, element})
+		if isError(accumulated) {
+			return accumulated
+		}
+	}
+	return accumulated
+}
+
+// builtinRange Returns an array of the integers from start up to, but not including,
+// end taking steps of a given size. range(end) starts from 0 and step defaults to 1
+func builtinRange(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("range", args, 1, 3); err != nil {
+		return err
+	}
+	bounds := make([]int64, 0, len(args))
+	for i := range args {
+		if err := checkArgType("range", args, i, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+		bounds = append(bounds, args[i].(*object.Integer).Value)
+	}
+	start, end, step := int64(0), bounds[0], int64(1)
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Step of range must not be 0")
+	}
+	count, ok := rangeLength(start, end, step)
+	if !ok {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Range from %d to %d with step %d has too many elements",
+			start, end, step)
+	}
+	size := int64(math.MaxInt64)
+	if count < (math.MaxInt64-object.ARRAY_SIZE)/(object.ELEMENT_SIZE+object.INTEGER_SIZE) {
+		size = object.ARRAY_SIZE + count*(object.ELEMENT_SIZE+object.INTEGER_SIZE)
+	}
+	if err := object.ReserveMemory(env, size); err != nil {
+		return err
+	}
+	capacity := count
+	if capacity > RANGE_CAPACITY {
+		capacity = RANGE_CAPACITY
+	}
+	elements := make([]object.Object, 0, capacity)
+	for n := int64(0); n < count; n++ {
+		elements = append(elements, object.NewInteger(start+n*step))
+	}
+	return object.NewArray(elements)
+}
+
+// rangeLength Returns the number of integers from start up to, but not including,
+// end taking steps of a non zero size. The distance between the bounds is computed
+// unsigned since it may not fit in an int64, false is returned if the count does not fit in an int
+func rangeLength(start, end, step int64) (int64, bool) {
+	var distance, stride uint64
+	switch {
+	case step > 0 && end > start:
+		distance, stride = uint64(end)-uint64(start), uint64(step)
+	case step < 0 && start > end:
+		distance, stride = uint64(start)-uint64(end), uint64(-step)
+	default:
+		return 0, true
+	}
+	count := distance / stride
+	if distance%stride != 0 {
+		count++
+	}
+	if count > math.MaxInt {
+		return 0, false
+	}
+	return int64(count), true
+}
+
+// builtinSort Returns a sorted copy of an array. Without a comparator the array
+// must only contain INTEGERs or only STRINGs, a comparator is called with two
+// elements and returns true if the first should be placed before the second
+func builtinSort(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("sort", args, 1, 2); err != nil {
+		return err
+	}
+	if err := checkArgType("sort", args, 0, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	var less func(a, b object.Object) object.Object
+	if len(args) == 2 {
+		if err := checkCallable("sort", args, 1); err != nil {
+			return err
+		}
+		less = func(a, b object.Object) object.Object {
+			return callFunction(env, args[1], []object.Object{a, b})
+		}
+	} else {
+		less = compareNatural
+	}
+	sorted := append([]object.Object{}, args[0].(*object.Array).Elements...)
+	var sortErr object.Object
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+		result := less(sorted[i], sorted[j])
+		if isError(result) {
+			sortErr = result
+			return false
+		}
+		if result.Type() != object.BOOLEAN_OBJ {
+			sortErr = object.NewKindErrorf(object.TYPE_ERROR, "Comparator passed to sort must return BOOLEAN Got=%s",
+				result.Type())
+			return false
+		}
+		return result == TRUE
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+	return object.NewArray(sorted)
+}
+
+// compareNatural Orders integers by value and strings lexicographically
+func compareNatural(a, b object.Object) object.Object {
+	switch {
+	case a.Type() == object.INTEGER_OBJ && b.Type() == object.INTEGER_OBJ:
+		return nativeBoolTooBooleanObject(a.(*object.Integer).Value < b.(*object.Integer).Value)
+	case a.Type() == object.STRING_OBJ && b.Type() == object.STRING_OBJ:
+		return nativeBoolTooBooleanObject(stringValue(a) < stringValue(b))
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR, "Cannot sort %s and %s without a comparator",
+			a.Type(), b.Type())
+	}
+}
+
+// builtinZip Returns an array of [a, b] pairs of the elements at the same index
+// in two arrays, the result is as long as the shorter array
+func builtinZip(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("zip", args, object.ARRAY_OBJ, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	left := args[0].(*object.Array).Elements
+	right := args[1].(*object.Array).Elements
+	length := len(left)
+	if len(right) < length {
+		length = len(right)
+	}
+	pairs := make([]object.Object, 0, length)
+	for i := 0; i < length; i++ {
+		pairs = append(pairs, object.NewArray([]object.Object{left[i], right[i]}))
+	}
+	return object.NewArray(pairs)
+}
+
+// builtinKeys Returns the keys of a hash in insertion order
+func builtinKeys(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("keys", args, object.HASH_OBJ); err != nil {
+		return err
+	}
+	entries := args[0].(*object.Hash).Entries()
+	keys := make([]object.Object, 0, len(entries))
+	for _, pair := range entries {
+		keys = append(keys, pair.Key)
+	}
+	return object.NewArray(keys)
+}
+
+// builtinValues Returns the values of a hash in insertion order of their keys
+func builtinValues(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("values", args, object.HASH_OBJ); err != nil {
+		return err
+	}
+	entries := args[0].(*object.Hash).Entries()
+	values := make([]object.Object, 0, len(entries))
+	for _, pair := range entries {
+		values = append(values, pair.Value)
+	}
+	return object.NewArray(values)
+}
+
+// builtinContains Checks if a string contains a substring, an array contains
+// an element or a hash contains a key
+func builtinContains(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCount("contains", args, 2); err != nil {
+		return err
+	}
+	switch collection := args[0].(type) {
+	case *object.String:
+		if err := checkArgType("contains", args, 1, object.STRING_OBJ); err != nil {
+			return err
+		}
+		return nativeBoolTooBooleanObject(strings.Contains(collection.Value, stringValue(args[1])))
+	case *object.Array:
+		for _, element := range collection.Elements {
+			if objectsEqual(element, args[1]) {
+				return TRUE
+			}
+		}
+		return FALSE
+	case *object.Hash:
+		key, ok := args[1].(object.Hashable)
+		if !ok {
+			return object.NewKindErrorf(object.TYPE_ERROR, "Unusable as hash key: %s",
+				args[1].Type())
+		}
+		_, found := collection.Get(key)
+		return nativeBoolTooBooleanObject(found)
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR, "Argument 1 to contains not supported Got=%s",
+			collection.Type())
+	}
+}
+
+// objectsEqual Checks if two objects are equal, hashable objects, arrays
+// and hashes are compared by value and all other objects by identity
+func objectsEqual(a, b object.Object) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	if left, ok := a.(*object.Hash); ok {
+		right := b.(*object.Hash)
+		if len(left.Pairs) != len(right.Pairs) {
+			return false
+		}
+		for key, pair := range left.Pairs {
+			other, ok := right.Pairs[key]
+			if !ok || !objectsEqual(pair.Value, other.Value) {
+				return false
+			}
+		}
+		return true
+	}
+	if left, ok := a.(*object.Array); ok {
+		right := b.(*object.Array)
+		if len(left.Elements) != len(right.Elements) {
+			return false
+		}
+		for i := range left.Elements {
+			if !objectsEqual(left.Elements[i], right.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if left, ok := a.(object.Hashable); ok {
+		return left.HashKey() == b.(object.Hashable).HashKey()
+	}
+	return a == b
+}
+
+// checkCallbackArgs Checks the arguments of a builtin taking an array followed by a function
+func checkCallbackArgs(name string, args []object.Object) *object.Error {
+	if err := checkArgCount(name, args, 2); err != nil {
+		return err
+	}
+	if err := checkArgType(name, args, 0, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	return checkCallable(name, args, 1)
+}
+
+// checkCallable Checks that the argument at index i is a function or builtin
+func checkCallable(name string, args []object.Object, i int) *object.Error {
+	switch args[i].(type) {
+	case *object.Function, *object.Builtin:
+		return nil
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR, "Argument %d to %s must be FUNCTION Got=%s",
+			i+1, name, args[i].Type())
+	}
+}
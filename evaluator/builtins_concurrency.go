@@ -0,0 +1,150 @@
+package evaluator
+
+import (
+	"sync"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+// tasks Spawned tasks that have not completed
+var tasks sync.WaitGroup
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"spawn":   builtinSpawn,
+		"await":   builtinAwait,
+		"channel": builtinChannel,
+		"send":    builtinSend,
+		"recv":    builtinRecv,
+		"close":   builtinClose,
+		"select":  builtinSelect,
+	})
+}
+
+// builtinSpawn Calls a function with the remaining arguments in a goroutine
+// of its own and returns a task that can be awaited for its result. Raises
+// a RuntimeError while evaluation is observed by a Hook, which keeps track
+// of a single call stack
+func builtinSpawn(env *object.Environment, args ...object.Object) object.Object {
+	if hook != nil {
+		return object.NewKindErrorf(object.RUNTIME_ERROR,
+			"Tasks cannot be spawned while evaluation is observed, e.g. by a debugger or profiler")
+	}
+	if len(args) == 0 {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to spawn Expected=1+ Got=0")
+	}
+	fn := args[0]
+	if fn.Type() != object.FUNCTION_OBJ && fn.Type() != object.BUILTIN_OBJ {
+		return object.NewKindErrorf(object.TYPE_ERROR, "Argument 1 to spawn must be FUNCTION Got=%s", fn.Type())
+	}
+	fnArgs := make([]object.Object, len(args)-1)
+	copy(fnArgs, args[1:])
+	task := object.NewTask()
+	tasks.Add(1)
+	taskEnv := object.NewTaskEnvironment(env)
+	go func() {
+		defer tasks.Done()
+		task.Complete(callFunction(taskEnv, fn, fnArgs))
+	}()
+	return task
+}
+
+// builtinAwait Blocks until a task is complete and returns its result,
+// an error raised by the task is raised again by each await
+func builtinAwait(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("await", args, object.TASK_OBJ); err != nil {
+		return err
+	}
+	result := args[0].(*object.Task).Wait()
+	if err, ok := result.(*object.Error); ok {
+		return err.Copy()
+	}
+	return result
+}
+
+// builtinChannel Creates a channel buffering up to the supplied number of
+// values, without a capacity sends block until the value is received
+func builtinChannel(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("channel", args, 0, 1); err != nil {
+		return err
+	}
+	capacity := int64(0)
+	if len(args) == 1 {
+		if err := checkArgType("channel", args, 0, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+		capacity = args[0].(*object.Integer).Value
+	}
+	if capacity < 0 {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Capacity of channel must not be negative Got=%d", capacity)
+	}
+	if err := object.ReserveMemory(env, capacity*object.ELEMENT_SIZE); err != nil {
+		return err
+	}
+	return object.NewChannel(int(capacity))
+}
+
+// builtinSend Sends a value on a channel, blocking until it is received or buffered
+func builtinSend(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCount("send", args, 2); err != nil {
+		return err
+	}
+	if err := checkArgType("send", args, 0, object.CHANNEL_OBJ); err != nil {
+		return err
+	}
+	if err := args[0].(*object.Channel).Send(args[1]); err != nil {
+		return err
+	}
+	return NULL
+}
+
+// builtinRecv Receives a value from a channel, blocking until one is sent.
+// Returns null once the channel is closed and every value has been received
+func builtinRecv(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("recv", args, object.CHANNEL_OBJ); err != nil {
+		return err
+	}
+	value, ok := args[0].(*object.Channel).Receive()
+	if !ok {
+		return NULL
+	}
+	return value
+}
+
+// builtinClose Closes a channel, after which values can no longer be sent on it
+func builtinClose(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("close", args, object.CHANNEL_OBJ); err != nil {
+		return err
+	}
+	if err := args[0].(*object.Channel).Close(); err != nil {
+		return err
+	}
+	return NULL
+}
+
+// builtinSelect Receives from whichever of an array of channels first has a
+// value or is closed, returns an array holding the index of the channel and
+// the value received, which is null if the channel is closed
+func builtinSelect(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("select", args, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	elements := args[0].(*object.Array).Elements
+	if len(elements) == 0 {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Argument to select must not be empty")
+	}
+	channels := make([]*object.Channel, 0, len(elements))
+	for i, element := range elements {
+		ch, ok := element.(*object.Channel)
+		if !ok {
+			return object.NewKindErrorf(object.TYPE_ERROR, "Element %d of the argument to select must be CHANNEL Got=%s",
+				i, element.Type())
+		}
+		channels = append(channels, ch)
+	}
+	i, value, ok := object.Select(channels)
+	if !ok {
+		value = NULL
+	}
+	return object.NewArray([]object.Object{object.NewInteger(int64(i)), value})
+}
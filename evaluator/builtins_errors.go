@@ -0,0 +1,61 @@
+package evaluator
+
+import (
+	"github.com/CzarSimon/monkey/object"
+)
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"error":         builtinError,
+		"error_message": builtinErrorMessage,
+		"error_kind":    builtinErrorKind,
+		"error_stack":   builtinErrorStack,
+	})
+}
+
+// builtinError Creates an error value with a message and an optional kind that can be thrown
+func builtinError(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("error", args, 1, 2); err != nil {
+		return err
+	}
+	for i := range args {
+		if err := checkArgType("error", args, i, object.STRING_OBJ); err != nil {
+			return err
+		}
+	}
+	kind := object.THROWN_ERROR
+	if len(args) == 2 {
+		kind = object.ErrorKind(stringValue(args[1]))
+	}
+	return object.NewErrorValue(object.NewKindError(kind, stringValue(args[0])))
+}
+
+// builtinErrorMessage Returns the message of a caught error
+func builtinErrorMessage(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("error_message", args, object.ERROR_VALUE_OBJ); err != nil {
+		return err
+	}
+	return object.NewString(args[0].(*object.ErrorValue).Error.Message)
+}
+
+// builtinErrorKind Returns the kind of a caught error, e.g. TypeError
+func builtinErrorKind(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("error_kind", args, object.ERROR_VALUE_OBJ); err != nil {
+		return err
+	}
+	return object.NewString(string(args[0].(*object.ErrorValue).Error.Kind))
+}
+
+// builtinErrorStack Returns the frames executing when a caught error was raised as
+// "position in function" strings, starting with the frame that raised it
+func builtinErrorStack(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("error_stack", args, object.ERROR_VALUE_OBJ); err != nil {
+		return err
+	}
+	trace := args[0].(*object.ErrorValue).Error.Trace()
+	frames := make([]object.Object, 0, len(trace))
+	for i := len(trace) - 1; i >= 0; i-- {
+		frames = append(frames, object.NewString(trace[i].String()))
+	}
+	return object.NewArray(frames)
+}
@@ -0,0 +1,212 @@
+package evaluator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"split":     builtinSplit,
+		"join":      builtinJoin,
+		"trim":      builtinTrim,
+		"upper":     builtinUpper,
+		"lower":     builtinLower,
+		"replace":   builtinReplace,
+		"index_of":  builtinIndexOf,
+		"substr":    builtinSubstr,
+		"format":    builtinFormat,
+		"to_string": builtinToString,
+		"parse_int": builtinParseInt,
+	})
+}
+
+// builtinSplit Splits a string into an array of the substrings between separators,
+// an empty separator splits the string into its characters
+func builtinSplit(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("split", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	parts := strings.Split(stringValue(args[0]), stringValue(args[1]))
+	elements := make([]object.Object, 0, len(parts))
+	for _, part := range parts {
+		elements = append(elements, object.NewString(part))
+	}
+	return object.NewArray(elements)
+}
+
+// builtinJoin Concatenates an array of strings placing a separator between them
+func builtinJoin(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("join", args, object.ARRAY_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	elements := args[0].(*object.Array).Elements
+	parts := make([]string, 0, len(elements))
+	for i, element := range elements {
+		str, ok := element.(*object.String)
+		if !ok {
+			return object.NewKindErrorf(object.TYPE_ERROR, "Element %d of array passed to join must be STRING Got=%s",
+				i, element.Type())
+		}
+		parts = append(parts, str.Value)
+	}
+	return object.NewString(strings.Join(parts, stringValue(args[1])))
+}
+
+// builtinTrim Removes leading and trailing whitespace from a string
+func builtinTrim(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("trim", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return object.NewString(strings.TrimSpace(stringValue(args[0])))
+}
+
+// builtinUpper Converts a string to upper case
+func builtinUpper(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("upper", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return object.NewString(strings.ToUpper(stringValue(args[0])))
+}
+
+// builtinLower Converts a string to lower case
+func builtinLower(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("lower", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return object.NewString(strings.ToLower(stringValue(args[0])))
+}
+
+// builtinReplace Replaces all occurrences of a substring with another string
+func builtinReplace(env *object.Environment, args ...object.Object) object.Object {
+	err := checkArgs("replace", args, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ)
+	if err != nil {
+		return err
+	}
+	return object.NewString(strings.Replace(
+		stringValue(args[0]), stringValue(args[1]), stringValue(args[2]), -1))
+}
+
+// builtinIndexOf Returns the character index of the first occurrence
+// of a substring in a string or -1 if it is not present
+func builtinIndexOf(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("index_of", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	str, sub := stringValue(args[0]), stringValue(args[1])
+	index := strings.Index(str, sub)
+	if index < 0 {
+		return object.NewInteger(-1)
+	}
+	return object.NewInteger(int64(len([]rune(str[:index]))))
+}
+
+// builtinSubstr Returns the characters of a string starting at an index,
+// either up to the end of the string or limited to a length
+func builtinSubstr(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCountRange("substr", args, 2, 3); err != nil {
+		return err
+	}
+	types := []object.ObjectType{object.STRING_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ}
+	for i := range args {
+		if err := checkArgType("substr", args, i, types[i]); err != nil {
+			return err
+		}
+	}
+	runes := []rune(stringValue(args[0]))
+	start := args[1].(*object.Integer).Value
+	if start < 0 || start > int64(len(runes)) {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Start index out of range in substr Expected=0-%d Got=%d",
+			len(runes), start)
+	}
+	end := int64(len(runes))
+	if len(args) == 3 {
+		length := args[2].(*object.Integer).Value
+		if length < 0 || start+length > end {
+			return object.NewKindErrorf(object.VALUE_ERROR, "Length out of range in substr Expected=0-%d Got=%d",
+				end-start, length)
+		}
+		end = start + length
+	}
+	return object.NewString(string(runes[start:end]))
+}
+
+// builtinFormat Formats a string by replacing %d with an INTEGER argument,
+// %s with any argument and %% with a percent sign
+func builtinFormat(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) == 0 {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to format Expected=1+ Got=0")
+	}
+	if err := checkArgType("format", args, 0, object.STRING_OBJ); err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	format := []rune(stringValue(args[0]))
+	next := 1
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteRune(format[i])
+			continue
+		}
+		if i+1 == len(format) {
+			return object.NewKindErrorf(object.VALUE_ERROR, "Format string ends with an incomplete verb")
+		}
+		i++
+		verb := format[i]
+		if verb == '%' {
+			out.WriteRune('%')
+			continue
+		}
+		if verb != 'd' && verb != 's' {
+			return object.NewKindErrorf(object.VALUE_ERROR, "Unknown format verb %%%c", verb)
+		}
+		if next >= len(args) {
+			return object.NewKindErrorf(object.ARGUMENT_ERROR, "Missing argument for %%%c in format",
+				verb)
+		}
+		if verb == 'd' {
+			if err := checkArgType("format", args, next, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+		}
+		out.WriteString(args[next].Inspect())
+		next++
+	}
+	if next != len(args) {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Too many arguments to format Expected=%d Got=%d",
+			next-1, len(args)-1)
+	}
+	return object.NewString(out.String())
+}
+
+// builtinToString Returns the string representation of any object
+func builtinToString(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgCount("to_string", args, 1); err != nil {
+		return err
+	}
+	if str, ok := args[0].(*object.String); ok {
+		return str
+	}
+	return object.NewString(args[0].Inspect())
+}
+
+// builtinParseInt Parses a base 10 integer from a string
+func builtinParseInt(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("parse_int", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(stringValue(args[0])), 10, 64)
+	if err != nil {
+		return object.NewKindErrorf(object.VALUE_ERROR, "Could not parse %q as INTEGER",
+			stringValue(args[0]))
+	}
+	return object.NewInteger(value)
+}
+
+// stringValue Returns the value of an object known to be a String
+func stringValue(obj object.Object) string {
+	return obj.(*object.String).Value
+}
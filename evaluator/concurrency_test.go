@@ -0,0 +1,169 @@
+package evaluator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+func TestTasks(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"await(spawn(fn(x, y) { x * y }, 6, 7))", "42"},
+		{"let t = spawn(len, \"abc\"); await(t) + await(t)", "6"},
+		{"let base = 10; let tasks = map(range(50), fn(i) { spawn(fn() { let y = i * 2; y + base }) });\n" +
+			"reduce(map(tasks, fn(t) { await(t) }), fn(acc, x) { acc + x }, 0)", "2950"},
+		{"let fib = fn(n) { if (n < 2) { n } else { let a = spawn(fib, n - 1); fib(n - 2) + await(a) } }; fib(12)", "144"},
+		{"try { await(spawn(fn() { throw \"boom\" })) } catch (e) { error_message(e) }", "boom"},
+		{"let t = spawn(fn() { throw \"boom\" }); let a = try { await(t) } catch (e) { 1 };\n" +
+			"let b = try { await(t) } catch (e) { 2 }; a + b", "3"},
+		{"spawn(1)", "Argument 1 to spawn must be FUNCTION Got=INTEGER"},
+		{"spawn()", "Wrong number of arguments to spawn Expected=1+ Got=0"},
+		{"await(1)", "Argument 1 to await must be TASK Got=INTEGER"},
+	}
+	for i, test := range tests {
+		result := testEval(test.input)
+		got := result.Inspect()
+		if err, ok := result.(*object.Error); ok {
+			got = err.Message
+		}
+		if got != test.expected {
+			t.Errorf("%d - Wrong result Expected=%s Got=%s", i, test.expected, got)
+		}
+	}
+}
+
+func TestChannels(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let ch = channel(2); send(ch, 1); send(ch, 2); recv(ch) + recv(ch)", "3"},
+		{"let ch = channel(); let t = spawn(send, ch, \"hi\"); let x = recv(ch); await(t); x", "hi"},
+		{"let ch = channel(1); send(ch, 1); close(ch); [recv(ch), recv(ch), recv(ch)]", "[1, null, null]"},
+		{"let produce = fn(ch, n) { if (n > 0) { send(ch, n); produce(ch, n - 1) } else { close(ch) } };\n" +
+			"let consume = fn(ch, sum) { let x = recv(ch); if (x) { consume(ch, sum + x) } else { sum } };\n" +
+			"let ch = channel(); let t = spawn(produce, ch, 100); let sum = consume(ch, 0); await(t); sum", "5050"},
+		{"let results = channel(10); let tasks = map(range(10), fn(i) { spawn(fn() { send(results, i * i) }) });\n" +
+			"map(tasks, fn(t) { await(t) }); reduce(map(range(10), fn(i) { recv(results) }), fn(acc, x) { acc + x }, 0)", "285"},
+		{"let a = channel(1); let b = channel(1); send(b, \"b\"); select([a, b])", "[1, b]"},
+		{"let a = channel(); let b = channel(); close(a); select([b, a])", "[1, null]"},
+		{"let a = channel(); let b = channel(); let t = spawn(send, a, 7); let x = select([a, b]); await(t); x", "[0, 7]"},
+		{"let ch = channel(); close(ch); try { send(ch, 1) } catch (e) { error_message(e) }", "Send on a closed channel"},
+		{"let ch = channel(); close(ch); try { close(ch) } catch (e) { error_message(e) }", "Channel is already closed"},
+		{"channel(-1)", "Capacity of channel must not be negative Got=-1"},
+		{"select([])", "Argument to select must not be empty"},
+		{"select([channel(), 1])", "Element 1 of the argument to select must be CHANNEL Got=INTEGER"},
+		{"channel(3)", "<channel 3>"},
+	}
+	for i, test := range tests {
+		result := testEval(test.input)
+		got := result.Inspect()
+		if err, ok := result.(*object.Error); ok {
+			got = err.Message
+		}
+		if got != test.expected {
+			t.Errorf("%d - Wrong result Expected=%s Got=%s", i, test.expected, got)
+		}
+	}
+}
+
+func TestBlockedSendIsWokenByClose(t *testing.T) {
+	input := "let ch = channel(); let t = spawn(send, ch, 1); close(ch);\n" +
+		"try { await(t) } catch (e) { error_kind(e) }"
+	if result := testEval(input); result.Inspect() != "ValueError" {
+		t.Errorf("Expected the blocked send to raise a ValueError Got=%s", result.Inspect())
+	}
+}
+
+func TestConcurrentOutput(t *testing.T) {
+	var out bytes.Buffer
+	previous := Output
+	Output = &out
+	defer func() { Output = previous }()
+	testEval("let tasks = map(range(20), fn(i) { spawn(puts, i) }); map(tasks, fn(t) { await(t) })")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	expected := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		expected = append(expected, fmt.Sprint(i))
+	}
+	sort.Strings(expected)
+	if strings.Join(lines, ",") != strings.Join(expected, ",") {
+		t.Errorf("Wrong output Got=%q", out.String())
+	}
+}
+
+func TestConcurrentMemory(t *testing.T) {
+	input := "let tasks = map(range(20), fn(i) { spawn(fn() { len(range(100)) }) });\n" +
+		"reduce(map(tasks, fn(t) { await(t) }), fn(acc, x) { acc + x }, 0)"
+	result, memory := evalWithMemory(input, 0)
+	if result.Inspect() != "2000" {
+		t.Fatalf("Wrong result Expected=2000 Got=%s", result.Inspect())
+	}
+	if memory.Allocated() == 0 {
+		t.Errorf("Expected the allocations of the spawned tasks to be accounted")
+	}
+}
+
+func TestSpawnIsRejectedWhileObserved(t *testing.T) {
+	h := &countingHook{nodes: make(map[string]int)}
+	defer SetHook(SetHook(h))
+	result := testEval("try { spawn(fn() { 1 }) } catch (e) { error_kind(e) }")
+	if result.Inspect() != "RuntimeError" {
+		t.Errorf("Expected spawn to raise a RuntimeError Got=%s", result.Inspect())
+	}
+}
+
+func TestSetHookWaitsForTasks(t *testing.T) {
+	result := testEval("let ch = channel(); spawn(recv, ch); ch")
+	ch, ok := result.(*object.Channel)
+	if !ok {
+		t.Fatalf("Expected a channel Got=%s", result.Inspect())
+	}
+	set := make(chan Hook)
+	go func() {
+		set <- SetHook(&countingHook{nodes: make(map[string]int)})
+	}()
+	select {
+	case <-set:
+		t.Fatalf("Expected SetHook to wait for the running task")
+	case <-time.After(20 * time.Millisecond):
+	}
+	ch.Send(object.NewInteger(1))
+	SetHook(<-set)
+}
+
+func TestConcurrentEnvironment(t *testing.T) {
+	env := object.NewEnvironment()
+	inner := object.NewEnclosedEnvironment(env)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				name := fmt.Sprintf("x%d", j%10)
+				env.Set(name, object.NewInteger(int64(i)))
+				inner.Declare(fmt.Sprintf("y%d_%d", i, j), object.NewInteger(int64(j)), false)
+				inner.Get(name)
+				env.Export(name)
+				env.IsExported(name)
+				inner.Names()
+				env.Bindings()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if names := inner.Names(); len(names) != 1010 {
+		t.Errorf("Expected 1010 names Got=%d", len(names))
+	}
+}
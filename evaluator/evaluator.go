@@ -3,6 +3,7 @@ package evaluator
 import (
 	"github.com/CzarSimon/monkey/ast"
 	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
 )
 
 var (
@@ -12,8 +13,28 @@ var (
 )
 
 // Eval Evaluates a part of an AST from the supplied node downwards
-// and returns a resulting object.Object
+// and returns a resulting object.Object. Errors raised while evaluating
+// the node are given its position unless raised by a node below it
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	beforeNode(node)
+	result := evalNode(node, env)
+	if !isError(result) {
+		if createsValue(node) {
+			object.Allocate(env, result)
+		}
+		if err := object.CheckMemory(env); err != nil {
+			result = err
+		}
+	}
+	if err, ok := result.(*object.Error); ok && !err.Position.IsValid() {
+		raiseAt(err, node.Pos(), env)
+	}
+	afterNode(node, result)
+	return result
+}
+
+// evalNode Evaluates a node based on its type
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node.Statements, env)
@@ -23,6 +44,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return object.NewInteger(node.Value)
 	case *ast.Boolean:
 		return nativeBoolTooBooleanObject(node.Value)
+	case *ast.StringLiteral:
+		return object.NewString(node.Value)
 	case *ast.PrefixExpression:
 		rightArg := Eval(node.Right, env)
 		if isError(rightArg) {
@@ -49,17 +72,38 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return value
 		}
 		return object.NewReturnValue(value)
+	case *ast.FunctionStatement:
+		fn := object.NewFunction(node.Function, env)
+		object.Allocate(env, fn)
+		if _, err := env.Declare(node.Name.Value, fn, false); err != nil {
+			return err
+		}
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	case *ast.LetStatement:
-		value := Eval(node.Value, env)
+		return evalLetStatement(node, env)
+	case *ast.ExportStatement:
+		value := Eval(node.Statement, env)
 		if isError(value) {
 			return value
 		}
-		env.Set(node.Name.Value, value)
+		for _, name := range node.Statement.Names() {
+			env.Export(name.Value)
+		}
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.FunctionLiteral:
 		return object.NewFunction(node, env)
+	case *ast.MacroLiteral:
+		return object.NewErrorf("Macros can only be defined by top level let statements")
 	case *ast.CallExpression:
+		if isCallTo(node, QUOTE) {
+			return evalQuote(node, env)
+		}
 		fn := Eval(node.Function, env)
 		if isError(fn) {
 			return fn
@@ -68,16 +112,61 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(fn, args)
+		return applyObserved(env, fn, args, node.Pos())
+	case *ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return object.NewArray(elements)
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+	case *ast.IndexExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case *ast.ImportExpression:
+		return evalImportExpression(node, env)
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
 	}
 	return nil
 }
 
+// createsValue Checks if evaluating a node creates the value it results in,
+// which is then accounted to the Memory of the evaluation
+func createsValue(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.IntegerLiteral, *ast.StringLiteral, *ast.PrefixExpression, *ast.InfixExpression,
+		*ast.ArrayLiteral, *ast.HashLiteral, *ast.FunctionLiteral:
+		return true
+	}
+	return false
+}
+
 // evalProgram Evaluates a series of supplied statements and
 // and returns a resulting object.Object
 func evalProgram(statements []ast.Statement, env *object.Environment) object.Object {
+	object.EnterEnvironment(env)
+	defer object.LeaveEnvironment(env)
+	if err := hoistFunctions(statements, env); err != nil {
+		return err
+	}
 	var result object.Object
 	for _, stmt := range statements {
+		if isFunctionStatement(stmt) {
+			result = nil
+			continue
+		}
+		if err := beforeStatement(stmt, env); err != nil {
+			return err
+		}
 		result = Eval(stmt, env)
 		switch res := result.(type) {
 		case *object.ReturnValue:
@@ -89,6 +178,28 @@ func evalProgram(statements []ast.Statement, env *object.Environment) object.Obj
 	return result
 }
 
+// hoistFunctions Binds the functions declared by the function statements
+// of a block before any of its statements are evaluated, allowing them to
+// be called before their declaration and to call each other
+func hoistFunctions(statements []ast.Statement, env *object.Environment) object.Object {
+	for _, stmt := range statements {
+		if !isFunctionStatement(stmt) {
+			continue
+		}
+		if result := Eval(stmt, env); isError(result) {
+			return result
+		}
+	}
+	return nil
+}
+
+// isFunctionStatement Checks if a statement is a FunctionStatement, these are
+// evaluated when hoisted and skipped when reached in the block
+func isFunctionStatement(stmt ast.Statement) bool {
+	_, ok := stmt.(*ast.FunctionStatement)
+	return ok
+}
+
 // evalExpressions Evaluates an supplied array of expressions and
 // returns an object.Object slice
 func evalExpressions(expressions []ast.Expression, env *object.Environment) []object.Object {
@@ -103,20 +214,59 @@ func evalExpressions(expressions []ast.Expression, env *object.Environment) []ob
 	return result
 }
 
-// applyFunction Applies a series of evaluated arguments on a function
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// raiseAt Sets the position an error was raised at and the frames of the calls
+// being evaluated by the task evaluating code in env when it was raised
+func raiseAt(err *object.Error, pos token.Position, env *object.Environment) {
+	err.Position = pos
+	err.Stack = env.CallStack().Frames()
+}
+
+// applyFunction Applies a series of evaluated arguments on a function called at
+// a position from env, the call is on the call stack until it returns
+func applyFunction(env *object.Environment, fn object.Object, args []object.Object, pos token.Position) object.Object {
+	stack := env.CallStack()
+	stack.Push(object.Frame{Function: FunctionName(fn), Position: pos})
+	defer stack.Pop()
+	if builtin, ok := fn.(*object.Builtin); ok {
+		result := builtin.Fn(env, args...)
+		object.AllocateAll(env, result)
+		return result
+	}
 	function, ok := fn.(*object.Function)
 	if !ok {
-		return object.NewErrorf("%s not a function", fn.Type())
+		return object.NewKindErrorf(object.TYPE_ERROR, "%s not a function", fn.Type())
+	}
+	if len(args) != len(function.Parameters) {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments Expected=%d Got=%d",
+			len(function.Parameters), len(args))
+	}
+	functionEnv := extendFunctionEnv(function, args, env)
+	object.EnterEnvironment(functionEnv)
+	evaluated := unwrappReturnValue(Eval(function.Body, functionEnv))
+	object.LeaveEnvironment(functionEnv)
+	if evaluated == nil {
+		return NULL
 	}
-	functionEnv := extendFunctionEnv(function, args)
-	evaluated := Eval(function.Body, functionEnv)
-	return unwrappReturnValue(evaluated)
+	return evaluated
+}
+
+// FunctionName Returns the name of a called function used in stack frames
+func FunctionName(fn object.Object) string {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if fn.Name != "" {
+			return fn.Name
+		}
+	case *object.Builtin:
+		return fn.Name
+	}
+	return object.ANONYMOUS_FRAME
 }
 
 // extendFunctionEnv Adds evaluated objects to a temporary environment
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(fn.Env)
+// evaluated by the task evaluating code in the calling environment
+func extendFunctionEnv(fn *object.Function, args []object.Object, caller *object.Environment) *object.Environment {
+	env := object.NewCallEnvironment(fn.Env, caller)
 	for i, param := range fn.Parameters {
 		env.Set(param.Value, args[i])
 	}
@@ -134,8 +284,18 @@ func unwrappReturnValue(obj object.Object) object.Object {
 // evalBlockStatement Evaluates a series of supplied statements
 // and returns a result
 func evalBlockStatement(statements []ast.Statement, env *object.Environment) object.Object {
+	if err := hoistFunctions(statements, env); err != nil {
+		return err
+	}
 	var result object.Object
 	for _, stmt := range statements {
+		if isFunctionStatement(stmt) {
+			result = nil
+			continue
+		}
+		if err := beforeStatement(stmt, env); err != nil {
+			return err
+		}
 		result = Eval(stmt, env)
 		if blockShouldReturn(result) {
 			return result
@@ -144,13 +304,17 @@ func evalBlockStatement(statements []ast.Statement, env *object.Environment) obj
 	return result
 }
 
-// evalIdentifier Evaluates an the value of an Identifier bound to the environment
+// evalIdentifier Evaluates an the value of an Identifier bound to the environment,
+// falling back to the builtin functions for unbound names
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
 	value, err := env.Get(node.Value)
-	if err != nil {
-		return err
+	if err == nil {
+		return value
 	}
-	return value
+	if builtin, ok := builtins[node.Value]; ok && env.AllowsBuiltin(node.Value) {
+		return builtin
+	}
+	return err
 }
 
 func blockShouldReturn(result object.Object) bool {
@@ -168,7 +332,8 @@ func evalPrefixExpression(operator string, argument object.Object) object.Object
 	case "-":
 		return evalMinusPrefixOperatorExpression(argument)
 	default:
-		return object.NewErrorf("Unkown operator: %s%s", operator, argument.Type())
+		return object.NewKindErrorf(object.TYPE_ERROR, "Unkown operator: %s%s",
+			operator, argument.Type())
 	}
 }
 
@@ -191,7 +356,7 @@ func evalNotOperatorExpression(argument object.Object) object.Object {
 // with -1 and returns the result
 func evalMinusPrefixOperatorExpression(argument object.Object) object.Object {
 	if argument.Type() != object.INTEGER_OBJ {
-		return object.NewErrorf("Unknown operator: -%s", argument.Type())
+		return object.NewKindErrorf(object.TYPE_ERROR, "Unknown operator: -%s", argument.Type())
 	}
 	value := argument.(*object.Integer).Value
 	return object.NewInteger(-value)
@@ -202,16 +367,18 @@ func evalMinusPrefixOperatorExpression(argument object.Object) object.Object {
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
 	case left.Type() != right.Type():
-		return object.NewErrorf("Type missmatch: %s %s %s",
+		return object.NewKindErrorf(object.TYPE_ERROR, "Type missmatch: %s %s %s",
 			left.Type(), operator, right.Type())
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left, right)
 	case operator == "==":
 		return nativeBoolTooBooleanObject(left == right)
 	case operator == "!=":
 		return nativeBoolTooBooleanObject(left != right)
 	default:
-		return object.NewErrorf("Unknown operator: %s %s %s",
+		return object.NewKindErrorf(object.TYPE_ERROR, "Unknown operator: %s %s %s",
 			left.Type(), operator, right.Type())
 	}
 }
@@ -229,6 +396,9 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case "*":
 		return object.NewInteger(leftValue * rightValue)
 	case "/":
+		if rightValue == 0 {
+			return object.NewKindErrorf(object.ARITHMETIC_ERROR, "Division by zero")
+		}
 		return object.NewInteger(leftValue / rightValue)
 	case "<":
 		return nativeBoolTooBooleanObject(leftValue < rightValue)
@@ -239,18 +409,133 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case "!=":
 		return nativeBoolTooBooleanObject(leftValue != rightValue)
 	default:
-		return object.NewErrorf("Unknown operator: %s %s %s",
+		return object.NewKindErrorf(object.TYPE_ERROR, "Unknown operator: %s %s %s",
 			left.Type(), operator, right.Type())
 	}
 }
 
+// evalStringInfixExpression Returns the result of concatenating or comparing two strings
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := left.(*object.String).Value
+	rightValue := right.(*object.String).Value
+	switch operator {
+	case "+":
+		return object.NewString(leftValue + rightValue)
+	case "==":
+		return nativeBoolTooBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolTooBooleanObject(leftValue != rightValue)
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR, "Unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+// evalIndexExpression Returns the element of an array or hash at an index
+func evalIndexExpression(left, index object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		return evalArrayIndexExpression(left, index)
+	case *object.Hash:
+		return evalHashIndexExpression(left, index)
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR, "Index operator not supported: %s",
+			left.Type())
+	}
+}
+
+// evalArrayIndexExpression Returns the element of an array at an index,
+// NULL is returned for indexes out of range
+func evalArrayIndexExpression(array *object.Array, index object.Object) object.Object {
+	i, ok := index.(*object.Integer)
+	if !ok {
+		return object.NewKindErrorf(object.TYPE_ERROR, "Array index must be INTEGER Got=%s",
+			index.Type())
+	}
+	if i.Value < 0 || i.Value >= int64(len(array.Elements)) {
+		return NULL
+	}
+	return array.Elements[i.Value]
+}
+
+// evalHashIndexExpression Returns the value stored under a key in a hash
+func evalHashIndexExpression(hash *object.Hash, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return object.NewKindErrorf(object.TYPE_ERROR, "Unusable as hash key: %s", index.Type())
+	}
+	value, ok := hash.Get(key)
+	if !ok {
+		return object.NewKindErrorf(object.KEY_ERROR, "Key not found: %s", index.Inspect())
+	}
+	return value
+}
+
+// evalHashLiteral Evaluates the keys and values of a HashLiteral in source order
+func evalHashLiteral(hashLit *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+	for _, pair := range hashLit.Pairs {
+		key := Eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return object.NewKindErrorf(object.TYPE_ERROR, "Unusable as hash key: %s", key.Type())
+		}
+		value := Eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+		hash.Set(hashKey, value)
+	}
+	return hash
+}
+
+// evalImportExpression Evaluates the path of an import and loads the module it refers to
+func evalImportExpression(importExpr *ast.ImportExpression, env *object.Environment) object.Object {
+	if env.Sandboxed() {
+		return object.NewKindError(object.PERMISSION_ERROR, "Modules cannot be imported in a sandbox")
+	}
+	path := Eval(importExpr.Path, env)
+	if isError(path) {
+		return path
+	}
+	str, ok := path.(*object.String)
+	if !ok {
+		return object.NewKindErrorf(object.TYPE_ERROR, "Import path must be a STRING Got=%s",
+			path.Type())
+	}
+	return Modules.Import(str.Value, importExpr.Pos().Filename, env)
+}
+
+// evalMemberExpression Looks up an exported binding of a module
+func evalMemberExpression(member *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := Eval(member.Object, env)
+	if isError(obj) {
+		return obj
+	}
+	module, ok := obj.(*object.Module)
+	if !ok {
+		return object.NewKindErrorf(object.TYPE_ERROR, "Cannot access member %s of %s",
+			member.Property.Value, obj.Type())
+	}
+	value, err := module.Member(member.Property.Value)
+	if err != nil {
+		return err
+	}
+	return value
+}
+
 // evalIfExpression Selects one branch of an IFExpression to evaluate
 func evalIfExpression(ifExpr *ast.IFExpression, env *object.Environment) object.Object {
 	condition := Eval(ifExpr.Condition, env)
 	if isError(condition) {
 		return condition
 	}
-	if isTruthy(condition) {
+	truthy := isTruthy(condition)
+	branchTaken(ifExpr, truthy)
+	if truthy {
 		return Eval(ifExpr.Consequence, env)
 	} else if ifExpr.Alternative != nil {
 		return Eval(ifExpr.Alternative, env)
@@ -258,6 +543,48 @@ func evalIfExpression(ifExpr *ast.IFExpression, env *object.Environment) object.
 	return NULL
 }
 
+// evalThrowStatement Raises an error with a STRING message or rethrows a caught error
+func evalThrowStatement(throwStmt *ast.ThrowStatement, env *object.Environment) object.Object {
+	value := Eval(throwStmt.Value, env)
+	if isError(value) {
+		return value
+	}
+	switch value := value.(type) {
+	case *object.String:
+		return object.NewKindError(object.THROWN_ERROR, value.Value)
+	case *object.ErrorValue:
+		return value.Error.Copy()
+	default:
+		return object.NewKindErrorf(object.TYPE_ERROR,
+			"Cannot throw %s, expected STRING or ERROR_VALUE", value.Type())
+	}
+}
+
+// evalTryExpression Evaluates the body of a TryExpression, if an error is raised it is
+// bound to the catch parameter and the catch block evaluated instead. The finally
+// block is evaluated last and only replaces the result if it returns or raises. Errors
+// that are not Catchable are neither caught nor followed by the finally block
+func evalTryExpression(tryExpr *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(tryExpr.Body, env)
+	if err, ok := result.(*object.Error); ok && err.Catchable() {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		catchEnv.Set(tryExpr.CatchParam.Value, object.NewErrorValue(err))
+		result = Eval(tryExpr.Catch, catchEnv)
+	}
+	if err, ok := result.(*object.Error); ok && !err.Catchable() {
+		return err
+	}
+	if tryExpr.Finally != nil {
+		if finalResult := Eval(tryExpr.Finally, env); blockShouldReturn(finalResult) {
+			return finalResult
+		}
+	}
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
 // isTruthy Checks if a supplied object is is truty
 func isTruthy(obj object.Object) bool {
 	switch obj {
@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/CzarSimon/monkey/lexer"
@@ -132,7 +133,7 @@ func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 		return false
 	}
 	if res.Value != expected {
-		t.Errorf("Wrong res.Value Expected=%d Got=%d", expected, res.Value)
+		t.Errorf("Wrong res.Value Expected=%t Got=%t", expected, res.Value)
 		return false
 	}
 	return true
@@ -237,6 +238,90 @@ func TestLetStatement(t *testing.T) {
 	}
 }
 
+func TestConstBindings(t *testing.T) {
+	tests := []testStruct{
+		{"const x = 5; x", 5},
+		{"const x = 5; let x = 6; x", "Cannot redeclare constant x"},
+		{"const x = 5; const x = 6; x", "Cannot redeclare constant x"},
+		{"let x = 5; const x = 6; x", "Cannot redeclare x"},
+		{"let x = 5; let x = 6; x", 6},
+		{"const x = 5; let f = fn() { let x = 6; x }; f() + x", 11},
+		{"const x = 5; let f = fn(x) { x }; f(1)", 1},
+		{"const f = 1; fn f() { 2 }; f", "Cannot redeclare f"},
+		{"fn f() { 2 }; const f = 1; f", "Cannot redeclare f"},
+		{"const x = 1; try { let x = 2; } catch (e) { error_kind(e) }", "AssignmentError"},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if str, ok := evaluated.(*object.String); ok {
+				if str.Value != expected {
+					t.Errorf("%d. - Wrong string Expected=%s Got=%s", i, expected, str.Value)
+				}
+				continue
+			}
+			testErrorMessage(t, i, evaluated, expected)
+		}
+	}
+	env := object.NewNoRedeclareEnvironment()
+	program := parser.New(lexer.New("let x = 1; let f = fn() { let y = 1; let y = 2; y }; f(); let x = 2;")).ParseProgram()
+	testErrorMessage(t, 0, Eval(program, env), "Cannot redeclare x")
+}
+
+func TestDestructuringLet(t *testing.T) {
+	tests := []testStruct{
+		{"let [a, b] = [1, 2]; a + b", 3},
+		{"let [a, _, c] = [1, 2, 3]; a + c", 4},
+		{"let [a, ...rest] = [1, 2, 3]; a + len(rest) * 10", 21},
+		{"let [a, ...rest] = [1]; len(rest)", 0},
+		{"let xs = [1, 2, 3]; let [_, ...rest] = xs; rest[0] + rest[1]", 5},
+		{`let {name, age} = {"name": "x", "age": 4, "extra": 1}; age`, 4},
+		{`let f = fn() { let {v} = {"v": 7}; v }; f()`, 7},
+		{`const [c] = [1]; let c = 2;`, "Cannot redeclare constant c"},
+		{"let [a, b] = [1];", "Array pattern expects 2 elements Got=1"},
+		{"let [a] = [1, 2];", "Array pattern expects 1 elements Got=2"},
+		{"let [a, b, ...c] = [1];", "Array pattern expects at least 2 elements Got=1"},
+		{"let [a] = 1;", "Cannot destructure INTEGER with an array pattern"},
+		{`let {a} = [1];`, "Cannot destructure ARRAY with a hash pattern"},
+		{`let {a, b} = {"a": 1};`, "Key not found: b"},
+		{`try { let {a} = {}; } catch (e) { error_kind(e) }`, "KeyError"},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if str, ok := evaluated.(*object.String); ok {
+				if str.Value != expected {
+					t.Errorf("%d. - Wrong string Expected=%s Got=%s", i, expected, str.Value)
+				}
+				continue
+			}
+			testErrorMessage(t, i, evaluated, expected)
+		}
+	}
+	positions := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1;\nlet [a, b] = [x];", "2:5"},
+		{"let {a,\n  b} = {\"a\": 1};", "2:3"},
+	}
+	for i, test := range positions {
+		err, ok := testEval(test.input).(*object.Error)
+		if !ok {
+			t.Fatalf("%d. - Expected error", i)
+		}
+		if err.Position.String() != test.expected {
+			t.Errorf("%d. - Wrong error position Expected=%s Got=%s", i, test.expected, err.Position)
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fn(x) { x + 2; }"
 	evaluated := testEval(input)
@@ -256,6 +341,48 @@ func TestFunctionObject(t *testing.T) {
 	}
 }
 
+func TestNamedFunctions(t *testing.T) {
+	inspectTests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(x) { x }", "fn(x)"},
+		{"let add = fn(x, y) { x + y }; add", "fn add(x, y)"},
+		{"fn greet() { 1 }; greet", "fn greet()"},
+		{"let f = fn() { fn(a) { a } }; f()", "fn(a)"},
+		{"let g = fn() { 1 }; let h = g; h", "fn g()"},
+	}
+	for i, test := range inspectTests {
+		evaluated := testEval(test.input)
+		if evaluated.Inspect() != test.expected {
+			t.Errorf("%d. - Wrong Inspect() Expected=%s Got=%s", i, test.expected, evaluated.Inspect())
+		}
+	}
+	tests := []testStruct{
+		{"fn double(x) { x * 2 }; double(4)", 8},
+		{"double(4); fn double(x) { x * 2 }", nil},
+		{"let y = double(4); fn double(x) { x * 2 }; y", 8},
+		{`fn isEven(n) { if (n == 0) { true } else { isOdd(n - 1) } }
+		fn isOdd(n) { if (n == 0) { false } else { isEven(n - 1) } }
+		if (isEven(10)) { 1 } else { 0 }`, 1},
+		{`let isEven = fn(n) { if (n == 0) { true } else { isOdd(n - 1) } };
+		let isOdd = fn(n) { if (n == 0) { false } else { isEven(n - 1) } };
+		if (isOdd(7)) { 1 } else { 0 }`, 1},
+		{`let f = fn() { let r = g(); fn g() { 5 }; r }; f()`, 5},
+		{`let f = g; fn g() { 1 }; if (f == g) { 1 } else { 0 }`, 1},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		if expected, ok := test.expected.(int); ok {
+			if !testIntegerObject(t, evaluated, int64(expected)) {
+				t.Errorf("%d. - Test failed", i)
+			}
+		} else if evaluated != nil {
+			t.Errorf("%d. - Expected nil Got=%+v", i, evaluated)
+		}
+	}
+}
+
 func TestFunctionApplication(t *testing.T) {
 	tests := []testStruct{
 		{"let I = fn(x) { x; }; I(5);", 5},
@@ -273,3 +400,445 @@ func TestFunctionApplication(t *testing.T) {
 		}
 	}
 }
+
+func TestClosures(t *testing.T) {
+	tests := []testStruct{
+		{"let limit = 10; let above = fn(n) { n > limit }; if (above(11)) { 1 } else { 0 }", 1},
+		{"let adder = fn(x) { fn(y) { x + y } }; let addTwo = adder(2); addTwo(3)", 5},
+		{"let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } }; fact(5)", 120},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		expectedInt := test.expected.(int)
+		if !testIntegerObject(t, evaluated, int64(expectedInt)) {
+			t.Errorf("%d. - Test failed", i)
+		}
+	}
+}
+
+func TestStringExpressions(t *testing.T) {
+	tests := []testStruct{
+		{`"hello"`, "hello"},
+		{`"hello" + " " + "world"`, "hello world"},
+		{`let greet = fn(name) { "hi " + name }; greet("monkey")`, "hi monkey"},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("%d. - Object is not a String Got=%T (%+v)", i, evaluated, evaluated)
+		}
+		if str.Value != test.expected.(string) {
+			t.Errorf("%d. - Wrong str.Value Expected=%q Got=%q", i, test.expected, str.Value)
+		}
+	}
+	booleanTests := []testStruct{
+		{`"a" == "a"`, true},
+		{`"a" == "b"`, false},
+		{`"a" != "b"`, true},
+	}
+	for _, test := range booleanTests {
+		testBooleanObject(t, testEval(test.input), test.expected.(bool))
+	}
+	testErrorMessage(t, 0, testEval(`"a" - "b"`), "Unknown operator: STRING - STRING")
+}
+
+func testErrorMessage(t *testing.T, i int, obj object.Object, expected string) bool {
+	err, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("%d. - Expected type *object.Error Got=%T(%+v)", i, obj, obj)
+		return false
+	}
+	if err.Message != expected {
+		t.Errorf("%d. - Wrong error message: Expected=%s Got=%s", i, expected, err.Message)
+		return false
+	}
+	return true
+}
+
+func TestArrayExpressions(t *testing.T) {
+	evaluated := testEval("[1, 2 * 2, 3 + 3]")
+	array, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("Object is not an Array Got=%T (%+v)", evaluated, evaluated)
+	}
+	if len(array.Elements) != 3 {
+		t.Fatalf("Wrong number of elements Expected=3 Got=%d", len(array.Elements))
+	}
+	testIntegerObject(t, array.Elements[0], 1)
+	testIntegerObject(t, array.Elements[1], 4)
+	testIntegerObject(t, array.Elements[2], 6)
+	tests := []testStruct{
+		{"[1, 2, 3][0]", 1},
+		{"[1, 2, 3][1 + 1]", 3},
+		{"let i = 0; [1][i]", 1},
+		{"let xs = [1, 2, 3]; xs[0] + xs[1] + xs[2]", 6},
+		{"[1, 2, 3][3]", nil},
+		{"[1, 2, 3][-1]", nil},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		if expected, ok := test.expected.(int); ok {
+			if !testIntegerObject(t, evaluated, int64(expected)) {
+				t.Errorf("%d. - Test failed", i)
+			}
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+	testErrorMessage(t, 0, testEval(`[1]["a"]`), "Array index must be INTEGER Got=STRING")
+	testErrorMessage(t, 1, testEval(`1[0]`), "Index operator not supported: INTEGER")
+}
+
+func TestHashExpressions(t *testing.T) {
+	input := `let two = "two";
+	{"one": 10 - 9, two: 1 + 1, "thr" + "ee": 6 / 2, 4: 4, true: 5, false: 6}`
+	evaluated := testEval(input)
+	hash, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Object is not a Hash Got=%T (%+v)", evaluated, evaluated)
+	}
+	expected := "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}"
+	if hash.Inspect() != expected {
+		t.Errorf("Wrong hash Expected=%s Got=%s", expected, hash.Inspect())
+	}
+	tests := []testStruct{
+		{`{"foo": 5}["foo"]`, 5},
+		{`let key = "foo"; {"foo": 5}[key]`, 5},
+		{`{5: 5}[5]`, 5},
+		{`{true: 5}[true]`, 5},
+		{`{"a": 1, "a": 2}["a"]`, 2},
+		{`len({"a": 1, "b": 2})`, 2},
+		{`{"foo": 5}["bar"]`, "Key not found: bar"},
+		{`{"foo": 5}[fn(x) { x }]`, "Unusable as hash key: FUNCTION"},
+		{`{[1]: 5}`, "Unusable as hash key: ARRAY"},
+	}
+	testBuiltinResults(t, tests)
+}
+
+func TestBuiltinFunctions(t *testing.T) {
+	tests := []testStruct{
+		{`len("")`, 0},
+		{`len("four")`, 4},
+		{`len("håll")`, 4},
+		{`len([1, 2, 3])`, 3},
+		{`len(1)`, "Argument 1 to len not supported Got=INTEGER"},
+		{`len("a", "b")`, "Wrong number of arguments to len Expected=1 Got=2"},
+		{`puts()`, nil},
+		{`let len = fn(x) { 42 }; len("a")`, 42},
+		{`let f = fn(x) { x }; f(1, 2)`, "Wrong number of arguments Expected=1 Got=2"},
+	}
+	testBuiltinResults(t, tests)
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []testStruct{
+		{`split("a,b,c", ",")`, []string{"a", "b", "c"}},
+		{`split("abc", "")`, []string{"a", "b", "c"}},
+		{`split("abc", 1)`, "Argument 2 to split must be STRING Got=INTEGER"},
+		{`join(["a", "b"], "-")`, "a-b"},
+		{`join([], "-")`, ""},
+		{`join(["a", 1], "-")`, "Element 1 of array passed to join must be STRING Got=INTEGER"},
+		{`trim("  hi \n")`, "hi"},
+		{`upper("abc")`, "ABC"},
+		{`lower("ABC")`, "abc"},
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`replace("a")`, "Wrong number of arguments to replace Expected=3 Got=1"},
+		{`index_of("monkey", "key")`, 3},
+		{`index_of("håll", "l")`, 2},
+		{`index_of("monkey", "ape")`, -1},
+		{`substr("monkey", 3)`, "key"},
+		{`substr("monkey", 0, 3)`, "mon"},
+		{`substr("håll", 1, 1)`, "å"},
+		{`substr("monkey", 7)`, "Start index out of range in substr Expected=0-6 Got=7"},
+		{`substr("monkey", 4, 3)`, "Length out of range in substr Expected=0-2 Got=3"},
+		{`substr("monkey")`, "Wrong number of arguments to substr Expected=2-3 Got=1"},
+		{`substr("monkey", "1")`, "Argument 2 to substr must be INTEGER Got=STRING"},
+		{`format("%s is %d%%", "x", 10)`, "x is 10%"},
+		{`format("%s", [1, 2])`, "[1, 2]"},
+		{`format("%d", "x")`, "Argument 2 to format must be INTEGER Got=STRING"},
+		{`format("%d %d", 1)`, "Missing argument for %d in format"},
+		{`format("%d", 1, 2)`, "Too many arguments to format Expected=1 Got=2"},
+		{`format("%x", 1)`, "Unknown format verb %x"},
+		{`format()`, "Wrong number of arguments to format Expected=1+ Got=0"},
+		{`to_string(12)`, "12"},
+		{`to_string(true)`, "true"},
+		{`to_string("a")`, "a"},
+		{`parse_int(" 42 ")`, 42},
+		{`parse_int("-7")`, -7},
+		{`parse_int("4x")`, `Could not parse "4x" as INTEGER`},
+	}
+	testBuiltinResults(t, tests)
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []testStruct{
+		{`map([1, 2, 3], fn(x) { x * 2 })`, []string{"2", "4", "6"}},
+		{`map([], fn(x) { x })`, []string{}},
+		{`map(["a", "b"], upper)`, []string{"A", "B"}},
+		{`map([1, 2], fn(x) { x + "a" })`, "Type missmatch: INTEGER + STRING"},
+		{`map([1, 2], fn(x, y) { x })`, "Wrong number of arguments Expected=2 Got=1"},
+		{`map([1], 1)`, "Argument 2 to map must be FUNCTION Got=INTEGER"},
+		{`map(1, fn(x) { x })`, "Argument 1 to map must be ARRAY Got=INTEGER"},
+		{`map([1, 2], fn(x) { if (x > 5) { x } })`, []string{"null", "null"}},
+		{`filter(range(10), fn(x) { x / 2 * 2 == x })`, []string{"0", "2", "4", "6", "8"}},
+		{`filter([1, 2], fn(x) { y })`, "Identifier not found: y"},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc + x })`, 10},
+		{`reduce([1, 2, 3], fn(acc, x) { acc + x }, 10)`, 16},
+		{`reduce(["a", "b"], fn(acc, x) { acc + x }, "")`, "ab"},
+		{`reduce([], fn(acc, x) { acc + x }, 0)`, 0},
+		{`reduce([], fn(acc, x) { acc + x })`, "Cannot reduce an empty array without an initial value"},
+		{`reduce([1, 2], fn(acc, x) { return false + x; })`, "Type missmatch: BOOLEAN + INTEGER"},
+		{`range(3)`, []string{"0", "1", "2"}},
+		{`range(2, 5)`, []string{"2", "3", "4"}},
+		{`range(10, 0, -3)`, []string{"10", "7", "4", "1"}},
+		{`range(5, 2)`, []string{}},
+		{`range(9223372036854775806, 9223372036854775807, 2)`, []string{"9223372036854775806"}},
+		{`range(-9223372036854775807 - 1, 9223372036854775807, 9223372036854775807)`,
+			[]string{"-9223372036854775808", "-1", "9223372036854775806"}},
+		{`range(9223372036854775807, -9223372036854775807 - 1, -9223372036854775807 - 1)`,
+			[]string{"9223372036854775807", "-1"}},
+		{`range(-9223372036854775807, 9223372036854775807)`,
+			"Range from -9223372036854775807 to 9223372036854775807 with step 1 has too many elements"},
+		{`range(9223372036854775807, -9223372036854775807 - 1, -1)`,
+			"Range from 9223372036854775807 to -9223372036854775808 with step -1 has too many elements"},
+		{`range(0, 5, 0)`, "Step of range must not be 0"},
+		{`range("5")`, "Argument 1 to range must be INTEGER Got=STRING"},
+		{`sort([3, 1, 2])`, []string{"1", "2", "3"}},
+		{`sort(["b", "c", "a"])`, []string{"a", "b", "c"}},
+		{`sort([3, 1, 2], fn(a, b) { a > b })`, []string{"3", "2", "1"}},
+		{`sort([[2, "b"], [1, "a"]], fn(a, b) { a[0] < b[0] })`, []string{"[1, a]", "[2, b]"}},
+		{`let xs = [2, 1]; sort(xs); xs`, []string{"2", "1"}},
+		{`sort([1, "a"])`, "Cannot sort STRING and INTEGER without a comparator"},
+		{`sort([1, 2], fn(a, b) { 1 })`, "Comparator passed to sort must return BOOLEAN Got=INTEGER"},
+		{`sort([1, 2], fn(a, b) { c })`, "Identifier not found: c"},
+		{`zip([1, 2, 3], ["a", "b"])`, []string{"[1, a]", "[2, b]"}},
+		{`keys({"b": 1, "a": 2})`, []string{"b", "a"}},
+		{`values({"b": 1, "a": 2})`, []string{"1", "2"}},
+		{`keys([])`, "Argument 1 to keys must be HASH Got=ARRAY"},
+		{`contains([1, [2, 3], "a"], [2, 3])`, true},
+		{`contains([1, 2], "1")`, false},
+		{`contains({"a": 1}, "a")`, true},
+		{`contains({"a": 1}, 1)`, false},
+		{`contains("monkey", "key")`, true},
+		{`contains("monkey", "ape")`, false},
+		{`contains("monkey", 1)`, "Argument 2 to contains must be STRING Got=INTEGER"},
+		{`contains(1, 1)`, "Argument 1 to contains not supported Got=INTEGER"},
+		{`let sum = fn(xs) { reduce(xs, fn(a, b) { a + b }, 0) }; sum(map(range(1, 101), fn(x) { x }))`, 5050},
+	}
+	testBuiltinResults(t, tests)
+}
+
+func TestAssertBuiltins(t *testing.T) {
+	tests := []testStruct{
+		{`assert(1 < 2)`, nil},
+		{`assert(1 > 2)`, "Assertion failed"},
+		{`assert(false, "math is broken")`, "math is broken: Assertion failed"},
+		{`assert(true, 1)`, "Argument 2 to assert must be STRING Got=INTEGER"},
+		{`assert_eq(1 + 1, 2)`, nil},
+		{`assert_eq([1, {"a": [2]}], [1, {"a": [2]}])`, nil},
+		{`assert_eq({"a": 1, "b": 2}, {"b": 2, "a": 1})`, nil},
+		{`assert_eq({"a": 1}, {"a": 2})`, "Expected={a: 2} Got={a: 1}"},
+		{`assert_eq(1 + 2, 4, "sum")`, "sum: Expected=4 Got=3"},
+		{`assert_eq("1", 1)`, "Expected=1 (INTEGER) Got=1 (STRING)"},
+		{`assert_eq(1)`, "Wrong number of arguments to assert_eq Expected=2-3 Got=1"},
+		{`error_kind(assert_error(fn() { 1 / 0 }))`, "ArithmeticError"},
+		{`error_message(assert_error(fn() { throw "bad" }, "bad"))`, "bad"},
+		{`error_kind(assert_error(fn() { missing }, "NameError"))`, "NameError"},
+		{`assert_error(fn() { missing }, "TypeError")`, "Expected error TypeError Got=NameError: Identifier not found: missing"},
+		{`assert_error(fn() { 1 })`, "Expected an error Got=1"},
+		{`assert_error(1)`, "Argument 1 to assert_error must be FUNCTION Got=INTEGER"},
+		{`try { assert(false) } catch (e) { error_kind(e) }`, "AssertionError"},
+	}
+	testBuiltinResults(t, tests)
+
+	evaluated := testEval("let check = fn(x) {\n  assert_eq(x, 1)\n};\ncheck(2)")
+	err, ok := evaluated.(*object.Error)
+	if !ok || err.Kind != object.ASSERTION_ERROR {
+		t.Fatalf("Expected AssertionError Got=%+v", evaluated)
+	}
+	if err.Position.String() != "2:12" {
+		t.Errorf("Wrong position Expected=2:12 Got=%s", err.Position)
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []testStruct{
+		{`try { 1 } catch (e) { 2 }`, 1},
+		{`try { throw "oops"; 1 } catch (e) { 2 }`, 2},
+		{`try { throw "oops" } catch (e) { error_message(e) }`, "oops"},
+		{`try { throw "oops" } catch (e) { error_kind(e) }`, "Error"},
+		{`try { 1 + true } catch (e) { error_kind(e) }`, "TypeError"},
+		{`try { {"a": 1}["b"] } catch (e) { error_message(e) }`, "Key not found: b"},
+		{`try { 1 / 0 } catch (e) { error_kind(e) }`, "ArithmeticError"},
+		{`try { parse_int("x") } catch (e) { error_kind(e) }`, "ValueError"},
+		{`try { missing } catch (e) { error_kind(e) }`, "NameError"},
+		{`try { throw error("bad", "ValueError") } catch (e) { error_kind(e) + ": " + error_message(e) }`,
+			"ValueError: bad"},
+		{`try { throw 1 } catch (e) { error_message(e) }`, "Cannot throw INTEGER, expected STRING or ERROR_VALUE"},
+		{`let f = fn() { throw "inner" }; try { f() } catch (e) { error_message(e) }`, "inner"},
+		{`try { try { throw "a" } catch (e) { throw e } } catch (e) { error_message(e) }`, "a"},
+		{`try { try { throw "a" } catch (e) { throw "b" } } catch (e) { error_message(e) }`, "b"},
+		{`try { throw "a" } catch (e) { e }; 5`, 5},
+		{`let x = 1; try { throw "a" } catch (e) { let x = 2; }; x`, 1},
+		{`let r = try { 1 } catch (e) { 2 } finally { 3 }; r`, 1},
+		{`let r = try { throw "a" } catch (e) { 2 } finally { 3 }; r`, 2},
+		{`let f = fn() { try { return 1; } catch (e) { 2 } finally { 3 }; 4 }; f()`, 1},
+		{`let f = fn() { try { 1 } catch (e) { 2 } finally { return 3; } }; f()`, 3},
+		{`try { 1 } catch (e) { 2 } finally { throw "f" }`, "f"},
+		{`try { throw "a" } catch (e) { throw "b" } finally { 3 }`, "b"},
+		{`throw "uncaught"; 5`, "uncaught"},
+		{`let e = error("x"); e`, "x"},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch obj := evaluated.(type) {
+			case *object.String:
+				if obj.Value != expected {
+					t.Errorf("%d. - Wrong string Expected=%q Got=%q", i, expected, obj.Value)
+				}
+			case *object.Error:
+				testErrorMessage(t, i, obj, expected)
+			case *object.ErrorValue:
+				if obj.Error.Message != expected {
+					t.Errorf("%d. - Wrong error value Expected=%q Got=%q", i, expected, obj.Error.Message)
+				}
+			default:
+				t.Errorf("%d. - Unexpected result Got=%T (%+v)", i, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []testStruct{
+		{`match (1) { 1 => 10, _ => 20 }`, 10},
+		{`match (2) { 1 => 10, _ => 20 }`, 20},
+		{`match ("b") { "a" => 1, "b" => 2 }`, 2},
+		{`match (-3) { -3 => 1, _ => 2 }`, 1},
+		{`match (true) { false => 1, true => 2 }`, 2},
+		{`match (5) { n => n * 2 }`, 10},
+		{`match (5) { n if n > 10 => 1, n if n > 1 => 2, _ => 3 }`, 2},
+		{`match ([1, 2]) { [a] => a, [a, b] => a + b }`, 3},
+		{`match ([1, [2, 3]]) { [1, [_, c]] => c }`, 3},
+		{`match ([1, 2]) { [2, _] => 1, [1, _] => 2 }`, 2},
+		{`match ({"name": "x", "age": 4}) { {"age": a} => a }`, 4},
+		{`match ({"a": 1}) { {"b": b} => b, {"a": 2} => 2, {"a": a} => a + 10 }`, 11},
+		{`match ("s") { [x] => 1, {"a": x} => 2, x => 3 }`, 3},
+		{`let n = 1; match (2) { n => n }; n`, 1},
+		{`let f = fn(x) { match (x) { 0 => 1, n => n * f(n - 1) } }; f(5)`, 120},
+		{`match (3) { 1 => 1, 2 => 2 }`, "No arm matched 3"},
+		{`match ([1]) { [a] if a > 1 => a }`, "No arm matched [1]"},
+		{`match (1) { a if missing => a }`, "Identifier not found: missing"},
+		{`try { match (4) { 1 => 1 } } catch (e) { error_kind(e) }`, "MatchError"},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch obj := evaluated.(type) {
+			case *object.String:
+				if obj.Value != expected {
+					t.Errorf("%d. - Wrong string Expected=%q Got=%q", i, expected, obj.Value)
+				}
+			case *object.Error:
+				testErrorMessage(t, i, obj, expected)
+			default:
+				t.Errorf("%d. - Unexpected result Got=%T (%+v)", i, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	input := `let inner = fn() {
+  throw "deep";
+};
+let outer = fn() {
+  inner()
+};
+try {
+  outer();
+} catch (e) {
+  error_stack(e)
+}`
+	evaluated := testEval(input)
+	stack, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("Object is not an Array Got=%T (%+v)", evaluated, evaluated)
+	}
+	expected := "[2:3 in inner, 5:8 in outer, 8:8 in <module>]"
+	if stack.Inspect() != expected {
+		t.Errorf("Wrong stack Expected=%s Got=%s", expected, stack.Inspect())
+	}
+	raisedTests := []struct {
+		input    string
+		expected string
+	}{
+		{`let f = fn() { try { throw "boom" } catch (e) { error_stack(e) } }; f()`,
+			"[1:22 in f, 1:70 in <module>]"},
+		{`let f = fn(x) { x / 0 }; try { map([1], f) } catch (e) { error_stack(e) }`,
+			"[1:19 in f, <builtin> in map, 1:35 in <module>]"},
+		{"let g = fn() { throw \"x\" };\nlet f = fn() { try { g() } catch (e) { error_stack(e) } };\nlet h = fn() { f() };\nh()",
+			"[1:16 in g, 2:23 in f, 3:17 in h, 4:2 in <module>]"},
+	}
+	for i, test := range raisedTests {
+		if got := testEval(test.input).Inspect(); got != test.expected {
+			t.Errorf("%d - Wrong stack Expected=%s Got=%s", i, test.expected, got)
+		}
+	}
+	evaluated = testEval("let f = fn() { 1 + true };\nf()")
+	err, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("Object is not an Error Got=%T (%+v)", evaluated, evaluated)
+	}
+	if err.Kind != object.TYPE_ERROR || err.Position.String() != "1:18" || len(err.Stack) != 1 ||
+		err.Stack[0].String() != "2:2 in f" {
+		t.Errorf("Wrong error Got=%+v", err)
+	}
+	if err.Inspect() != "ERROR: Type missmatch: INTEGER + BOOLEAN" {
+		t.Errorf("Wrong uncaught error Inspect Got=%s", err.Inspect())
+	}
+}
+
+// testBuiltinResults Checks the results of evaluating builtin calls, an expected
+// string matches either a String result or the message of an Error
+func testBuiltinResults(t *testing.T, tests []testStruct) {
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case nil:
+			testNullObject(t, evaluated)
+		case []string:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("%d. - Object is not an Array Got=%T (%+v)", i, evaluated, evaluated)
+				continue
+			}
+			if array.Inspect() != "["+strings.Join(expected, ", ")+"]" {
+				t.Errorf("%d. - Wrong elements Expected=%v Got=%s", i, expected, array.Inspect())
+			}
+		case string:
+			if errObj, ok := evaluated.(*object.Error); ok {
+				if errObj.Message != expected {
+					t.Errorf("%d. - Wrong error message Expected=%s Got=%s", i, expected, errObj.Message)
+				}
+				continue
+			}
+			str, ok := evaluated.(*object.String)
+			if !ok || str.Value != expected {
+				t.Errorf("%d. - Wrong string Expected=%q Got=%+v", i, expected, evaluated)
+			}
+		}
+	}
+}
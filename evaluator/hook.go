@@ -0,0 +1,169 @@
+package evaluator
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// Hook Observer of the evaluation of programs, e.g. a debugger.
+// BeforeStatement is called before each statement is evaluated with the
+// environment it is evaluated in, a returned error stops the evaluation and
+// is raised at the statement. BeforeCall and AfterCall are called around
+// each function call, pos is the position of the call expression and is
+// not valid for calls made by builtin functions. Tasks cannot be spawned
+// while a Hook is set, so hooks are only notified by a single goroutine
+type Hook interface {
+	BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error
+	BeforeCall(fn object.Object, args []object.Object, pos token.Position)
+	AfterCall(fn object.Object, result object.Object)
+}
+
+// NodeHook Hook that is also notified before each node is evaluated, e.g. to
+// count the nodes evaluated. It is called often and should return quickly
+type NodeHook interface {
+	Hook
+	BeforeNode(node ast.Node)
+}
+
+// BranchHook Hook that is also notified of the branch taken by each evaluated
+// IFExpression, consequence is false when the condition is not truthy
+// regardless of whether the expression has an alternative
+type BranchHook interface {
+	Hook
+	Branch(ifExpr *ast.IFExpression, consequence bool)
+}
+
+// ResultHook Hook that is also notified of the result of each evaluated node,
+// result may be nil for statements that do not produce a value
+type ResultHook interface {
+	Hook
+	AfterNode(node ast.Node, result object.Object)
+}
+
+var (
+	hook       Hook       // the Hook observing evaluation, nil if evaluation is not observed
+	nodeHook   NodeHook   // hook if it is a NodeHook, otherwise nil
+	branchHook BranchHook // hook if it is a BranchHook, otherwise nil
+	resultHook ResultHook // hook if it is a ResultHook, otherwise nil
+)
+
+// SetHook Sets the Hook observing evaluation and returns the previous one,
+// setting nil stops the observation. Waits for spawned tasks that are still
+// running to complete first, since they are evaluated without notifications
+func SetHook(h Hook) Hook {
+	tasks.Wait()
+	previous := hook
+	hook = h
+	nodeHook, _ = h.(NodeHook)
+	branchHook, _ = h.(BranchHook)
+	resultHook, _ = h.(ResultHook)
+	return previous
+}
+
+// Hooks Combines hooks into a single Hook notifying each of them in order,
+// the first error returned by BeforeStatement stops the evaluation
+func Hooks(hooks ...Hook) Hook {
+	return multiHook(hooks)
+}
+
+// multiHook Hook forwarding the notifications to a list of hooks
+type multiHook []Hook
+
+// BeforeStatement Notifies each hook until one of them returns an error
+func (hooks multiHook) BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	for _, h := range hooks {
+		if err := h.BeforeStatement(stmt, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BeforeCall Notifies each hook of a call
+func (hooks multiHook) BeforeCall(fn object.Object, args []object.Object, pos token.Position) {
+	for _, h := range hooks {
+		h.BeforeCall(fn, args, pos)
+	}
+}
+
+// AfterCall Notifies each hook of a returning call
+func (hooks multiHook) AfterCall(fn object.Object, result object.Object) {
+	for _, h := range hooks {
+		h.AfterCall(fn, result)
+	}
+}
+
+// BeforeNode Notifies the hooks that are NodeHooks of a node
+func (hooks multiHook) BeforeNode(node ast.Node) {
+	for _, h := range hooks {
+		if h, ok := h.(NodeHook); ok {
+			h.BeforeNode(node)
+		}
+	}
+}
+
+// AfterNode Notifies the hooks that are ResultHooks of the result of a node
+func (hooks multiHook) AfterNode(node ast.Node, result object.Object) {
+	for _, h := range hooks {
+		if h, ok := h.(ResultHook); ok {
+			h.AfterNode(node, result)
+		}
+	}
+}
+
+// Branch Notifies the hooks that are BranchHooks of a branch
+func (hooks multiHook) Branch(ifExpr *ast.IFExpression, consequence bool) {
+	for _, h := range hooks {
+		if h, ok := h.(BranchHook); ok {
+			h.Branch(ifExpr, consequence)
+		}
+	}
+}
+
+// beforeStatement Notifies the hook that a statement is about to be evaluated
+func beforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	if hook == nil {
+		return nil
+	}
+	err := hook.BeforeStatement(stmt, env)
+	if err != nil && !err.Position.IsValid() {
+		raiseAt(err, stmt.Pos(), env)
+	}
+	return err
+}
+
+// applyObserved Applies arguments on a function, notifying the hook before and after the call
+func applyObserved(env *object.Environment, fn object.Object, args []object.Object, pos token.Position) object.Object {
+	if hook == nil {
+		return applyFunction(env, fn, args, pos)
+	}
+	hook.BeforeCall(fn, args, pos)
+	result := applyFunction(env, fn, args, pos)
+	hook.AfterCall(fn, result)
+	return result
+}
+
+// beforeNode Notifies the hook that a node is about to be evaluated if it is a NodeHook
+func beforeNode(node ast.Node) {
+	if nodeHook == nil {
+		return
+	}
+	nodeHook.BeforeNode(node)
+}
+
+// afterNode Notifies the hook of the result of a node if it is a ResultHook
+func afterNode(node ast.Node, result object.Object) {
+	if resultHook == nil {
+		return
+	}
+	resultHook.AfterNode(node, result)
+}
+
+// branchTaken Notifies the hook of the branch taken by an IFExpression if it is a BranchHook
+func branchTaken(ifExpr *ast.IFExpression, consequence bool) {
+	if branchHook == nil {
+		return
+	}
+	branchHook.Branch(ifExpr, consequence)
+}
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// recordingHook Hook recording the events it observes, stopping the
+// evaluation before the statement at stopLine if set
+type recordingHook struct {
+	events   []string
+	stopLine int
+}
+
+func (h *recordingHook) BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	h.events = append(h.events, "stmt "+stmt.Pos().String())
+	if stmt.Pos().Line == h.stopLine {
+		return object.NewErrorf("stopped")
+	}
+	return nil
+}
+
+func (h *recordingHook) BeforeCall(fn object.Object, args []object.Object, pos token.Position) {
+	h.events = append(h.events, fmt.Sprintf("call %s %d %s", FunctionName(fn), len(args), pos))
+}
+
+func (h *recordingHook) AfterCall(fn object.Object, result object.Object) {
+	h.events = append(h.events, fmt.Sprintf("return %s %s", FunctionName(fn), result.Inspect()))
+}
+
+func TestHook(t *testing.T) {
+	input := "let double = fn(x) {\n  x * 2\n};\nlet a = double(2);\nmap([1], double);"
+	h := &recordingHook{}
+	defer SetHook(SetHook(h))
+	testEval(input)
+	expected := []string{
+		"stmt 1:1",
+		"stmt 4:1",
+		"call double 1 4:15",
+		"stmt 2:3",
+		"return double 4",
+		"stmt 5:1",
+		"call map 2 5:4",
+		"call double 1 0:0",
+		"stmt 2:3",
+		"return double 2",
+		"return map [2]",
+	}
+	if strings.Join(h.events, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Wrong events Expected=%q Got=%q", expected, h.events)
+	}
+
+	h.events, h.stopLine = nil, 2
+	result := testEval(input)
+	err, ok := result.(*object.Error)
+	if !ok || err.Message != "stopped" || err.Position.String() != "2:3" {
+		t.Fatalf("Expected error stopping the evaluation at 2:3 Got=%+v", result)
+	}
+	if len(err.Stack) != 1 || err.Stack[0].Function != "double" {
+		t.Errorf("Wrong stack Got=%+v", err.Stack)
+	}
+	if SetHook(nil) != h {
+		t.Errorf("Expected SetHook to return the previous hook")
+	}
+}
+
+// countingHook NodeHook counting the nodes evaluated
+type countingHook struct {
+	recordingHook
+	nodes map[string]int
+}
+
+func (h *countingHook) BeforeNode(node ast.Node) {
+	h.nodes[fmt.Sprintf("%T", node)]++
+}
+
+func TestNodeHook(t *testing.T) {
+	h := &countingHook{nodes: make(map[string]int)}
+	defer SetHook(SetHook(h))
+	testEval("let a = 1 + 2;\na * a")
+	expected := map[string]int{
+		"*ast.Program":             1,
+		"*ast.LetStatement":        1,
+		"*ast.ExpressionStatement": 1,
+		"*ast.InfixExpression":     2,
+		"*ast.IntegerLiteral":      2,
+		"*ast.Identifier":          2,
+	}
+	if fmt.Sprint(h.nodes) != fmt.Sprint(expected) {
+		t.Errorf("Wrong node counts Expected=%v Got=%v", expected, h.nodes)
+	}
+}
+
+// branchRecorder BranchHook recording the arms taken
+type branchRecorder struct {
+	recordingHook
+	branches []string
+}
+
+func (h *branchRecorder) Branch(ifExpr *ast.IFExpression, consequence bool) {
+	h.branches = append(h.branches, fmt.Sprintf("%s %t", ifExpr.Pos(), consequence))
+}
+
+func TestHooks(t *testing.T) {
+	first, second := &branchRecorder{}, &countingHook{nodes: make(map[string]int)}
+	second.stopLine = 3
+	defer SetHook(SetHook(Hooks(first, second)))
+	result := testEval("if (1 > 2) { 1 };\nif (true) { 2 } else { 3 };\n4")
+	if err, ok := result.(*object.Error); !ok || err.Position.String() != "3:1" {
+		t.Fatalf("Expected the second hook to stop the evaluation at 3:1 Got=%+v", result)
+	}
+	expected := "1:1 false,2:1 true"
+	if strings.Join(first.branches, ",") != expected {
+		t.Errorf("Wrong branches Expected=%q Got=%q", expected, first.branches)
+	}
+	if len(first.events) != 4 || len(second.events) != 4 {
+		t.Errorf("Expected both hooks to see 4 statements Got=%q %q", first.events, second.events)
+	}
+	if second.nodes["*ast.IFExpression"] != 2 {
+		t.Errorf("Expected the node hook to see 2 IFExpressions Got=%v", second.nodes)
+	}
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/object"
+)
+
+// MAX_EXPANSION_DEPTH Number of nested expansions after which a macro
+// expanding into calls of itself is reported as an error
+const MAX_EXPANSION_DEPTH = 100
+
+// DefineMacros Removes the top level let statements binding macro literals
+// from a program and binds the macros they define in env
+func DefineMacros(program *ast.Program, env *object.Environment) {
+	statements := make([]ast.Statement, 0, len(program.Statements))
+	for _, stmt := range program.Statements {
+		letStmt, ok := stmt.(*ast.LetStatement)
+		if !ok || letStmt.Name == nil {
+			statements = append(statements, stmt)
+			continue
+		}
+		macroLit, ok := letStmt.Value.(*ast.MacroLiteral)
+		if !ok {
+			statements = append(statements, stmt)
+			continue
+		}
+		env.Set(letStmt.Name.Value, object.NewMacro(letStmt.Name.Value, macroLit, env))
+	}
+	program.Statements = statements
+}
+
+// ExpandMacros Replaces the calls of the macros defined in env with the
+// quoted code returned by the macros. Macros are called with their
+// arguments quoted and the code they return is expanded in turn
+func ExpandMacros(program ast.Node, env *object.Environment) (ast.Node, *object.Error) {
+	return expandMacros(program, env, 0)
+}
+
+// expandMacros Expands the macro calls in a node expanded from depth macro calls
+func expandMacros(node ast.Node, env *object.Environment, depth int) (ast.Node, *object.Error) {
+	var err *object.Error
+	expanded := ast.Rewrite(node, func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || err != nil {
+			return node
+		}
+		macro, ok := lookupMacro(call, env)
+		if !ok {
+			return node
+		}
+		if depth >= MAX_EXPANSION_DEPTH {
+			err = object.NewErrorf("Macro expansion of %s exceeded depth %d", macro.Name, MAX_EXPANSION_DEPTH)
+			err.Position = call.Pos()
+			return node
+		}
+		var code ast.Node
+		if code, err = expandMacroCall(macro, call); err != nil {
+			return node
+		}
+		code, err = expandMacros(code, env, depth+1)
+		return code
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// lookupMacro Returns the macro called by a CallExpression if any
+func lookupMacro(call *ast.CallExpression, env *object.Environment) (*object.Macro, bool) {
+	id, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+	obj, err := env.Get(id.Value)
+	if err != nil {
+		return nil, false
+	}
+	macro, ok := obj.(*object.Macro)
+	return macro, ok
+}
+
+// expandMacroCall Evaluates the body of a macro with its parameters bound
+// to the quoted arguments of a call and returns the quoted code it returned
+func expandMacroCall(macro *object.Macro, call *ast.CallExpression) (ast.Node, *object.Error) {
+	if len(call.Arguments) != len(macro.Parameters) {
+		err := object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to %s Expected=%d Got=%d",
+			macro.Name, len(macro.Parameters), len(call.Arguments))
+		err.Position = call.Pos()
+		return nil, err
+	}
+	macroEnv := object.NewEnclosedEnvironment(macro.Env)
+	for i, param := range macro.Parameters {
+		macroEnv.Set(param.Value, object.NewQuote(call.Arguments[i]))
+	}
+	stack := macroEnv.CallStack()
+	stack.Push(object.Frame{Function: macro.Name, Position: call.Pos()})
+	evaluated := unwrappReturnValue(Eval(macro.Body, macroEnv))
+	stack.Pop()
+	if err, ok := evaluated.(*object.Error); ok {
+		return nil, err
+	}
+	quote, ok := evaluated.(*object.Quote)
+	if !ok {
+		err := object.NewKindErrorf(object.TYPE_ERROR, "Macro %s must return a QUOTE Got=%s",
+			macro.Name, typeOf(evaluated))
+		err.Position = call.Pos()
+		return nil, err
+	}
+	return quote.Node, nil
+}
+
+// typeOf Returns the type of an object, NULL for missing results
+func typeOf(obj object.Object) object.ObjectType {
+	if obj == nil {
+		return object.NULL_OBJ
+	}
+	return obj.Type()
+}
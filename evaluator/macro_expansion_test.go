@@ -0,0 +1,154 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, `5`},
+		{`quote(foobar + barfoo)`, `(foobar + barfoo)`},
+		{`quote(unquote(4 + 4))`, `8`},
+		{`quote(8 + unquote(4 + 4))`, `(8 + 8)`},
+		{`let x = 8; quote(unquote(x) > 1)`, `(8 > 1)`},
+		{`quote(unquote(true == false))`, `false`},
+		{`quote(unquote("a" + "b"))`, `"ab"`},
+		{`quote(unquote([1, 2]))`, `[1, 2]`},
+		{`quote(unquote({"a": 1}))`, `{"a": 1}`},
+		{`let q = quote(4 + 4); quote(unquote(q) * 2)`, `((4 + 4) * 2)`},
+		{`let f = fn(x) { quote(unquote(x) + 1) }; f(1); f(2)`, `(2 + 1)`},
+	}
+	for i, test := range tests {
+		evaluated := testEval(test.input)
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			t.Errorf("%d. - Expected *object.Quote Got=%T (%+v)", i, evaluated, evaluated)
+			continue
+		}
+		if quote.Node.String() != test.expected {
+			t.Errorf("%d. - Wrong quoted node Expected=%s Got=%s", i, test.expected, quote.Node.String())
+		}
+	}
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(1, 2)`, "Wrong number of arguments to quote Expected=1 Got=2"},
+		{`quote(unquote())`, "Wrong number of arguments to unquote Expected=1 Got=0"},
+		{`quote(unquote(fn() { 1 }))`, "Cannot unquote FUNCTION"},
+		{`quote(unquote(missing))`, "Identifier not found: missing"},
+		{`unquote(1)`, "Identifier not found: unquote"},
+	}
+	for i, test := range errorTests {
+		testErrorMessage(t, i, testEval(test.input), test.expected)
+	}
+}
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+	let number = 1;
+	let function = fn(x, y) { x + y };
+	let mymacro = macro(x, y) { x + y; };`
+	env := object.NewEnvironment()
+	program := parser.New(lexer.New(input)).ParseProgram()
+	DefineMacros(program, env)
+	if len(program.Statements) != 2 {
+		t.Fatalf("Wrong number of statements Expected=2 Got=%d", len(program.Statements))
+	}
+	if _, err := env.Get("number"); err == nil {
+		t.Errorf("number should not be defined")
+	}
+	obj, err := env.Get("mymacro")
+	if err != nil {
+		t.Fatalf("macro not in environment")
+	}
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("object is not *object.Macro Got=%T", obj)
+	}
+	if macro.Inspect() != "macro mymacro(x, y)" || macro.Body.String() != "(x + y)" {
+		t.Errorf("Wrong macro Got=%s %s", macro.Inspect(), macro.Body.String())
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let infix = macro() { quote(1 + 2); }; infix();`, `(1 + 2)`},
+		{`let reverse = macro(a, b) { quote(unquote(b) - unquote(a)); }; reverse(2 + 2, 10 - 5);`,
+			`(10 - 5) - (2 + 2)`},
+		{`let unless = macro(cond, cons, alt) {
+			quote(if (!(unquote(cond))) { unquote(cons); } else { unquote(alt); });
+		};
+		unless(10 > 5, puts("not greater"), puts("greater"));`,
+			`if (!(10 > 5)) { puts("not greater") } else { puts("greater") }`},
+		{`let twice = macro(x) { quote(unquote(x) + unquote(x)) };
+		let double = macro(x) { quote(twice(unquote(x))) };
+		double(1) * double(2)`, `((1 + 1) * (2 + 2))`},
+	}
+	for i, test := range tests {
+		expected := parser.New(lexer.New(test.expected)).ParseProgram()
+		program := parser.New(lexer.New(test.input)).ParseProgram()
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded, err := ExpandMacros(program, env)
+		if err != nil {
+			t.Fatalf("%d. - Unexpected error: %s", i, err.Message)
+		}
+		if expanded.String() != expected.String() {
+			t.Errorf("%d. - Wrong expansion Expected=%s Got=%s", i, expected.String(), expanded.String())
+		}
+	}
+}
+
+func TestMacroEvaluation(t *testing.T) {
+	tests := []testStruct{
+		{`let unless = macro(cond, cons, alt) {
+			quote(if (!(unquote(cond))) { unquote(cons) } else { unquote(alt) })
+		};
+		unless(1 > 2, 10, 20)`, 10},
+		{`let swap = macro(a, b) { quote(unquote(b) - unquote(a)) };
+		let f = fn(x) { swap(x, 10) }; f(3) + f(4)`, 13},
+		{`let m = macro(x) { 1 }; m(2)`, "Macro m must return a QUOTE Got=INTEGER"},
+		{`let m = macro(x) { quote(x) }; m()`, "Wrong number of arguments to m Expected=1 Got=0"},
+		{`let m = macro() { throw "bad" }; m()`, "bad"},
+		{`let loop = macro() { quote(loop()) }; loop()`, "Macro expansion of loop exceeded depth 100"},
+		{`let f = fn() { macro(x) { x } }; f()`, "Macros can only be defined by top level let statements"},
+	}
+	for i, test := range tests {
+		program := parser.New(lexer.New(test.input)).ParseProgram()
+		macroEnv := object.NewEnvironment()
+		DefineMacros(program, macroEnv)
+		var evaluated object.Object
+		expanded, err := ExpandMacros(program, macroEnv)
+		if err != nil {
+			evaluated = err
+		} else {
+			evaluated = Eval(expanded, object.NewEnvironment())
+		}
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorMessage(t, i, evaluated, expected)
+		}
+	}
+	program := parser.New(lexer.New("let m = macro(x) { quote(unquote(x) + 1) };\nm(\n1)")).ParseProgram()
+	env := object.NewEnvironment()
+	DefineMacros(program, env)
+	expanded, _ := ExpandMacros(program, env)
+	stmt := expanded.(*ast.Program).Statements[0].(*ast.ExpressionStatement)
+	if pos := stmt.Expression.(*ast.InfixExpression).Left.Pos(); pos.Line != 3 {
+		t.Errorf("Expected unquoted argument to keep its position Got=%s", pos)
+	}
+}
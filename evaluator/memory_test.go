@@ -0,0 +1,87 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+// evalWithMemory Evaluates input while accounting its memory to a Memory with a limit
+func evalWithMemory(input string, limit int64) (object.Object, *object.Memory) {
+	memory := object.NewMemory(limit)
+	env := object.NewEnvironment()
+	env.SetMemory(memory)
+	return Eval(parser.New(lexer.New(input)).ParseProgram(), env), memory
+}
+
+func TestMemoryLimit(t *testing.T) {
+	grow := "let grow = fn(s, n) { if (n == 0) { len(s) } else { grow(s + s, n - 1) } };\n"
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{grow + "grow(\"ab\", 40)", "MemoryError"},
+		{"range(100000000)", "MemoryError"},
+		{"range(-9223372036854775807, 9223372036854775807, 2)", "MemoryError"},
+		{"let xs = range(1000); range(1000000)", "MemoryError"},
+		{grow + "try { grow(\"ab\", 40) } catch (e) { error_kind(e) }", "MemoryError"},
+		{grow + "let r = try { grow(\"ab\", 40) } catch (e) { 0 }; grow(\"ab\", 10)", "2048"},
+		{"let f = fn() { len(range(10000)) }; reduce(range(100), fn(acc, i) { acc + f() }, 0)", "1000000"},
+	}
+	for i, test := range tests {
+		result, _ := evalWithMemory(test.input, 1000000)
+		got := result.Inspect()
+		if err, ok := result.(*object.Error); ok {
+			got = string(err.Kind)
+			if !strings.HasPrefix(err.Message, "Memory limit of 1000000 bytes exceeded") {
+				t.Errorf("%d - Wrong message Got=%s", i, err.Message)
+			}
+		}
+		if got != test.expected {
+			t.Errorf("%d - Wrong result Expected=%s Got=%s", i, test.expected, result.Inspect())
+		}
+	}
+}
+
+func TestMemoryPeak(t *testing.T) {
+	result, memory := evalWithMemory("let xs = range(10000); let f = fn() { len(range(10000)) }; f(); f(); len(xs)", 0)
+	if result.Inspect() != "10000" {
+		t.Fatalf("Wrong result Expected=10000 Got=%s", result.Inspect())
+	}
+	array := int64(object.ARRAY_SIZE + 10000*(object.ELEMENT_SIZE+object.INTEGER_SIZE))
+	if memory.Peak() < array || memory.Peak() > 3*array+object.MEASURE_INTERVAL {
+		t.Errorf("Wrong peak Expected about %d Got=%d", 2*array, memory.Peak())
+	}
+	if memory.Allocated() < 3*array {
+		t.Errorf("Expected every array to be allocated Got=%d", memory.Allocated())
+	}
+}
+
+func TestMemoryPerEvaluation(t *testing.T) {
+	input := "let f = fn() { len(range(10000)) }; f() + f()"
+	results := make(chan object.Object)
+	memories := make(chan *object.Memory)
+	for _, limit := range []int64{1000, 0} {
+		go func(limit int64) {
+			result, memory := evalWithMemory(input, limit)
+			results <- result
+			memories <- memory
+		}(limit)
+	}
+	got := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		got[(<-results).Inspect()] = true
+		if memory := <-memories; memory.Allocated() == 0 {
+			t.Errorf("Expected the allocations of both evaluations to be accounted")
+		}
+	}
+	if !got["20000"] || len(got) != 2 {
+		t.Errorf("Expected only the limited evaluation to raise a MemoryError Got=%v", got)
+	}
+	if result := testEval(input); result.Inspect() != "20000" {
+		t.Errorf("Expected an evaluation without Memory not to be limited Got=%s", result.Inspect())
+	}
+}
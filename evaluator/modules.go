@@ -0,0 +1,205 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+// SEARCH_PATH_ENV Environment variable holding a list of directories
+// searched for modules, separated like PATH
+const SEARCH_PATH_ENV = "MONKEY_PATH"
+
+// ModuleLoader Resolves, evaluates and caches the modules imported by programs
+type ModuleLoader struct {
+	SearchPath []string   // directories searched when a module is not found next to its importer
+	mutex      sync.Mutex // guards modules, loads, current and waiting, which spawned tasks may import into
+	modules    map[string]*object.Module
+	loads      map[string]*moduleLoad            // modules being evaluated by their key
+	current    map[*object.CallStack]*moduleLoad // innermost module being evaluated by each task
+	waiting    map[*object.CallStack]*moduleLoad // module evaluated by another task each task waits for
+}
+
+// moduleLoad Evaluation of a module in progress
+type moduleLoad struct {
+	key      string
+	importer *moduleLoad       // module evaluated by the same task that imported this one, nil if none
+	task     *object.CallStack // call stack of the task evaluating the module
+	done     chan struct{}     // closed once the evaluation is complete
+	module   *object.Module
+	err      *object.Error
+}
+
+// Modules The ModuleLoader used to evaluate import expressions
+var Modules = NewModuleLoader(SearchPathFromEnv())
+
+// NewModuleLoader Creates a ModuleLoader with an empty cache
+func NewModuleLoader(searchPath []string) *ModuleLoader {
+	return &ModuleLoader{
+		SearchPath: searchPath,
+		modules:    make(map[string]*object.Module),
+		loads:      make(map[string]*moduleLoad),
+		current:    make(map[*object.CallStack]*moduleLoad),
+		waiting:    make(map[*object.CallStack]*moduleLoad),
+	}
+}
+
+// SearchPathFromEnv Returns the module search path set in the environment
+func SearchPathFromEnv() []string {
+	value := os.Getenv(SEARCH_PATH_ENV)
+	if value == "" {
+		return []string{}
+	}
+	return filepath.SplitList(value)
+}
+
+// Import Returns the module stored at path, evaluating it in its own environment
+// the first time it is imported by the task evaluating code in env. Relative paths
+// are resolved against the directory of the importing file before the search path
+// is tried. Tasks importing a module while another task evaluates it wait for the evaluation
+func (loader *ModuleLoader) Import(path, importer string, env *object.Environment) object.Object {
+	resolved, key, err := loader.resolve(path, importer)
+	if err != nil {
+		return err
+	}
+	task := env.CallStack()
+	loader.mutex.Lock()
+	if module, ok := loader.modules[key]; ok {
+		loader.mutex.Unlock()
+		return module
+	}
+	if load, ok := loader.loads[key]; ok {
+		return loader.wait(load, task)
+	}
+	load := &moduleLoad{
+		key:      key,
+		importer: loader.current[task],
+		task:     task,
+		done:     make(chan struct{}),
+	}
+	loader.loads[key] = load
+	loader.current[task] = load
+	loader.mutex.Unlock()
+	module, err := loader.load(path, resolved, env)
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+	if load.importer != nil {
+		loader.current[task] = load.importer
+	} else {
+		delete(loader.current, task)
+	}
+	delete(loader.loads, key)
+	load.module, load.err = module, err
+	close(load.done)
+	if err != nil {
+		return err
+	}
+	loader.modules[key] = module
+	return module
+}
+
+// wait Waits for a module evaluated by another task, called with the mutex locked. Returns
+// an ImportError if the module is being evaluated by the calling task or one waiting for it
+func (loader *ModuleLoader) wait(load *moduleLoad, task *object.CallStack) object.Object {
+	if cycle := loader.cycle(load, task); cycle != nil {
+		loader.mutex.Unlock()
+		return object.NewKindErrorf(object.IMPORT_ERROR, "Import cycle: %s -> %s", strings.Join(cycle, " -> "), load.key)
+	}
+	loader.waiting[task] = load
+	loader.mutex.Unlock()
+	<-load.done
+	loader.mutex.Lock()
+	delete(loader.waiting, task)
+	loader.mutex.Unlock()
+	if load.err != nil {
+		return load.err.Copy()
+	}
+	return load.module
+}
+
+// cycle Returns the keys of the modules that would wait for themselves if the task
+// waited for a load, nil if there are none. The waits never form a cycle themselves,
+// since each one is checked before it starts
+func (loader *ModuleLoader) cycle(load *moduleLoad, task *object.CallStack) []string {
+	cycle := make([]string, 0)
+	for {
+		cycle = append(cycle, loader.chain(load)...)
+		if load.task == task {
+			return cycle
+		}
+		next, ok := loader.waiting[load.task]
+		if !ok {
+			return nil
+		}
+		load = next
+	}
+}
+
+// chain Returns the keys of a load and the loads imported after it by the same task, in import order
+func (loader *ModuleLoader) chain(load *moduleLoad) []string {
+	keys := make([]string, 0)
+	for current := loader.current[load.task]; current != load; current = current.importer {
+		keys = append(keys, current.key)
+	}
+	keys = append(keys, load.key)
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+	return keys
+}
+
+// load Reads, parses and evaluates a module in its own environment, evaluated
+// by the task evaluating code in the importing environment
+func (loader *ModuleLoader) load(path, resolved string, importer *object.Environment) (*object.Module, *object.Error) {
+	source, readErr := os.ReadFile(resolved)
+	if readErr != nil {
+		return nil, object.NewKindErrorf(object.IMPORT_ERROR, "Could not read module %s: %s",
+			path, readErr)
+	}
+	p := parser.New(lexer.NewWithFilename(string(source), resolved))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, object.NewKindErrorf(object.IMPORT_ERROR, "Could not parse module: %s", p.Errors()[0])
+	}
+	macroEnv := object.NewEnvironment()
+	DefineMacros(program, macroEnv)
+	expanded, expandErr := ExpandMacros(program, macroEnv)
+	if expandErr != nil {
+		return nil, expandErr
+	}
+	env := object.NewCallEnvironment(nil, importer)
+	if err, ok := Eval(expanded, env).(*object.Error); ok {
+		return nil, err
+	}
+	return object.NewModule(resolved, env), nil
+}
+
+// resolve Finds the file a module path refers to, returns the path
+// to read it from and the absolute path used as cache key
+func (loader *ModuleLoader) resolve(path, importer string) (string, string, *object.Error) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = []string{filepath.Join(filepath.Dir(importer), path)}
+		for _, dir := range loader.SearchPath {
+			candidates = append(candidates, filepath.Join(dir, path))
+		}
+	}
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		key, err := filepath.Abs(candidate)
+		if err != nil {
+			return "", "", object.NewKindErrorf(object.IMPORT_ERROR, "Could not resolve module %s: %s",
+				path, err)
+		}
+		return filepath.Clean(candidate), key, nil
+	}
+	return "", "", object.NewKindErrorf(object.IMPORT_ERROR, "Module not found: %s", path)
+}
@@ -0,0 +1,155 @@
+package evaluator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	libDir := t.TempDir()
+	writeTestModule(t, dir, "main.monkey", `
+		let math = import "math.monkey";
+		let strings = import "strings.monkey";
+		math.square(3) + math.offset`)
+	writeTestModule(t, dir, "math.monkey", `
+		let helper = fn(x) { x * x };
+		export let square = fn(x) { helper(x) };
+		export let offset = 1;`)
+	writeTestModule(t, libDir, "strings.monkey", `export let greeting = "hi";`)
+	writeTestModule(t, dir, "sub/counter.monkey", `
+		let inner = import "../math.monkey";
+		export let value = inner.square(2);`)
+	writeTestModule(t, dir, "a.monkey", `let b = import "b.monkey"; export let x = 1;`)
+	writeTestModule(t, dir, "b.monkey", `let a = import "a.monkey"; export let y = 2;`)
+	writeTestModule(t, dir, "broken.monkey", `let = 5;`)
+	writeTestModule(t, dir, "failing.monkey", `export let x = 1 + true;`)
+
+	Modules = NewModuleLoader([]string{libDir})
+	main := filepath.Join(dir, "main.monkey")
+	testIntegerObject(t, testEvalFile(t, main), 10)
+	testIntegerObject(t, testEvalFileSource(t, main, `(import "sub/counter.monkey").value`), 4)
+
+	greeting := testEvalFileSource(t, main, `(import "strings.monkey").greeting`)
+	if str, ok := greeting.(*object.String); !ok || str.Value != "hi" {
+		t.Errorf("Wrong greeting Got=%+v", greeting)
+	}
+
+	first := testEvalFileSource(t, main, `import "math.monkey"`)
+	second := testEvalFileSource(t, main, `import "sub/../math.monkey"`)
+	if first != second {
+		t.Errorf("Expected module to be cached, Got two different modules")
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`(import "math.monkey").helper`, "helper is not exported by module " + filepath.Join(dir, "math.monkey")},
+		{`import "missing.monkey"`, "Module not found: missing.monkey"},
+		{`import 5`, "Import path must be a STRING Got=INTEGER"},
+		{`5.x`, "Cannot access member x of INTEGER"},
+		{`import "broken.monkey"`, "Could not parse module: " + filepath.Join(dir, "broken.monkey") +
+			":1:5: peekToken: Expected type=IDENT Got=="},
+		{`import "failing.monkey"`, "Type missmatch: INTEGER + BOOLEAN"},
+	}
+	for i, test := range errorTests {
+		testErrorMessage(t, i, testEvalFileSource(t, main, test.input), test.expected)
+	}
+
+	cycle := testEvalFileSource(t, main, `import "a.monkey"`)
+	aPath, _ := filepath.Abs(filepath.Join(dir, "a.monkey"))
+	bPath, _ := filepath.Abs(filepath.Join(dir, "b.monkey"))
+	testErrorMessage(t, 0, cycle, "Import cycle: "+aPath+" -> "+bPath+" -> "+aPath)
+}
+
+func TestConcurrentImport(t *testing.T) {
+	dir := t.TempDir()
+	writeTestModule(t, dir, "main.monkey", "")
+	writeTestModule(t, dir, "slow.monkey", `
+		let n = reduce(range(20000), fn(acc, x) { acc + 1 }, 0);
+		puts("loaded");
+		export let value = n;`)
+	writeTestModule(t, dir, "a.monkey", `let b = import "b.monkey"; export let x = 1;`)
+	writeTestModule(t, dir, "b.monkey", `let slow = import "slow.monkey"; let a = import "a.monkey"; export let y = 2;`)
+	var out bytes.Buffer
+	previous := Output
+	Output = &out
+	defer func() { Output = previous }()
+	Modules = NewModuleLoader([]string{})
+	main := filepath.Join(dir, "main.monkey")
+
+	result := testEvalFileSource(t, main, `
+		let load = fn() { let slow = import "slow.monkey"; slow.value };
+		let tasks = map(range(4), fn(i) { spawn(load) });
+		reduce(map(tasks, fn(t) { await(t) }), fn(acc, x) { acc + x }, 0)`)
+	testIntegerObject(t, result, 80000)
+	if out.String() != "loaded\n" {
+		t.Errorf("Expected the module to be evaluated once Got=%q", out.String())
+	}
+
+	result = testEvalFileSource(t, main, `
+		let load = fn(path) { try { import path; "loaded" } catch (e) { error_kind(e) } };
+		let a = spawn(load, "a.monkey");
+		let b = spawn(load, "b.monkey");
+		[await(a), await(b)]`)
+	if result.Inspect() != "[ImportError, ImportError]" {
+		t.Errorf("Expected both tasks to report the import cycle Got=%s", result.Inspect())
+	}
+	if len(Modules.loads) != 0 || len(Modules.current) != 0 || len(Modules.waiting) != 0 {
+		t.Errorf("Expected no imports in progress Got=%v %v %v", Modules.loads, Modules.current, Modules.waiting)
+	}
+}
+
+func TestImportCycleAcrossTasks(t *testing.T) {
+	loader := NewModuleLoader([]string{})
+	first, second, third := object.NewCallStack(), object.NewCallStack(), object.NewCallStack()
+	a := &moduleLoad{key: "a", task: first}
+	c := &moduleLoad{key: "c", importer: a, task: first}
+	b := &moduleLoad{key: "b", task: second}
+	loader.current[first], loader.current[second] = c, b
+	loader.waiting[first] = b
+	if cycle := strings.Join(loader.cycle(a, second), ","); cycle != "a,c,b" {
+		t.Errorf("Wrong cycle Expected=a,c,b Got=%s", cycle)
+	}
+	if cycle := strings.Join(loader.cycle(c, first), ","); cycle != "c" {
+		t.Errorf("Wrong cycle Expected=c Got=%s", cycle)
+	}
+	if cycle := loader.cycle(b, third); cycle != nil {
+		t.Errorf("Expected no cycle Got=%v", cycle)
+	}
+}
+
+func writeTestModule(t *testing.T, dir, name, source string) {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(source), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testEvalFile(t *testing.T, filename string) object.Object {
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testEvalFileSource(t, filename, string(source))
+}
+
+func testEvalFileSource(t *testing.T, filename, source string) object.Object {
+	p := parser.New(lexer.NewWithFilename(source, filename))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors: %v", p.Errors())
+	}
+	return Eval(program, object.NewEnvironment())
+}
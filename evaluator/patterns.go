@@ -0,0 +1,200 @@
+package evaluator
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/object"
+)
+
+// evalMatchExpression Evaluates the body of the first arm whose pattern matches
+// the subject and whose guard, if any, is truthy. The arm is evaluated in an
+// enclosed environment holding the names bound by its pattern
+func evalMatchExpression(matchExpr *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(matchExpr.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+	for _, arm := range matchExpr.Arms {
+		bindings := make(map[string]object.Object)
+		matched, err := matchPattern(arm.Pattern, subject, bindings, env)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		armEnv := object.NewEnclosedEnvironment(env)
+		for name, value := range bindings {
+			armEnv.Set(name, value)
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+	return object.NewKindErrorf(object.MATCH_ERROR, "No arm matched %s", subject.Inspect())
+}
+
+// matchPattern Checks if a value matches a pattern and records the names
+// bound by the pattern. Literal patterns are compared by value, array
+// patterns must have the length of the value and hash patterns match
+// any hash holding the keys of the pattern
+func matchPattern(pattern ast.Expression, value object.Object, bindings map[string]object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != ast.WILDCARD {
+			bindings[pattern.Value] = value
+		}
+		return true, nil
+	case *ast.ArrayLiteral:
+		array, ok := value.(*object.Array)
+		if !ok || len(array.Elements) != len(pattern.Elements) {
+			return false, nil
+		}
+		for i, element := range pattern.Elements {
+			if matched, err := matchPattern(element, array.Elements[i], bindings, env); !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case *ast.HashLiteral:
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return false, nil
+		}
+		for _, pair := range pattern.Pairs {
+			key, err := evalPatternLiteral(pair.Key, env)
+			if err != nil {
+				return false, err
+			}
+			hashKey, ok := key.(object.Hashable)
+			if !ok {
+				return false, object.NewKindErrorf(object.TYPE_ERROR, "Unusable as hash key: %s", key.Type())
+			}
+			entry, ok := hash.Get(hashKey)
+			if !ok {
+				return false, nil
+			}
+			if matched, err := matchPattern(pair.Value, entry, bindings, env); !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		literal, err := evalPatternLiteral(pattern, env)
+		if err != nil {
+			return false, err
+		}
+		return objectsEqual(literal, value), nil
+	}
+}
+
+// evalPatternLiteral Evaluates a literal used in a pattern
+func evalPatternLiteral(literal ast.Expression, env *object.Environment) (object.Object, *object.Error) {
+	value := Eval(literal, env)
+	if err, ok := value.(*object.Error); ok {
+		return nil, err
+	}
+	return value, nil
+}
+
+// evalLetStatement Binds the value of a LetStatement to its name
+// or to the names of the pattern destructuring it
+func evalLetStatement(letStmt *ast.LetStatement, env *object.Environment) object.Object {
+	value := Eval(letStmt.Value, env)
+	if isError(value) {
+		return value
+	}
+	if letStmt.Pattern == nil {
+		if _, err := env.Declare(letStmt.Name.Value, value, letStmt.IsConst()); err != nil {
+			return err
+		}
+		return nil
+	}
+	values, err := destructure(letStmt.Pattern, value)
+	if err != nil {
+		return err
+	}
+	for i, name := range letStmt.Pattern.Names() {
+		if _, err := env.Declare(name.Value, values[i], letStmt.IsConst()); err != nil {
+			err.Position = name.Pos()
+			return err
+		}
+	}
+	return nil
+}
+
+// destructure Returns the parts of a value bound to the names of a pattern,
+// in the order of the names. Values not matching the shape of the pattern
+// raise an error positioned at the pattern or at the missing key
+func destructure(pattern ast.Pattern, value object.Object) ([]object.Object, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.ArrayPattern:
+		return destructureArray(pattern, value)
+	case *ast.HashPattern:
+		return destructureHash(pattern, value)
+	default:
+		return nil, object.NewErrorf("Unknown pattern: %s", pattern)
+	}
+}
+
+// destructureArray Returns the elements of an array bound by an ArrayPattern,
+// the array must have as many elements as the pattern unless it has a rest
+func destructureArray(pattern *ast.ArrayPattern, value object.Object) ([]object.Object, *object.Error) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return nil, positionedError(pattern, object.NewKindErrorf(object.TYPE_ERROR,
+			"Cannot destructure %s with an array pattern", value.Type()))
+	}
+	expected, got := len(pattern.Elements), len(array.Elements)
+	if pattern.Rest == nil && got != expected {
+		return nil, positionedError(pattern, object.NewKindErrorf(object.VALUE_ERROR,
+			"Array pattern expects %d elements Got=%d", expected, got))
+	}
+	if got < expected {
+		return nil, positionedError(pattern, object.NewKindErrorf(object.VALUE_ERROR,
+			"Array pattern expects at least %d elements Got=%d", expected, got))
+	}
+	values := make([]object.Object, 0, expected+1)
+	for i, element := range pattern.Elements {
+		if element.Value != ast.WILDCARD {
+			values = append(values, array.Elements[i])
+		}
+	}
+	if pattern.Rest != nil && pattern.Rest.Value != ast.WILDCARD {
+		rest := make([]object.Object, got-expected)
+		copy(rest, array.Elements[expected:])
+		values = append(values, object.NewArray(rest))
+	}
+	return values, nil
+}
+
+// destructureHash Returns the values of a hash stored under the keys of a HashPattern
+func destructureHash(pattern *ast.HashPattern, value object.Object) ([]object.Object, *object.Error) {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return nil, positionedError(pattern, object.NewKindErrorf(object.TYPE_ERROR,
+			"Cannot destructure %s with a hash pattern", value.Type()))
+	}
+	values := make([]object.Object, 0, len(pattern.Keys))
+	for _, key := range pattern.Keys {
+		entry, ok := hash.Get(object.NewString(key.Value))
+		if !ok {
+			return nil, positionedError(key, object.NewKindErrorf(object.KEY_ERROR,
+				"Key not found: %s", key.Value))
+		}
+		values = append(values, entry)
+	}
+	return values, nil
+}
+
+// positionedError Sets the position of an error to the position of a node
+func positionedError(node ast.Node, err *object.Error) *object.Error {
+	err.Position = node.Pos()
+	return err
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"strconv"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+const (
+	QUOTE   = "quote"   // name of the call returning its argument unevaluated
+	UNQUOTE = "unquote" // name of the call evaluating its argument inside a quote
+)
+
+// isCallTo Checks if a CallExpression calls the identifier name, e.g. quote
+func isCallTo(call *ast.CallExpression, name string) bool {
+	id, ok := call.Function.(*ast.Identifier)
+	return ok && id.Value == name
+}
+
+// evalQuote Returns the argument of a quote call as an object.Quote. The
+// argument of each unquote call in it is evaluated and the result converted
+// back into an AST node replacing the call. The quoted AST is a copy, so the
+// same quote can be evaluated any number of times
+func evalQuote(call *ast.CallExpression, env *object.Environment) object.Object {
+	if len(call.Arguments) != 1 {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to %s Expected=1 Got=%d",
+			QUOTE, len(call.Arguments))
+	}
+	var err *object.Error
+	node := ast.Rewrite(ast.Copy(call.Arguments[0]), func(node ast.Node) ast.Node {
+		unquote, ok := node.(*ast.CallExpression)
+		if !ok || !isCallTo(unquote, UNQUOTE) || err != nil {
+			return node
+		}
+		if len(unquote.Arguments) != 1 {
+			err = object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to %s Expected=1 Got=%d",
+				UNQUOTE, len(unquote.Arguments))
+			err.Position = unquote.Pos()
+			return node
+		}
+		value := Eval(unquote.Arguments[0], env)
+		if valueErr, ok := value.(*object.Error); ok {
+			err = valueErr
+			return node
+		}
+		unquoted, convertErr := objectToASTNode(value, unquote.Pos())
+		if convertErr != nil {
+			err = convertErr
+			err.Position = unquote.Pos()
+			return node
+		}
+		return unquoted
+	})
+	if err != nil {
+		return err
+	}
+	return object.NewQuote(node)
+}
+
+// objectToASTNode Converts an evaluated object back into an AST node
+// positioned at pos, quotes are replaced by the node they wrap
+func objectToASTNode(obj object.Object, pos token.Position) (ast.Node, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Quote:
+		return obj.Node, nil
+	case *object.Integer:
+		tok := token.Token{Type: token.INT, Literal: strconv.FormatInt(obj.Value, 10), Position: pos}
+		return &ast.IntegerLiteral{Token: tok, Value: obj.Value}, nil
+	case *object.Boolean:
+		tok := token.Token{Type: token.FALSE, Literal: "false", Position: pos}
+		if obj.Value {
+			tok = token.Token{Type: token.TRUE, Literal: "true", Position: pos}
+		}
+		return ast.NewBoolean(tok), nil
+	case *object.String:
+		tok := token.Token{Type: token.STRING, Literal: obj.Value, Position: pos}
+		return ast.NewStringLiteral(tok), nil
+	case *object.Array:
+		array := ast.NewArrayLiteral(token.Token{Type: token.LBRACKET, Literal: "[", Position: pos})
+		for _, element := range obj.Elements {
+			node, err := objectToASTExpression(element, pos)
+			if err != nil {
+				return nil, err
+			}
+			array.Elements = append(array.Elements, node)
+		}
+		return array, nil
+	case *object.Hash:
+		hash := ast.NewHashLiteral(token.Token{Type: token.LBRACE, Literal: "{", Position: pos})
+		for _, entry := range obj.Entries() {
+			key, err := objectToASTExpression(entry.Key, pos)
+			if err != nil {
+				return nil, err
+			}
+			value, err := objectToASTExpression(entry.Value, pos)
+			if err != nil {
+				return nil, err
+			}
+			hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+		}
+		return hash, nil
+	}
+	return nil, object.NewKindErrorf(object.TYPE_ERROR, "Cannot unquote %s", obj.Type())
+}
+
+// objectToASTExpression Converts an evaluated object into an expression
+func objectToASTExpression(obj object.Object, pos token.Position) (ast.Expression, *object.Error) {
+	node, err := objectToASTNode(obj, pos)
+	if err != nil {
+		return nil, err
+	}
+	expr, ok := node.(ast.Expression)
+	if !ok {
+		return nil, object.NewKindErrorf(object.TYPE_ERROR, "Cannot unquote statement %s", node)
+	}
+	return expr, nil
+}
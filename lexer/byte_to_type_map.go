@@ -14,9 +14,13 @@ func NewByteToTypeMap() ByteToTypeMap {
 		'(': token.LPAREN,
 		')': token.RPAREN,
 		',': token.COMMA,
+		'.': token.DOT,
+		':': token.COLON,
 		'+': token.PLUS,
 		'{': token.LBRACE,
 		'}': token.RBRACE,
+		'[': token.LBRACKET,
+		']': token.RBRACKET,
 		'-': token.MINUS,
 		'*': token.MULTIPLY,
 		'/': token.DIVIDE,
@@ -1,5 +1,13 @@
 package lexer
 
+import "unicode/utf8"
+
+// IsIdentifierChar Checks if a character can be part of an identifier, for
+// finding the identifier around a position in source text without lexing it
+func IsIdentifierChar(char rune) bool {
+	return char < utf8.RuneSelf && isLetter(byte(char))
+}
+
 // isLetter Checks if a given charachter is a letter
 func isLetter(char byte) bool {
 	return isLowerCaseLetter(char) || isUpperCaseLetter(char) || isSpecialCharacter(char)
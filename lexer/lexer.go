@@ -1,6 +1,8 @@
 package lexer
 
 import (
+	"strings"
+
 	"github.com/CzarSimon/monkey/token"
 )
 
@@ -11,6 +13,10 @@ type Lexer struct {
 	position      int  // current position in the input (points to current char)
 	readPosition  int  // current readin gpositon in input (after current char)
 	currentChar   byte // current char under examination
+	filename      string
+	line          int           // line of the current char
+	column        int           // column of the current char
+	comments      []token.Token // comments skipped so far
 	byteToTypeMap ByteToTypeMap
 }
 
@@ -24,24 +30,45 @@ func New(input string) *Lexer {
 	lexer := &Lexer{
 		input:         input,
 		inputLength:   len(input),
+		line:          1,
 		byteToTypeMap: NewByteToTypeMap(),
 	}
 	lexer.readChar()
 	return lexer
 }
 
+// NewWithFilename Creates a new lexer for source read from a file,
+// the positions of the tokens it produces include the file name
+func NewWithFilename(input, filename string) *Lexer {
+	lexer := New(input)
+	lexer.filename = filename
+	return lexer
+}
+
+// Comments Returns the // comments skipped so far, including the slashes, in source order
+func (lexer *Lexer) Comments() []token.Token {
+	return lexer.comments
+}
+
 // NextToken Gets the next token from the input
 func (lexer *Lexer) NextToken() token.Token {
 	lexer.skipWhitespace()
+	position := lexer.currentPosition()
 	nextToken, shouldReadNextChar := lexer.buildNextToken()
 	if shouldReadNextChar {
 		lexer.readChar()
 	}
+	nextToken.Position = position
 	return nextToken
 }
 
 // buildNextToken Constructs the next token and instructs if a further character shold be read
 func (lexer *Lexer) buildNextToken() (token.Token, bool) {
+	if lexer.isEllipsisStart() {
+		lexer.readChar()
+		lexer.readChar()
+		return token.New(token.ELLIPSIS, token.ELLIPSIS), true
+	}
 	tokenType, isPresent := lexer.byteToTypeMap[lexer.currentChar]
 	if isPresent {
 		return token.New(tokenType, lexer.CurrentChar()), true
@@ -49,7 +76,14 @@ func (lexer *Lexer) buildNextToken() (token.Token, bool) {
 	switch lexer.currentChar {
 	case 0:
 		return token.New(token.EOF, ""), true
+	case '"':
+		return lexer.readString(), true
 	case '=':
+		if lexer.peekChar() == '>' {
+			previousChar := lexer.CurrentChar()
+			lexer.readChar()
+			return token.New(token.ARROW, previousChar+lexer.CurrentChar()), true
+		}
 		if lexer.peekChar() != '=' {
 			return token.New(token.ASSIGN, lexer.CurrentChar()), true
 		}
@@ -82,9 +116,23 @@ func (lexer *Lexer) handleDefault() (token.Token, bool) {
 
 // readChar Reads the current char fo the input string
 func (lexer *Lexer) readChar() {
+	if lexer.currentChar == '\n' {
+		lexer.line++
+		lexer.column = 0
+	}
 	lexer.currentChar = lexer.peekChar()
 	lexer.position = lexer.readPosition
 	lexer.readPosition++
+	lexer.column++
+}
+
+// currentPosition Returns the line and column of the current char
+func (lexer *Lexer) currentPosition() token.Position {
+	return token.Position{
+		Filename: lexer.filename,
+		Line:     lexer.line,
+		Column:   lexer.column,
+	}
 }
 
 // peekChar Looks up and retruns the next character in input
@@ -100,6 +148,42 @@ func (lexer *Lexer) readIdentifier() string {
 	return lexer.readType(isLetter)
 }
 
+// readString Reads a double quoted string literal from input, replacing escape
+// sequences with the characters they denote. An unterminated string is ILLEGAL
+func (lexer *Lexer) readString() token.Token {
+	var out strings.Builder
+	for {
+		lexer.readChar()
+		switch lexer.currentChar {
+		case '"':
+			return token.New(token.STRING, out.String())
+		case 0:
+			return token.New(token.ILLEGAL, "\""+out.String())
+		case '\\':
+			if lexer.peekChar() != 0 {
+				lexer.readChar()
+				out.WriteByte(unescape(lexer.currentChar))
+			}
+		default:
+			out.WriteByte(lexer.currentChar)
+		}
+	}
+}
+
+// unescape Returns the character denoted by an escape sequence
+func unescape(char byte) byte {
+	switch char {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	default:
+		return char
+	}
+}
+
 // readNumber Reads a number from input
 func (lexer *Lexer) readNumber() string {
 	return lexer.readType(isDigit)
@@ -114,11 +198,43 @@ func (lexer *Lexer) readType(typeCheck func(char byte) bool) string {
 	return lexer.input[startPosition:lexer.position]
 }
 
-// skipWhitespace Skips over whitespace characters in input
+// skipWhitespace Skips over whitespace characters and comments in input
 func (lexer *Lexer) skipWhitespace() {
-	for isWhitespace(lexer.currentChar) {
+	for isWhitespace(lexer.currentChar) || lexer.isCommentStart() {
+		if lexer.isCommentStart() {
+			lexer.skipComment()
+		} else {
+			lexer.readChar()
+		}
+	}
+}
+
+// isCommentStart Checks if the current char starts a // line comment
+func (lexer *Lexer) isCommentStart() bool {
+	return lexer.currentChar == '/' && lexer.peekChar() == '/'
+}
+
+// isEllipsisStart Checks if the current char starts a ... token
+func (lexer *Lexer) isEllipsisStart() bool {
+	return lexer.currentChar == '.' && strings.HasPrefix(lexer.input[lexer.position:], token.ELLIPSIS)
+}
+
+// skipComment Skips over the rest of the current line and records it as a comment
+func (lexer *Lexer) skipComment() {
+	position := lexer.currentPosition()
+	start := lexer.position
+	for lexer.currentChar != '\n' && lexer.currentChar != 0 {
 		lexer.readChar()
 	}
+	end := lexer.position
+	if end > lexer.inputLength {
+		end = lexer.inputLength
+	}
+	lexer.comments = append(lexer.comments, token.Token{
+		Type:     token.COMMENT,
+		Literal:  lexer.input[start:end],
+		Position: position,
+	})
 }
 
 // isWhitespace Checks if a character is considered a whitespace character
@@ -29,6 +29,7 @@ func TestNextToken(t *testing.T) {
 	}
 	10 == 10;
 	10 != 9;
+	match (x) { _ => 1 }
   `
 	tests := []expectedTokenType{
 		{token.LET, "let"}, {token.IDENT, "five"}, {token.ASSIGN, "="},
@@ -55,7 +56,10 @@ func TestNextToken(t *testing.T) {
 		{token.FALSE, "false"}, {token.SEMICOLON, ";"}, {token.RBRACE, "}"},
 		{token.INT, "10"}, {token.EQ, "=="}, {token.INT, "10"},
 		{token.SEMICOLON, ";"}, {token.INT, "10"}, {token.NOT_EQ, "!="},
-		{token.INT, "9"}, {token.SEMICOLON, ";"}, {token.EOF, ""},
+		{token.INT, "9"}, {token.SEMICOLON, ";"}, {token.MATCH, "match"},
+		{token.LPAREN, "("}, {token.IDENT, "x"}, {token.RPAREN, ")"},
+		{token.LBRACE, "{"}, {token.IDENT, "_"}, {token.ARROW, "=>"},
+		{token.INT, "1"}, {token.RBRACE, "}"}, {token.EOF, ""},
 	}
 	lexer := New(input)
 	for i, tt := range tests {
@@ -73,7 +77,7 @@ func TestNextToken(t *testing.T) {
 
 func TestNextTokenOnEmptyInput(t *testing.T) {
 	lexer := New("")
-	expectedToken := token.Token{token.EOF, ""}
+	expectedToken := token.Token{Type: token.EOF, Literal: ""}
 	tok := lexer.NextToken()
 	if tok.Type != expectedToken.Type {
 		t.Fatalf("tests - tokentype wrong. expected=%q, got=%q",
@@ -95,3 +99,106 @@ func TestCurrentChar(t *testing.T) {
 		t.Fatalf("Wrong CurrentChar (as string): Expected=- got=%s", lexer.CurrentChar())
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "let x = 5;\n  x == 10"
+	expectedPositions := []token.Position{
+		{Line: 1, Column: 1}, {Line: 1, Column: 5}, {Line: 1, Column: 7},
+		{Line: 1, Column: 9}, {Line: 1, Column: 10}, {Line: 2, Column: 3},
+		{Line: 2, Column: 5}, {Line: 2, Column: 8}, {Line: 2, Column: 10},
+	}
+	lexer := New(input)
+	for i, expectedPos := range expectedPositions {
+		tok := lexer.NextToken()
+		if tok.Position != expectedPos {
+			t.Errorf("tests[%d] - Position of %q wrong. expected=%s, got=%s",
+				i, tok.Literal, expectedPos, tok.Position)
+		}
+	}
+}
+
+func TestSkipComments(t *testing.T) {
+	input := `// a comment
+  let x = 10 / 2; // trailing comment
+  //`
+	tests := []expectedTokenType{
+		{token.LET, "let"}, {token.IDENT, "x"}, {token.ASSIGN, "="},
+		{token.INT, "10"}, {token.DIVIDE, "/"}, {token.INT, "2"},
+		{token.SEMICOLON, ";"}, {token.EOF, ""},
+	}
+	lexer := New(input)
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - TokenType wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestStringsAndMembers(t *testing.T) {
+	input := `"foo bar" "a\"b\n" lib.square(...rest, "" "unterminated`
+	tests := []expectedTokenType{
+		{token.STRING, "foo bar"}, {token.STRING, "a\"b\n"}, {token.IDENT, "lib"},
+		{token.DOT, "."}, {token.IDENT, "square"}, {token.LPAREN, "("},
+		{token.ELLIPSIS, "..."}, {token.IDENT, "rest"}, {token.COMMA, ","},
+		{token.STRING, ""}, {token.ILLEGAL, "\"unterminated"}, {token.EOF, ""},
+	}
+	lexer := New(input)
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - TokenType wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestPositionFilename(t *testing.T) {
+	tok := NewWithFilename("\n  x", "lib.monkey").NextToken()
+	if tok.Position.String() != "lib.monkey:2:3" {
+		t.Fatalf("Wrong position, expected=lib.monkey:2:3 got=%s", tok.Position)
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := "// first\nlet s = \"// not a comment\"; // second\n//"
+	lexer := New(input)
+	for tok := lexer.NextToken(); tok.Type != token.EOF; tok = lexer.NextToken() {
+	}
+	expected := []token.Token{
+		{Type: token.COMMENT, Literal: "// first", Position: token.Position{Line: 1, Column: 1}},
+		{Type: token.COMMENT, Literal: "// second", Position: token.Position{Line: 2, Column: 29}},
+		{Type: token.COMMENT, Literal: "//", Position: token.Position{Line: 3, Column: 1}},
+	}
+	comments := lexer.Comments()
+	if len(comments) != len(expected) {
+		t.Fatalf("Wrong number of comments Expected=%d Got=%v", len(expected), comments)
+	}
+	for i, comment := range comments {
+		if comment != expected[i] {
+			t.Errorf("%d - Wrong comment Expected=%+v Got=%+v", i, expected[i], comment)
+		}
+	}
+}
+
+func TestIsIdentifierChar(t *testing.T) {
+	for _, char := range "azAZ_?" {
+		if !IsIdentifierChar(char) {
+			t.Errorf("Expected %q to be an identifier character", char)
+		}
+	}
+	for _, char := range "09-. é😀" {
+		if IsIdentifierChar(char) {
+			t.Errorf("Expected %q not to be an identifier character", char)
+		}
+	}
+}
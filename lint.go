@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lint"
+)
+
+// lintCommand Lints the monkey files passed as arguments, exits with 1 if
+// any warnings or errors were found
+func lintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	disable := flags.String("disable", "", "comma separated list of rules to disable")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: monkey lint [-disable rules] files...")
+		return 2
+	}
+	config := lint.Config{Globals: append(evaluator.BuiltinNames(), evaluator.QUOTE, evaluator.UNQUOTE)}
+	if *disable != "" {
+		for _, name := range strings.Split(*disable, ",") {
+			rule := lint.Rule(strings.TrimSpace(name))
+			if !rule.IsValid() {
+				fmt.Fprintf(os.Stderr, "Unknown rule: %s\n", rule)
+				return 2
+			}
+			config.Disabled = append(config.Disabled, rule)
+		}
+	}
+	linter := lint.New(config)
+	exitCode := 0
+	for _, filename := range flags.Args() {
+		source, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+			continue
+		}
+		warnings, errs := linter.LintSource(filename, string(source))
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", filename, err)
+		}
+		for _, warning := range warnings {
+			fmt.Println(warning)
+		}
+		if len(errs) != 0 || len(warnings) != 0 {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
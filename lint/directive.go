@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// directivePattern Matches comments of the form: // lint:disable rule-a, rule-b
+var directivePattern = regexp.MustCompile(`^//\s*lint:disable\b(.*)`)
+
+// parseDirectives Finds the rules disabled for a file by the lint:disable
+// comments among the comments of its source, text in string literals is not
+// a comment. A directive without any listed rules disables all of them
+func parseDirectives(comments []token.Token) map[Rule]bool {
+	disabled := make(map[Rule]bool)
+	for _, comment := range comments {
+		match := directivePattern.FindStringSubmatch(comment.Literal)
+		if match == nil {
+			continue
+		}
+		names := strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\r'
+		})
+		if len(names) == 0 {
+			for _, rule := range Rules {
+				disabled[rule] = true
+			}
+		}
+		for _, name := range names {
+			disabled[Rule(name)] = true
+		}
+	}
+	return disabled
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+type expectedWarning struct {
+	rule     Rule
+	position token.Position
+}
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedWarning
+	}{
+		{"let x = 1; x;", []expectedWarning{}},
+		{"let x = 1;", []expectedWarning{{UNUSED_BINDING, token.Position{Line: 1, Column: 5}}}},
+		{"let x = 1; let x = 2; x;", []expectedWarning{{UNUSED_BINDING, token.Position{Line: 1, Column: 5}}}},
+		{"let x = 1; let x = x + 1; x;", []expectedWarning{}},
+		{"y;", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 1}}}},
+		{"add(1, 2);", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 1}}}},
+		{"x; let x = 1; x;", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 1}}}},
+		{"let f = fn(n) { f(n - 1) }; f(1);", []expectedWarning{}},
+		{"let f = fn() { g() }; let g = fn() { 1 }; f();", []expectedWarning{}},
+		{"let f = fn() { let y = 1; 2 }; f();", []expectedWarning{{UNUSED_BINDING, token.Position{Line: 1, Column: 20}}}},
+		{"let x = 1; let f = fn(x) { x }; f(x);", []expectedWarning{{SHADOWED_PARAM, token.Position{Line: 1, Column: 23}}}},
+		{"let f = fn(x) { fn(x) { x } }; f(1);", []expectedWarning{{SHADOWED_PARAM, token.Position{Line: 1, Column: 20}}}},
+		{"return 1; 2;", []expectedWarning{{UNREACHABLE_CODE, token.Position{Line: 1, Column: 11}}}},
+		{"let f = fn() {\n return 1;\n 2;\n}; f();", []expectedWarning{{UNREACHABLE_CODE, token.Position{Line: 3, Column: 2}}}},
+		{"let f = fn(x) { if (x) { return 1 } else { return 2 } 3 }; f(true);",
+			[]expectedWarning{{UNREACHABLE_CODE, token.Position{Line: 1, Column: 55}}}},
+		{"let f = fn(x) { if (x) { return 1 } 3 }; f(true);", []expectedWarning{}},
+		{"if (true) { let z = 1; } z;", []expectedWarning{}},
+		{"export let x = 1;", []expectedWarning{}},
+		{`let m = import "m.monkey"; m.value;`, []expectedWarning{}},
+		{"try { 1 } catch (e) { e }", []expectedWarning{}},
+		{"f(); fn f() { g() } fn g() { f() }", []expectedWarning{}},
+		{"fn f(x) { x }", []expectedWarning{{UNUSED_BINDING, token.Position{Line: 1, Column: 4}}}},
+		{"try { 1 } catch (e) { 2 }; e;", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 28}}}},
+		{"try { throw x } catch (e) { let y = 1; }", []expectedWarning{
+			{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 13}},
+			{UNUSED_BINDING, token.Position{Line: 1, Column: 33}},
+		}},
+		{"match (1) { [a, _] if a => a, b => c }", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 36}}}},
+		{"match (1) { a => 1 }; a;", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 23}}}},
+		{"let [a, _, ...b] = [1]; a;", []expectedWarning{{UNUSED_BINDING, token.Position{Line: 1, Column: 15}}}},
+		{"let {a, b} = c; a + b;", []expectedWarning{{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 14}}}},
+		{"let a = 1;\nb;", []expectedWarning{
+			{UNUSED_BINDING, token.Position{Line: 1, Column: 5}},
+			{UNDEFINED_IDENTIFIER, token.Position{Line: 2, Column: 1}},
+		}},
+	}
+	linter := New(Config{})
+	for i, test := range tests {
+		warnings, errs := linter.LintSource("test.monkey", test.input)
+		if len(errs) != 0 {
+			t.Fatalf("%d - Unexpected parse errors: %v", i, errs)
+		}
+		testWarnings(t, i, warnings, test.expected)
+	}
+}
+
+func TestLintDisabledRules(t *testing.T) {
+	input := "let x = 1; y;"
+	linter := New(Config{Disabled: []Rule{UNUSED_BINDING}})
+	warnings, _ := linter.LintSource("test.monkey", input)
+	testWarnings(t, 0, warnings, []expectedWarning{
+		{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 12}},
+	})
+
+	input = "// lint:disable undefined-identifier\nlet x = 1; y;"
+	warnings, _ = New(Config{}).LintSource("test.monkey", input)
+	testWarnings(t, 1, warnings, []expectedWarning{
+		{UNUSED_BINDING, token.Position{Line: 2, Column: 5}},
+	})
+
+	input = "let x = 1; y; // lint:disable"
+	warnings, _ = New(Config{}).LintSource("test.monkey", input)
+	testWarnings(t, 2, warnings, []expectedWarning{})
+
+	input = "let s = \"// lint:disable\"; y;"
+	warnings, _ = New(Config{}).LintSource("test.monkey", input)
+	testWarnings(t, 3, warnings, []expectedWarning{
+		{UNUSED_BINDING, token.Position{Line: 1, Column: 5}},
+		{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 28}},
+	})
+}
+
+func TestLintGlobals(t *testing.T) {
+	input := "let f = fn(puts) { puts }; f(len(1));"
+	linter := New(Config{Globals: []string{"len", "puts"}})
+	warnings, _ := linter.LintSource("test.monkey", input)
+	testWarnings(t, 0, warnings, []expectedWarning{
+		{SHADOWED_PARAM, token.Position{Line: 1, Column: 12}},
+	})
+	input = "let m = macro(x, y) { quote(unquote(x) + z) }; m(1, 2);"
+	linter = New(Config{Globals: []string{"quote", "unquote"}})
+	warnings, _ = linter.LintSource("test.monkey", input)
+	testWarnings(t, 1, warnings, []expectedWarning{
+		{UNDEFINED_IDENTIFIER, token.Position{Line: 1, Column: 42}},
+	})
+}
+
+func TestLintParseErrors(t *testing.T) {
+	warnings, errs := New(Config{}).LintSource("test.monkey", "let = 5;")
+	if len(errs) == 0 {
+		t.Fatalf("Expected parse errors Got none")
+	}
+	if warnings != nil {
+		t.Fatalf("Expected no warnings Got=%v", warnings)
+	}
+}
+
+func TestWarningString(t *testing.T) {
+	warning := Warning{
+		File:     "main.monkey",
+		Position: token.Position{Line: 3, Column: 7},
+		Rule:     UNUSED_BINDING,
+		Message:  "x is bound but never used",
+	}
+	expected := "main.monkey:3:7: x is bound but never used [unused-binding]"
+	if warning.String() != expected {
+		t.Fatalf("Wrong warning.String() Expected=[ %s ] Got=[ %s ]", expected, warning.String())
+	}
+}
+
+func testWarnings(t *testing.T, i int, warnings []Warning, expected []expectedWarning) {
+	if len(warnings) != len(expected) {
+		t.Errorf("%d - Wrong number of warnings Expected=%d Got=%d - %v",
+			i, len(expected), len(warnings), warnings)
+		return
+	}
+	for j, warning := range warnings {
+		if warning.Rule != expected[j].rule {
+			t.Errorf("%d - Wrong rule Expected=%s Got=%s", i, expected[j].rule, warning.Rule)
+		}
+		if warning.Position != expected[j].position {
+			t.Errorf("%d - Wrong position Expected=%s Got=%s",
+				i, expected[j].position, warning.Position)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+// Config Options controlling which rules are checked
+type Config struct {
+	Disabled []Rule   // rules not reported for any file
+	Globals  []string // names bound before the program runs, e.g. builtins
+}
+
+// Linter Static checker of monkey programs
+type Linter struct {
+	config Config
+}
+
+// New Creates a new Linter based on a supplied config
+func New(config Config) *Linter {
+	return &Linter{
+		config: config,
+	}
+}
+
+// LintSource Parses and lints source code read from a file, disable directives
+// in the source are respected. Parse errors are returned instead of warnings
+func (linter *Linter) LintSource(filename, source string) ([]Warning, []error) {
+	l := lexer.New(source)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, p.Errors()
+	}
+	return linter.lint(filename, program, parseDirectives(l.Comments())), nil
+}
+
+// Lint Checks a parsed program and returns the warnings found sorted by position
+func (linter *Linter) Lint(filename string, program *ast.Program) []Warning {
+	return linter.lint(filename, program, make(map[Rule]bool))
+}
+
+// lint Checks a program with a set of rules disabled in addition to the configured ones
+func (linter *Linter) lint(filename string, program *ast.Program, disabled map[Rule]bool) []Warning {
+	for _, rule := range linter.config.Disabled {
+		disabled[rule] = true
+	}
+	c := &checker{
+		filename: filename,
+		disabled: disabled,
+		warnings: make([]Warning, 0),
+	}
+	globals := newScope(nil)
+	for _, name := range linter.config.Globals {
+		globals.declare(&binding{name: name, kind: globalBinding})
+	}
+	c.checkScope(newScope(globals), program)
+	sort.SliceStable(c.warnings, func(i, j int) bool {
+		a, b := c.warnings[i].Position, c.warnings[j].Position
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return c.warnings
+}
+
+// checker State of a single lint run
+type checker struct {
+	filename string
+	disabled map[Rule]bool
+	warnings []Warning
+}
+
+// report Records a warning unless its rule is disabled
+func (c *checker) report(node ast.Node, rule Rule, format string, a ...interface{}) {
+	if c.disabled[rule] {
+		return
+	}
+	c.warnings = append(c.warnings, Warning{
+		File:     c.filename,
+		Position: node.Pos(),
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// checkScope Checks the node in the supplied scope and then the bodies of the
+// functions defined in it. Function bodies are checked last since they are
+// evaluated when called, at which point the whole enclosing scope is bound
+func (c *checker) checkScope(s *scope, node ast.Node) {
+	ast.Walk(&scopeVisitor{checker: c, scope: s}, node)
+	for i := 0; i < len(s.deferred); i++ {
+		c.checkFunction(s, s.deferred[i])
+	}
+	for _, b := range s.bindings {
+		c.checkUsed(b)
+	}
+}
+
+// checkFunction Checks the body of a function or macro literal in a new scope enclosed by outer
+func (c *checker) checkFunction(outer *scope, fn ast.Expression) {
+	var params []*ast.Identifier
+	var body *ast.BlockStatement
+	switch fn := fn.(type) {
+	case *ast.FunctionLiteral:
+		params, body = fn.Parameters, fn.Body
+	case *ast.MacroLiteral:
+		params, body = fn.Parameters, fn.Body
+	}
+	s := newScope(outer)
+	for _, param := range params {
+		if shadowed, ok := outer.resolve(param.Value); ok {
+			c.report(param, SHADOWED_PARAM, "Parameter %s shadows %s",
+				param.Value, describeBinding(shadowed))
+		}
+		s.declare(&binding{name: param.Value, kind: paramBinding, position: param.Pos()})
+	}
+	if body != nil {
+		c.checkScope(s, body)
+	}
+}
+
+// checkUsed Reports a let binding that was never referenced
+func (c *checker) checkUsed(b *binding) {
+	if b.kind != letBinding || b.used || c.disabled[UNUSED_BINDING] {
+		return
+	}
+	c.warnings = append(c.warnings, Warning{
+		File:     c.filename,
+		Position: b.position,
+		Rule:     UNUSED_BINDING,
+		Message:  fmt.Sprintf("%s is bound but never used", b.name),
+	})
+}
+
+// checkReachable Reports the first statement following a statement that always returns
+func (c *checker) checkReachable(stmts []ast.Statement) {
+	for i, stmt := range stmts {
+		if alwaysReturns(stmt) && i+1 < len(stmts) {
+			c.report(stmts[i+1], UNREACHABLE_CODE, "Unreachable code after return")
+			return
+		}
+	}
+}
+
+// scopeVisitor ast.Visitor resolving and binding names in a scope
+type scopeVisitor struct {
+	checker *checker
+	scope   *scope
+}
+
+// Visit Handles the nodes that bind or reference names
+func (v *scopeVisitor) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.Program:
+		v.checker.checkReachable(node.Statements)
+		v.hoist(node.Statements)
+	case *ast.BlockStatement:
+		v.checker.checkReachable(node.Statements)
+		v.hoist(node.Statements)
+	case *ast.LetStatement:
+		v.bind(node, false)
+		return nil
+	case *ast.ExportStatement:
+		if node.Statement != nil {
+			v.bind(node.Statement, true)
+		}
+		return nil
+	case *ast.MemberExpression:
+		if node.Object != nil {
+			ast.Walk(v, node.Object)
+		}
+		return nil
+	case *ast.Identifier:
+		if b, ok := v.scope.resolve(node.Value); ok {
+			b.used = true
+		} else {
+			v.checker.report(node, UNDEFINED_IDENTIFIER, "Identifier not found: %s", node.Value)
+		}
+		return nil
+	case *ast.FunctionLiteral, *ast.MacroLiteral:
+		v.scope.deferred = append(v.scope.deferred, node.(ast.Expression))
+		return nil
+	case *ast.FunctionStatement:
+		if node.Function != nil {
+			v.scope.deferred = append(v.scope.deferred, node.Function)
+		}
+		return nil
+	case *ast.TryExpression:
+		v.checkTry(node)
+		return nil
+	case *ast.MatchExpression:
+		v.checkMatch(node)
+		return nil
+	}
+	return v
+}
+
+// checkTry Checks a TryExpression, the catch block is checked in a scope
+// of its own where the caught error is bound
+func (v *scopeVisitor) checkTry(tryExpr *ast.TryExpression) {
+	if tryExpr.Body != nil {
+		ast.Walk(v, tryExpr.Body)
+	}
+	if tryExpr.Catch != nil {
+		s := newScope(v.scope)
+		param := tryExpr.CatchParam
+		s.declare(&binding{name: param.Value, kind: paramBinding, position: param.Pos()})
+		v.checker.checkScope(s, tryExpr.Catch)
+	}
+	if tryExpr.Finally != nil {
+		ast.Walk(v, tryExpr.Finally)
+	}
+}
+
+// checkMatch Checks a MatchExpression, the guard and body of each arm are
+// checked in a scope of their own where the names of the pattern are bound
+func (v *scopeVisitor) checkMatch(matchExpr *ast.MatchExpression) {
+	if matchExpr.Subject != nil {
+		ast.Walk(v, matchExpr.Subject)
+	}
+	for _, arm := range matchExpr.Arms {
+		s := newScope(v.scope)
+		ast.Inspect(arm.Pattern, func(node ast.Node) bool {
+			if id, ok := node.(*ast.Identifier); ok && id.Value != ast.WILDCARD {
+				s.declare(&binding{name: id.Value, kind: paramBinding, position: id.Pos()})
+			}
+			return true
+		})
+		armVisitor := &scopeVisitor{checker: v.checker, scope: s}
+		if arm.Guard != nil {
+			ast.Walk(armVisitor, arm.Guard)
+		}
+		if arm.Body != nil {
+			ast.Walk(armVisitor, arm.Body)
+		}
+		for i := 0; i < len(s.deferred); i++ {
+			v.checker.checkFunction(s, s.deferred[i])
+		}
+	}
+}
+
+// bind Declares the names of a let statement after checking its value,
+// exported bindings are used by importers and therefore never unused
+func (v *scopeVisitor) bind(letStmt *ast.LetStatement, exported bool) {
+	if letStmt.Value != nil {
+		ast.Walk(v, letStmt.Value)
+	}
+	for _, name := range letStmt.Names() {
+		previous := v.scope.declare(&binding{
+			name:     name.Value,
+			kind:     letBinding,
+			position: name.Pos(),
+			used:     exported,
+		})
+		if previous != nil {
+			v.checker.checkUsed(previous)
+		}
+	}
+}
+
+// hoist Declares the names of the function statements in a block, which
+// are bound before any statement in the block is evaluated
+func (v *scopeVisitor) hoist(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		fnStmt, ok := stmt.(*ast.FunctionStatement)
+		if !ok {
+			continue
+		}
+		previous := v.scope.declare(&binding{
+			name:     fnStmt.Name.Value,
+			kind:     letBinding,
+			position: fnStmt.Name.Pos(),
+		})
+		if previous != nil {
+			v.checker.checkUsed(previous)
+		}
+	}
+}
+
+// alwaysReturns Checks if a statement returns on every path through it
+func alwaysReturns(stmt ast.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStatement:
+		return true
+	case *ast.ExpressionStatement:
+		ifExpr, ok := stmt.Expression.(*ast.IFExpression)
+		if !ok || ifExpr.Consequence == nil || ifExpr.Alternative == nil {
+			return false
+		}
+		return blockAlwaysReturns(ifExpr.Consequence) && blockAlwaysReturns(ifExpr.Alternative)
+	}
+	return false
+}
+
+// blockAlwaysReturns Checks if any statement in a block always returns
+func blockAlwaysReturns(block *ast.BlockStatement) bool {
+	for _, stmt := range block.Statements {
+		if alwaysReturns(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeBinding Returns a description of a binding used in warning messages
+func describeBinding(b *binding) string {
+	switch b.kind {
+	case globalBinding:
+		return "global " + b.name
+	case paramBinding:
+		return fmt.Sprintf("parameter declared at %s", b.position)
+	default:
+		return fmt.Sprintf("binding declared at %s", b.position)
+	}
+}
@@ -0,0 +1,30 @@
+package lint
+
+// Rule Identifier of a check performed by the linter
+type Rule string
+
+// Rules reported by the linter
+const (
+	UNUSED_BINDING       Rule = "unused-binding"
+	SHADOWED_PARAM       Rule = "shadowed-param"
+	UNDEFINED_IDENTIFIER Rule = "undefined-identifier"
+	UNREACHABLE_CODE     Rule = "unreachable-code"
+)
+
+// Rules All rules known by the linter
+var Rules = []Rule{
+	UNUSED_BINDING,
+	SHADOWED_PARAM,
+	UNDEFINED_IDENTIFIER,
+	UNREACHABLE_CODE,
+}
+
+// IsValid Checks if the rule is known by the linter
+func (rule Rule) IsValid() bool {
+	for _, known := range Rules {
+		if rule == known {
+			return true
+		}
+	}
+	return false
+}
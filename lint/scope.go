@@ -0,0 +1,57 @@
+package lint
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// bindingKind Describes how a name was bound
+type bindingKind int
+
+const (
+	letBinding bindingKind = iota
+	paramBinding
+	globalBinding
+)
+
+// binding A name bound in a scope
+type binding struct {
+	name     string
+	kind     bindingKind
+	position token.Position
+	used     bool
+}
+
+// scope Static counterpart of an object.Environment. Just like at runtime
+// only function bodies create a new scope, blocks bind into the enclosing one
+type scope struct {
+	bindings map[string]*binding
+	outer    *scope
+	deferred []ast.Expression // function and macro literals checked after the scope
+}
+
+// newScope Creates an empty scope enclosed by outer
+func newScope(outer *scope) *scope {
+	return &scope{
+		bindings: make(map[string]*binding),
+		outer:    outer,
+		deferred: make([]ast.Expression, 0),
+	}
+}
+
+// resolve Looks up a binding in the scope chain
+func (s *scope) resolve(name string) (*binding, bool) {
+	for current := s; current != nil; current = current.outer {
+		if b, ok := current.bindings[name]; ok {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// declare Binds a name in the scope, returning the binding it replaced if any
+func (s *scope) declare(b *binding) *binding {
+	previous := s.bindings[b.name]
+	s.bindings[b.name] = b
+	return previous
+}
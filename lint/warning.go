@@ -0,0 +1,21 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// Warning Issue found by the linter at a position in a file
+type Warning struct {
+	File     string
+	Position token.Position
+	Rule     Rule
+	Message  string
+}
+
+// String Returns a file:line:column prefixed description of the warning
+func (warning Warning) String() string {
+	return fmt.Sprintf("%s:%s: %s [%s]",
+		warning.File, warning.Position, warning.Message, warning.Rule)
+}
@@ -0,0 +1,275 @@
+package lsp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// document Open document with the program parsed from its text
+type document struct {
+	uri     string
+	lines   []string
+	program *ast.Program
+	errors  []error
+	index   *index
+}
+
+// newDocument Parses and indexes the text of a document. The statements
+// parsed before and after parse errors are indexed as well
+func newDocument(uri, text string) *document {
+	p := parser.New(lexer.New(text))
+	program := p.ParseProgram()
+	return &document{
+		uri:     uri,
+		lines:   strings.Split(text, "\n"),
+		program: program,
+		errors:  p.Errors(),
+		index:   newIndex(program),
+	}
+}
+
+// diagnostics Returns the parse errors of the document as diagnostics
+func (doc *document) diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(doc.errors))
+	for _, err := range doc.errors {
+		pos, message := token.Position{Line: 1, Column: 1}, err.Error()
+		if parseErr, ok := err.(*parser.ParseError); ok && parseErr.Position.IsValid() {
+			pos, message = parseErr.Position, parseErr.Message
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    doc.span(pos, 1),
+			Severity: SEVERITY_ERROR,
+			Source:   "monkey",
+			Message:  message,
+		})
+	}
+	return diagnostics
+}
+
+// position Converts a one based token position to a zero based LSP position,
+// whose characters are UTF-16 code units where token columns count bytes
+func (doc *document) position(pos token.Position) Position {
+	return Position{Line: pos.Line - 1, Character: utf16Length(doc.line(pos.Line-1), pos.Column-1)}
+}
+
+// tokenPosition Converts a zero based LSP position to a one based token position
+func (doc *document) tokenPosition(pos Position) token.Position {
+	return token.Position{Line: pos.Line + 1, Column: byteOffset(doc.line(pos.Line), pos.Character) + 1}
+}
+
+// span Returns the range of length bytes starting at a token position
+func (doc *document) span(pos token.Position, length int) Range {
+	end := pos
+	end.Column += length
+	return Range{Start: doc.position(pos), End: doc.position(end)}
+}
+
+// line Returns a zero based line of the text, empty if there is no such line
+func (doc *document) line(i int) string {
+	if i < 0 || i >= len(doc.lines) {
+		return ""
+	}
+	return doc.lines[i]
+}
+
+// location Returns the location of an identifier in the document
+func (doc *document) location(id *ast.Identifier) Location {
+	return Location{URI: doc.uri, Range: doc.span(id.Pos(), len(id.Value))}
+}
+
+// definition Returns the location of the binding of the identifier at a position
+func (doc *document) definition(pos Position) (*Location, bool) {
+	def, ok := doc.index.definitionAt(doc.tokenPosition(pos))
+	if !ok {
+		return nil, false
+	}
+	location := doc.location(def.id)
+	return &location, true
+}
+
+// references Returns the locations of the identifiers referring to the
+// same binding as the identifier at a position
+func (doc *document) references(pos Position, includeDeclaration bool) []Location {
+	locations := make([]Location, 0)
+	def, ok := doc.index.definitionAt(doc.tokenPosition(pos))
+	if !ok {
+		return locations
+	}
+	if includeDeclaration {
+		locations = append(locations, doc.location(def.id))
+	}
+	for _, ref := range def.references {
+		locations = append(locations, doc.location(ref))
+	}
+	return locations
+}
+
+// hover Describes the binding of the identifier at a position, functions
+// are described by their signature
+func (doc *document) hover(pos Position) (*Hover, bool) {
+	id, ok := doc.index.at(doc.tokenPosition(pos))
+	if !ok {
+		return nil, false
+	}
+	var description string
+	if def, ok := doc.index.resolved[id]; ok {
+		description = describe(def)
+	} else if isBuiltin(id.Value) {
+		description = "builtin function " + id.Value
+	} else {
+		return nil, false
+	}
+	idRange := doc.span(id.Pos(), len(id.Value))
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: "```monkey\n" + description + "\n```"},
+		Range:    &idRange,
+	}, true
+}
+
+// symbols Returns the top level bindings of the document in source order
+func (doc *document) symbols() []DocumentSymbol {
+	symbols := make([]DocumentSymbol, 0)
+	for _, def := range doc.index.definitions {
+		if !def.topLevel || def.kind == paramDefinition {
+			continue
+		}
+		kind := SYMBOL_VARIABLE
+		if isFunction(def) {
+			kind = SYMBOL_FUNCTION
+		} else if def.kind == constDefinition {
+			kind = SYMBOL_CONSTANT
+		}
+		idRange := doc.span(def.id.Pos(), len(def.name))
+		symbols = append(symbols, DocumentSymbol{
+			Name:           def.name,
+			Detail:         describe(def),
+			Kind:           kind,
+			Range:          idRange,
+			SelectionRange: idRange,
+		})
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		a, b := symbols[i].Range.Start, symbols[j].Range.Start
+		return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+	})
+	return symbols
+}
+
+// completions Returns the keywords, builtins and names bound in the document
+// starting with the identifier prefix preceding a position
+func (doc *document) completions(pos Position) []CompletionItem {
+	prefix := doc.prefixAt(doc.tokenPosition(pos))
+	items := make([]CompletionItem, 0)
+	seen := make(map[string]bool)
+	add := func(label string, kind int, detail string) {
+		if strings.HasPrefix(label, prefix) && !seen[label] {
+			seen[label] = true
+			items = append(items, CompletionItem{Label: label, Kind: kind, Detail: detail})
+		}
+	}
+	for _, def := range doc.index.definitions {
+		if isFunction(def) {
+			add(def.name, COMPLETION_FUNCTION, describe(def))
+		} else {
+			add(def.name, COMPLETION_VARIABLE, describe(def))
+		}
+	}
+	for _, name := range evaluator.BuiltinNames() {
+		add(name, COMPLETION_FUNCTION, "builtin function "+name)
+	}
+	for _, keyword := range token.Keywords() {
+		add(keyword, COMPLETION_KEYWORD, "")
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return items
+}
+
+// prefixAt Returns the part of an identifier preceding a position
+func (doc *document) prefixAt(pos token.Position) string {
+	line := doc.line(pos.Line - 1)
+	end := pos.Column - 1
+	if end > len(line) {
+		end = len(line)
+	}
+	start := end
+	for start > 0 && lexer.IsIdentifierChar(rune(line[start-1])) {
+		start--
+	}
+	return line[start:end]
+}
+
+// describe Returns the signature of a function binding or the kind and name of other bindings
+func describe(def *definition) string {
+	switch value := def.value.(type) {
+	case *ast.FunctionLiteral:
+		return object.NewFunction(value, nil).Inspect()
+	case *ast.MacroLiteral:
+		return object.NewMacro(def.name, value, nil).Inspect()
+	}
+	switch def.kind {
+	case constDefinition:
+		return "const " + def.name
+	case paramDefinition:
+		return "parameter " + def.name
+	default:
+		return "let " + def.name
+	}
+}
+
+// isFunction Checks if a definition binds a function
+func isFunction(def *definition) bool {
+	_, ok := def.value.(*ast.FunctionLiteral)
+	return ok || def.kind == functionDefinition
+}
+
+// isBuiltin Checks if a name is the name of a builtin function
+func isBuiltin(name string) bool {
+	for _, builtin := range evaluator.BuiltinNames() {
+		if builtin == name {
+			return true
+		}
+	}
+	return false
+}
+
+// utf16Length Returns the number of UTF-16 code units encoding the first n bytes
+// of a line, bytes past its end count as one unit each
+func utf16Length(line string, n int) int {
+	units := 0
+	for offset, char := range line {
+		if offset >= n {
+			return units
+		}
+		units += utf16Units(char)
+	}
+	return units + n - len(line)
+}
+
+// byteOffset Returns the number of bytes encoding the first units UTF-16 code
+// units of a line, units past its end count as one byte each
+func byteOffset(line string, units int) int {
+	for offset, char := range line {
+		if units <= 0 {
+			return offset
+		}
+		units -= utf16Units(char)
+	}
+	return len(line) + units
+}
+
+// utf16Units Returns the number of UTF-16 code units encoding a character
+func utf16Units(char rune) int {
+	if char >= 0x10000 {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,269 @@
+package lsp
+
+import (
+	"sort"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// definitionKind Describes how a name was bound
+type definitionKind int
+
+const (
+	letDefinition definitionKind = iota
+	constDefinition
+	functionDefinition
+	paramDefinition
+)
+
+// definition A name bound in a document and the identifiers referring to it
+type definition struct {
+	name       string
+	kind       definitionKind
+	id         *ast.Identifier
+	value      ast.Expression // value bound by let, const and function statements
+	topLevel   bool
+	references []*ast.Identifier
+}
+
+// index Definitions of a document and the definition each identifier resolves to
+type index struct {
+	definitions []*definition
+	resolved    map[*ast.Identifier]*definition
+	identifiers []*ast.Identifier // all identifiers in source order
+}
+
+// newIndex Resolves the identifiers of a program. Scoping follows the evaluator:
+// function bodies get a scope of their own and are resolved after the enclosing
+// scope, since they are evaluated when called with all enclosing bindings made
+func newIndex(program *ast.Program) *index {
+	idx := &index{
+		definitions: make([]*definition, 0),
+		resolved:    make(map[*ast.Identifier]*definition),
+		identifiers: make([]*ast.Identifier, 0),
+	}
+	idx.resolveScope(newScope(nil), program)
+	sort.SliceStable(idx.identifiers, func(i, j int) bool {
+		return isBefore(idx.identifiers[i].Pos(), idx.identifiers[j].Pos())
+	})
+	return idx
+}
+
+// at Returns the identifier at a position in the document
+func (idx *index) at(pos token.Position) (*ast.Identifier, bool) {
+	for _, id := range idx.identifiers {
+		start := id.Pos()
+		if start.Line == pos.Line && start.Column <= pos.Column &&
+			pos.Column <= start.Column+len(id.Value) {
+			return id, true
+		}
+	}
+	return nil, false
+}
+
+// definitionAt Returns the definition of the identifier at a position
+func (idx *index) definitionAt(pos token.Position) (*definition, bool) {
+	id, ok := idx.at(pos)
+	if !ok {
+		return nil, false
+	}
+	def, ok := idx.resolved[id]
+	return def, ok
+}
+
+// scope Bindings visible in a function body or the program
+type scope struct {
+	bindings map[string]*definition
+	outer    *scope
+	deferred []ast.Expression
+}
+
+// newScope Creates an empty scope enclosed by outer
+func newScope(outer *scope) *scope {
+	return &scope{
+		bindings: make(map[string]*definition),
+		outer:    outer,
+		deferred: make([]ast.Expression, 0),
+	}
+}
+
+// resolve Looks up a definition in the scope chain
+func (s *scope) resolve(name string) (*definition, bool) {
+	for current := s; current != nil; current = current.outer {
+		if def, ok := current.bindings[name]; ok {
+			return def, true
+		}
+	}
+	return nil, false
+}
+
+// resolveScope Resolves a node in a scope followed by the bodies of the functions defined in it
+func (idx *index) resolveScope(s *scope, node ast.Node) {
+	ast.Walk(&indexVisitor{index: idx, scope: s}, node)
+	idx.resolveDeferred(s)
+}
+
+// resolveDeferred Resolves the bodies of the functions defined in a scope
+func (idx *index) resolveDeferred(s *scope) {
+	for i := 0; i < len(s.deferred); i++ {
+		var params []*ast.Identifier
+		var body *ast.BlockStatement
+		switch fn := s.deferred[i].(type) {
+		case *ast.FunctionLiteral:
+			params, body = fn.Parameters, fn.Body
+		case *ast.MacroLiteral:
+			params, body = fn.Parameters, fn.Body
+		}
+		fnScope := newScope(s)
+		for _, param := range params {
+			idx.define(fnScope, param, paramDefinition, nil)
+		}
+		if body != nil {
+			idx.resolveScope(fnScope, body)
+		}
+	}
+}
+
+// define Binds an identifier in a scope
+func (idx *index) define(s *scope, id *ast.Identifier, kind definitionKind, value ast.Expression) {
+	def := &definition{
+		name:     id.Value,
+		kind:     kind,
+		id:       id,
+		value:    value,
+		topLevel: s.outer == nil,
+	}
+	s.bindings[id.Value] = def
+	idx.definitions = append(idx.definitions, def)
+	idx.resolved[id] = def
+	idx.identifiers = append(idx.identifiers, id)
+}
+
+// reference Resolves an identifier referring to a binding
+func (idx *index) reference(s *scope, id *ast.Identifier) {
+	idx.identifiers = append(idx.identifiers, id)
+	if def, ok := s.resolve(id.Value); ok {
+		def.references = append(def.references, id)
+		idx.resolved[id] = def
+	}
+}
+
+// indexVisitor ast.Visitor binding and resolving names in a scope
+type indexVisitor struct {
+	index *index
+	scope *scope
+}
+
+// Visit Handles the nodes that bind or reference names
+func (v *indexVisitor) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.Program:
+		v.hoist(node.Statements)
+	case *ast.BlockStatement:
+		v.hoist(node.Statements)
+	case *ast.LetStatement:
+		v.bind(node)
+		return nil
+	case *ast.ExportStatement:
+		if node.Statement != nil {
+			v.bind(node.Statement)
+		}
+		return nil
+	case *ast.FunctionStatement:
+		if node.Function != nil {
+			v.scope.deferred = append(v.scope.deferred, node.Function)
+		}
+		return nil
+	case *ast.FunctionLiteral, *ast.MacroLiteral:
+		v.scope.deferred = append(v.scope.deferred, node.(ast.Expression))
+		return nil
+	case *ast.MemberExpression:
+		if node.Object != nil {
+			ast.Walk(v, node.Object)
+		}
+		return nil
+	case *ast.Identifier:
+		v.index.reference(v.scope, node)
+		return nil
+	case *ast.TryExpression:
+		v.resolveTry(node)
+		return nil
+	case *ast.MatchExpression:
+		v.resolveMatch(node)
+		return nil
+	}
+	return v
+}
+
+// bind Defines the names of a let statement after resolving its value
+func (v *indexVisitor) bind(letStmt *ast.LetStatement) {
+	if letStmt.Value != nil {
+		ast.Walk(v, letStmt.Value)
+	}
+	kind := letDefinition
+	if letStmt.IsConst() {
+		kind = constDefinition
+	}
+	for _, name := range letStmt.Names() {
+		var value ast.Expression
+		if name == letStmt.Name {
+			value = letStmt.Value
+		}
+		v.index.define(v.scope, name, kind, value)
+	}
+}
+
+// hoist Defines the function statements of a block before its other statements
+func (v *indexVisitor) hoist(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if fnStmt, ok := stmt.(*ast.FunctionStatement); ok && fnStmt.Name != nil {
+			v.index.define(v.scope, fnStmt.Name, functionDefinition, fnStmt.Function)
+		}
+	}
+}
+
+// resolveTry Resolves a TryExpression, the catch parameter is bound in a scope of its own
+func (v *indexVisitor) resolveTry(tryExpr *ast.TryExpression) {
+	if tryExpr.Body != nil {
+		ast.Walk(v, tryExpr.Body)
+	}
+	if tryExpr.Catch != nil {
+		s := newScope(v.scope)
+		v.index.define(s, tryExpr.CatchParam, paramDefinition, nil)
+		v.index.resolveScope(s, tryExpr.Catch)
+	}
+	if tryExpr.Finally != nil {
+		ast.Walk(v, tryExpr.Finally)
+	}
+}
+
+// resolveMatch Resolves a MatchExpression, the names of each pattern are bound
+// in a scope of their own holding the guard and body of the arm
+func (v *indexVisitor) resolveMatch(matchExpr *ast.MatchExpression) {
+	if matchExpr.Subject != nil {
+		ast.Walk(v, matchExpr.Subject)
+	}
+	for _, arm := range matchExpr.Arms {
+		s := newScope(v.scope)
+		ast.Inspect(arm.Pattern, func(node ast.Node) bool {
+			if id, ok := node.(*ast.Identifier); ok && id.Value != ast.WILDCARD {
+				v.index.define(s, id, paramDefinition, nil)
+			}
+			return true
+		})
+		armVisitor := &indexVisitor{index: v.index, scope: s}
+		if arm.Guard != nil {
+			ast.Walk(armVisitor, arm.Guard)
+		}
+		if arm.Body != nil {
+			ast.Walk(armVisitor, arm.Body)
+		}
+		v.index.resolveDeferred(s)
+	}
+}
+
+// isBefore Checks if position a comes before position b in the source
+func isBefore(a, b token.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
@@ -0,0 +1,149 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// JSON-RPC error codes used in responses
+const (
+	PARSE_ERROR      = -32700
+	INVALID_REQUEST  = -32600
+	METHOD_NOT_FOUND = -32601
+	INVALID_PARAMS   = -32602
+)
+
+// Message JSON-RPC 2.0 request, notification or response. Requests have
+// both an ID and a Method, notifications only a Method and responses only an ID
+type Message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *ResponseError   `json:"error,omitempty"`
+}
+
+// IsRequest Checks if the message is a request expecting a response
+func (msg *Message) IsRequest() bool {
+	return msg.ID != nil && msg.Method != ""
+}
+
+// ResponseError Error returned in place of the result of a failed request
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (err *ResponseError) Error() string {
+	return fmt.Sprintf("%d: %s", err.Code, err.Message)
+}
+
+// response Successful response, the result is always present even if null
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+}
+
+// errorResponse Response of a failed request
+type errorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   *ResponseError   `json:"error"`
+}
+
+// notification Message sent without expecting a response
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// request Message expecting a response with the same ID
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// Conn JSON-RPC connection exchanging messages framed by a
+// Content-Length header, as used by the language server protocol
+type Conn struct {
+	reader *bufio.Reader
+	writer io.Writer
+	mutex  sync.Mutex // serializes writes
+}
+
+// NewConn Creates a connection reading messages from in and writing them to out
+func NewConn(in io.Reader, out io.Writer) *Conn {
+	return &Conn{
+		reader: bufio.NewReader(in),
+		writer: out,
+	}
+}
+
+// Read Reads the next message, io.EOF is returned when the input is closed
+func (conn *Conn) Read() (*Message, error) {
+	headers, err := textproto.NewReader(conn.reader).ReadMIMEHeader()
+	if err != nil {
+		if len(headers) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("Invalid Content-Length: %q", headers.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(conn.reader, body); err != nil {
+		return nil, err
+	}
+	msg := &Message{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, &ResponseError{Code: PARSE_ERROR, Message: err.Error()}
+	}
+	return msg, nil
+}
+
+// Write Writes a message preceded by its Content-Length header
+func (conn *Conn) Write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	if _, err := fmt.Fprintf(conn.writer, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = conn.writer.Write(body)
+	return err
+}
+
+// Reply Sends the result of a request
+func (conn *Conn) Reply(id *json.RawMessage, result interface{}) error {
+	return conn.Write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// ReplyError Sends the error of a failed request
+func (conn *Conn) ReplyError(id *json.RawMessage, err *ResponseError) error {
+	return conn.Write(errorResponse{JSONRPC: "2.0", ID: id, Error: err})
+}
+
+// Notify Sends a notification
+func (conn *Conn) Notify(method string, params interface{}) error {
+	return conn.Write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// Request Sends a request, the response is read like any other message
+func (conn *Conn) Request(id int, method string, params interface{}) error {
+	return conn.Write(request{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+}
@@ -0,0 +1,255 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// errExitWithoutShutdown Returned by Serve when the client exits before requesting a shutdown
+var errExitWithoutShutdown = errors.New("exit notification received before shutdown")
+
+// handler Handles the parameters of a request or notification and returns the result
+type handler func(server *Server, params json.RawMessage) (interface{}, *ResponseError)
+
+// handlers Methods supported by the server
+var handlers = map[string]handler{
+	"initialize":                  (*Server).initialize,
+	"initialized":                 (*Server).ignore,
+	"shutdown":                    (*Server).shutdown,
+	"textDocument/didOpen":        (*Server).didOpen,
+	"textDocument/didChange":      (*Server).didChange,
+	"textDocument/didClose":       (*Server).didClose,
+	"textDocument/didSave":        (*Server).ignore,
+	"textDocument/definition":     (*Server).definition,
+	"textDocument/references":     (*Server).references,
+	"textDocument/hover":          (*Server).hover,
+	"textDocument/documentSymbol": (*Server).documentSymbol,
+	"textDocument/completion":     (*Server).completion,
+}
+
+// Server Language server providing diagnostics and navigation for monkey documents
+type Server struct {
+	conn         *Conn
+	documents    map[string]*document
+	shuttingDown bool
+}
+
+// NewServer Creates a server reading messages from in and writing them to out
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		conn:      NewConn(in, out),
+		documents: make(map[string]*document),
+	}
+}
+
+// Serve Handles messages until the client sends the exit notification or closes
+// the input, an error is returned if that happens before a shutdown request
+func (server *Server) Serve() error {
+	for {
+		msg, err := server.conn.Read()
+		if err == io.EOF {
+			if server.shuttingDown {
+				return nil
+			}
+			return err
+		}
+		if respErr, ok := err.(*ResponseError); ok {
+			server.conn.ReplyError(nil, respErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			if !server.shuttingDown {
+				return errExitWithoutShutdown
+			}
+			return nil
+		}
+		server.handle(msg)
+	}
+}
+
+// handle Dispatches a message to its handler and replies to requests.
+// Notifications with unknown methods are ignored
+func (server *Server) handle(msg *Message) {
+	fn, ok := handlers[msg.Method]
+	if !msg.IsRequest() {
+		if ok {
+			fn(server, msg.Params)
+		}
+		return
+	}
+	if !ok {
+		server.conn.ReplyError(msg.ID, &ResponseError{Code: METHOD_NOT_FOUND, Message: "Method not found: " + msg.Method})
+		return
+	}
+	if server.shuttingDown {
+		server.conn.ReplyError(msg.ID, &ResponseError{Code: INVALID_REQUEST, Message: "Server is shutting down"})
+		return
+	}
+	result, respErr := fn(server, msg.Params)
+	if respErr != nil {
+		server.conn.ReplyError(msg.ID, respErr)
+		return
+	}
+	server.conn.Reply(msg.ID, result)
+}
+
+// initialize Returns the capabilities of the server
+func (server *Server) initialize(params json.RawMessage) (interface{}, *ResponseError) {
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:       TEXT_DOCUMENT_SYNC_FULL,
+			DefinitionProvider:     true,
+			ReferencesProvider:     true,
+			HoverProvider:          true,
+			DocumentSymbolProvider: true,
+			CompletionProvider:     &CompletionOptions{},
+		},
+		ServerInfo: ServerInfo{Name: "monkey"},
+	}, nil
+}
+
+// ignore Handles notifications that require no action
+func (server *Server) ignore(params json.RawMessage) (interface{}, *ResponseError) {
+	return nil, nil
+}
+
+// shutdown Prepares for the exit notification, further requests are rejected
+func (server *Server) shutdown(params json.RawMessage) (interface{}, *ResponseError) {
+	server.shuttingDown = true
+	return nil, nil
+}
+
+// didOpen Parses an opened document and publishes its diagnostics
+func (server *Server) didOpen(params json.RawMessage) (interface{}, *ResponseError) {
+	var p DidOpenTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	server.update(p.TextDocument.URI, p.TextDocument.Text)
+	return nil, nil
+}
+
+// didChange Parses the new text of a document and publishes its diagnostics
+func (server *Server) didChange(params json.RawMessage) (interface{}, *ResponseError) {
+	var p DidChangeTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.ContentChanges) != 0 {
+		server.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+	}
+	return nil, nil
+}
+
+// didClose Forgets a closed document and clears its diagnostics
+func (server *Server) didClose(params json.RawMessage) (interface{}, *ResponseError) {
+	var p DidCloseTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	delete(server.documents, p.TextDocument.URI)
+	server.conn.Notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         p.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+	return nil, nil
+}
+
+// update Replaces the text of a document and publishes its diagnostics
+func (server *Server) update(uri, text string) {
+	doc := newDocument(uri, text)
+	server.documents[uri] = doc
+	server.conn.Notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: doc.diagnostics(),
+	})
+}
+
+// definition Returns the location of the binding of the identifier at a position
+func (server *Server) definition(params json.RawMessage) (interface{}, *ResponseError) {
+	var p TextDocumentPositionParams
+	doc, err := server.positionParams(params, &p, &p)
+	if err != nil {
+		return nil, err
+	}
+	if location, ok := doc.definition(p.Position); ok {
+		return location, nil
+	}
+	return nil, nil
+}
+
+// references Returns the locations referring to the binding of the identifier at a position
+func (server *Server) references(params json.RawMessage) (interface{}, *ResponseError) {
+	var p ReferenceParams
+	doc, err := server.positionParams(params, &p, &p.TextDocumentPositionParams)
+	if err != nil {
+		return nil, err
+	}
+	return doc.references(p.Position, p.Context.IncludeDeclaration), nil
+}
+
+// hover Describes the binding of the identifier at a position
+func (server *Server) hover(params json.RawMessage) (interface{}, *ResponseError) {
+	var p TextDocumentPositionParams
+	doc, err := server.positionParams(params, &p, &p)
+	if err != nil {
+		return nil, err
+	}
+	if hover, ok := doc.hover(p.Position); ok {
+		return hover, nil
+	}
+	return nil, nil
+}
+
+// documentSymbol Returns the top level bindings of a document
+func (server *Server) documentSymbol(params json.RawMessage) (interface{}, *ResponseError) {
+	var p DocumentSymbolParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	doc, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	return doc.symbols(), nil
+}
+
+// completion Returns the completion candidates at a position
+func (server *Server) completion(params json.RawMessage) (interface{}, *ResponseError) {
+	var p TextDocumentPositionParams
+	doc, err := server.positionParams(params, &p, &p)
+	if err != nil {
+		return nil, err
+	}
+	return CompletionList{Items: doc.completions(p.Position)}, nil
+}
+
+// positionParams Unmarshals the parameters of a request concerning a position
+// into v and returns the document the position is in
+func (server *Server) positionParams(params json.RawMessage, v interface{}, p *TextDocumentPositionParams) (*document, *ResponseError) {
+	if err := unmarshalParams(params, v); err != nil {
+		return nil, err
+	}
+	return server.document(p.TextDocument.URI)
+}
+
+// document Returns an open document
+func (server *Server) document(uri string) (*document, *ResponseError) {
+	doc, ok := server.documents[uri]
+	if !ok {
+		return nil, &ResponseError{Code: INVALID_PARAMS, Message: "Document not open: " + uri}
+	}
+	return doc, nil
+}
+
+// unmarshalParams Decodes the parameters of a message
+func unmarshalParams(params json.RawMessage, v interface{}) *ResponseError {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &ResponseError{Code: INVALID_PARAMS, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+const testURI = "file:///test.monkey"
+
+const testSource = `let add = fn(x, y) {
+  x + y
+};
+const limit = 10;
+fn twice(f, v) { f(f(v)) }
+let result = add(limit, 1);
+twice(fn(n) { n * 2 }, result);
+len(result)`
+
+// testClient In-process JSON-RPC client connected to a Server through pipes
+type testClient struct {
+	t      *testing.T
+	conn   *Conn
+	nextID int
+	done   chan error
+}
+
+func newTestClient(t *testing.T) *testClient {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(serverIn, serverOut).Serve()
+		serverOut.Close()
+	}()
+	return &testClient{t: t, conn: NewConn(clientIn, clientOut), done: done}
+}
+
+// request Sends a request and decodes the result of its response into result
+func (client *testClient) request(method string, params, result interface{}) *ResponseError {
+	client.nextID++
+	if err := client.conn.Request(client.nextID, method, params); err != nil {
+		client.t.Fatalf("Failed to send %s: %s", method, err)
+	}
+	msg := client.read()
+	if msg.ID == nil || string(*msg.ID) != strings.TrimSpace(string(mustMarshal(client.nextID))) {
+		client.t.Fatalf("Expected response to %s Got=%+v", method, msg)
+	}
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if result != nil {
+		if err := json.Unmarshal(msg.Result, result); err != nil {
+			client.t.Fatalf("Failed to decode result of %s: %s", method, err)
+		}
+	}
+	return nil
+}
+
+// notify Sends a notification
+func (client *testClient) notify(method string, params interface{}) {
+	if err := client.conn.Notify(method, params); err != nil {
+		client.t.Fatalf("Failed to send %s: %s", method, err)
+	}
+}
+
+// diagnostics Reads the next message, which must publish diagnostics
+func (client *testClient) diagnostics() PublishDiagnosticsParams {
+	msg := client.read()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		client.t.Fatalf("Expected diagnostics Got=%+v", msg)
+	}
+	var params PublishDiagnosticsParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		client.t.Fatalf("Failed to decode diagnostics: %s", err)
+	}
+	return params
+}
+
+func (client *testClient) read() *Message {
+	msg, err := client.conn.Read()
+	if err != nil {
+		client.t.Fatalf("Failed to read message: %s", err)
+	}
+	return msg
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+func positionParams(line, character int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: testURI},
+		Position:     Position{Line: line, Character: character},
+	}
+}
+
+func TestServer(t *testing.T) {
+	client := newTestClient(t)
+	var initResult InitializeResult
+	if err := client.request("initialize", map[string]interface{}{}, &initResult); err != nil {
+		t.Fatalf("initialize failed: %s", err)
+	}
+	if !initResult.Capabilities.DefinitionProvider || initResult.Capabilities.TextDocumentSync != TEXT_DOCUMENT_SYNC_FULL {
+		t.Errorf("Wrong capabilities Got=%+v", initResult.Capabilities)
+	}
+	client.notify("initialized", map[string]interface{}{})
+
+	client.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: testURI, LanguageID: "monkey", Version: 1, Text: "let x = 1;\nlet = 5;"},
+	})
+	diagnostics := client.diagnostics()
+	if diagnostics.URI != testURI || len(diagnostics.Diagnostics) == 0 {
+		t.Fatalf("Expected diagnostics for %s Got=%+v", testURI, diagnostics)
+	}
+	first := diagnostics.Diagnostics[0]
+	if first.Message != "peekToken: Expected type=IDENT Got==" || first.Range.Start != (Position{Line: 1, Character: 4}) {
+		t.Errorf("Wrong diagnostic Got=%+v", first)
+	}
+	client.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: testURI},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: testSource}},
+	})
+	if diagnostics := client.diagnostics(); len(diagnostics.Diagnostics) != 0 {
+		t.Fatalf("Expected no diagnostics Got=%+v", diagnostics.Diagnostics)
+	}
+
+	var location Location
+	client.request("textDocument/definition", positionParams(5, 14), &location)
+	if location.URI != testURI || location.Range != (Range{Start: Position{0, 4}, End: Position{0, 7}}) {
+		t.Errorf("Wrong definition of add Got=%+v", location)
+	}
+	client.request("textDocument/definition", positionParams(1, 6), &location)
+	if location.Range.Start != (Position{0, 16}) {
+		t.Errorf("Wrong definition of y Got=%+v", location)
+	}
+	var raw json.RawMessage
+	client.request("textDocument/definition", positionParams(7, 1), &raw)
+	if string(raw) != "null" {
+		t.Errorf("Expected no definition of builtin Got=%s", raw)
+	}
+
+	referenceTests := []struct {
+		line, character    int
+		includeDeclaration bool
+		expected           []Position
+	}{
+		{0, 5, true, []Position{{0, 4}, {5, 13}}},
+		{5, 18, false, []Position{{5, 17}}},
+		{4, 17, true, []Position{{4, 9}, {4, 17}, {4, 19}}},
+		{6, 27, true, []Position{{5, 4}, {6, 23}, {7, 4}}},
+	}
+	for i, test := range referenceTests {
+		params := ReferenceParams{TextDocumentPositionParams: positionParams(test.line, test.character)}
+		params.Context.IncludeDeclaration = test.includeDeclaration
+		var locations []Location
+		client.request("textDocument/references", params, &locations)
+		if len(locations) != len(test.expected) {
+			t.Errorf("%d - Wrong number of references Expected=%d Got=%+v", i, len(test.expected), locations)
+			continue
+		}
+		for j, location := range locations {
+			if location.Range.Start != test.expected[j] {
+				t.Errorf("%d - Wrong reference %d Expected=%+v Got=%+v", i, j, test.expected[j], location.Range.Start)
+			}
+		}
+	}
+
+	hoverTests := []struct {
+		line, character int
+		expected        string
+	}{
+		{5, 14, "fn add(x, y)"},
+		{6, 2, "fn twice(f, v)"},
+		{5, 19, "const limit"},
+		{1, 2, "parameter x"},
+		{7, 1, "builtin function len"},
+	}
+	for i, test := range hoverTests {
+		var hover Hover
+		client.request("textDocument/hover", positionParams(test.line, test.character), &hover)
+		expected := "```monkey\n" + test.expected + "\n```"
+		if hover.Contents.Value != expected {
+			t.Errorf("%d - Wrong hover Expected=%q Got=%q", i, expected, hover.Contents.Value)
+		}
+	}
+
+	var symbols []DocumentSymbol
+	client.request("textDocument/documentSymbol", DocumentSymbolParams{TextDocument: TextDocumentIdentifier{URI: testURI}}, &symbols)
+	expectedSymbols := []struct {
+		name string
+		kind int
+	}{{"add", SYMBOL_FUNCTION}, {"limit", SYMBOL_CONSTANT}, {"twice", SYMBOL_FUNCTION}, {"result", SYMBOL_VARIABLE}}
+	if len(symbols) != len(expectedSymbols) {
+		t.Fatalf("Wrong number of symbols Expected=%d Got=%+v", len(expectedSymbols), symbols)
+	}
+	for i, expected := range expectedSymbols {
+		if symbols[i].Name != expected.name || symbols[i].Kind != expected.kind {
+			t.Errorf("%d - Wrong symbol Expected=%+v Got=%+v", i, expected, symbols[i])
+		}
+	}
+
+	var completions CompletionList
+	client.request("textDocument/completion", positionParams(7, 2), &completions)
+	labels := make([]string, 0)
+	for _, item := range completions.Items {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, ",") != "len,let" {
+		t.Errorf("Wrong completions Expected=len,let Got=%v", labels)
+	}
+	client.request("textDocument/completion", positionParams(5, 15), &completions)
+	if len(completions.Items) != 1 || completions.Items[0].Label != "add" || completions.Items[0].Detail != "fn add(x, y)" {
+		t.Errorf("Wrong completions Got=%+v", completions.Items)
+	}
+
+	if err := client.request("textDocument/rename", positionParams(0, 5), nil); err == nil || err.Code != METHOD_NOT_FOUND {
+		t.Errorf("Expected method not found error Got=%v", err)
+	}
+	if err := client.request("textDocument/hover", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///missing.monkey"}}, nil); err == nil || err.Code != INVALID_PARAMS {
+		t.Errorf("Expected invalid params error Got=%v", err)
+	}
+
+	if err := client.request("shutdown", nil, nil); err != nil {
+		t.Fatalf("shutdown failed: %s", err)
+	}
+	client.notify("exit", nil)
+	if err := <-client.done; err != nil {
+		t.Fatalf("Expected clean exit Got=%s", err)
+	}
+}
+
+func TestExitWithoutShutdown(t *testing.T) {
+	client := newTestClient(t)
+	client.notify("exit", nil)
+	if err := <-client.done; err != errExitWithoutShutdown {
+		t.Fatalf("Expected errExitWithoutShutdown Got=%v", err)
+	}
+}
+
+func TestUTF16Positions(t *testing.T) {
+	doc := newDocument(testURI, "let s = \"é😀\"; let x = s;\nx")
+	location, ok := doc.definition(Position{Line: 0, Character: 23})
+	if !ok || location.Range != (Range{Start: Position{0, 4}, End: Position{0, 5}}) {
+		t.Errorf("Wrong definition of s Got=%+v", location)
+	}
+	locations := doc.references(Position{Line: 1, Character: 0}, true)
+	if len(locations) != 2 || locations[0].Range != (Range{Start: Position{0, 19}, End: Position{0, 20}}) {
+		t.Errorf("Wrong references of x Got=%+v", locations)
+	}
+	if prefix := doc.prefixAt(doc.tokenPosition(Position{Line: 0, Character: 20})); prefix != "x" {
+		t.Errorf("Wrong prefix Expected=x Got=%q", prefix)
+	}
+}
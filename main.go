@@ -8,7 +8,32 @@ import (
 	"github.com/CzarSimon/monkey/repl"
 )
 
+// commands Subcommands of the monkey executable mapped to their entry points,
+// each command receives the arguments following its name and returns an exit code
+var commands = map[string]func(args []string) int{
+	"dap":   dapCommand,
+	"debug": debugCommand,
+	"lint":  lintCommand,
+	"lsp":   lspCommand,
+	"run":   runCommand,
+	"test":  testCommand,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		command, ok := commands[os.Args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", os.Args[1])
+			printUsage()
+			os.Exit(2)
+		}
+		os.Exit(command(os.Args[2:]))
+	}
+	startRepl()
+}
+
+// startRepl Greets the current user and starts an interactive session
+func startRepl() {
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -17,3 +42,15 @@ func main() {
 	fmt.Println("Feel free to type in commands")
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+// printUsage Prints the available commands to stderr
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: monkey [command] [arguments]")
+	fmt.Fprintln(os.Stderr, "Without a command an interactive session is started. Commands:")
+	fmt.Fprintln(os.Stderr, "\tdap\tRun a debug adapter over stdio")
+	fmt.Fprintln(os.Stderr, "\tdebug [-path dirs] file.monkey\tEvaluate a file in an interactive debugger")
+	fmt.Fprintln(os.Stderr, "\tlint [-disable rules] files...\tReport suspicious code")
+	fmt.Fprintln(os.Stderr, "\tlsp\tRun a language server over stdio")
+	fmt.Fprintln(os.Stderr, "\trun [-path dirs] [-no-redeclare] [-optimize] [-profile file] [-cover file] [-trace] [-trace-func name] [-trace-file file] [-memory] [-memory-limit bytes] file.monkey\tEvaluate a file")
+	fmt.Fprintln(os.Stderr, "\ttest [-format human|junit] [-o file] [-run regexp] [-v] [-cover file] [paths...]\tRun *_test.monkey files")
+}
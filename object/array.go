@@ -0,0 +1,34 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Array Object representing an ordered list of objects
+type Array struct {
+	Elements []Object
+}
+
+func (array *Array) Type() ObjectType {
+	return ARRAY_OBJ
+}
+
+func (array *Array) Inspect() string {
+	var out bytes.Buffer
+	elements := make([]string, 0, len(array.Elements))
+	for _, element := range array.Elements {
+		elements = append(elements, element.Inspect())
+	}
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+// NewArray Creates a new Array object and returns a reference to it
+func NewArray(elements []Object) *Array {
+	return &Array{
+		Elements: elements,
+	}
+}
@@ -0,0 +1,27 @@
+package object
+
+// BuiltinFunction Signature of functions implemented in Go and callable from monkey,
+// called with the environment of the call to evaluate code as the calling task
+type BuiltinFunction func(env *Environment, args ...Object) Object
+
+// Builtin Object wrapping a builtin function
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+func (builtin *Builtin) Type() ObjectType {
+	return BUILTIN_OBJ
+}
+
+func (builtin *Builtin) Inspect() string {
+	return "builtin function " + builtin.Name
+}
+
+// NewBuiltin Creates a new Builtin object and returns a reference to it
+func NewBuiltin(name string, fn BuiltinFunction) *Builtin {
+	return &Builtin{
+		Name: name,
+		Fn:   fn,
+	}
+}
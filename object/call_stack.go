@@ -0,0 +1,33 @@
+package object
+
+// CallStack Function calls being evaluated by a task, shared by the environments
+// the task evaluates code in. Only the goroutine evaluating the task may use it
+type CallStack struct {
+	frames []Frame // outermost first
+}
+
+// NewCallStack Creates an empty CallStack and returns a reference to it
+func NewCallStack() *CallStack {
+	return &CallStack{
+		frames: make([]Frame, 0),
+	}
+}
+
+// Push Adds the frame of a call that is about to be evaluated
+func (stack *CallStack) Push(frame Frame) {
+	stack.frames = append(stack.frames, frame)
+}
+
+// Pop Removes the frame of the innermost call once it has returned
+func (stack *CallStack) Pop() {
+	stack.frames = stack.frames[:len(stack.frames)-1]
+}
+
+// Frames Returns a copy of the frames of the calls being evaluated, innermost first
+func (stack *CallStack) Frames() []Frame {
+	frames := make([]Frame, 0, len(stack.frames))
+	for i := len(stack.frames) - 1; i >= 0; i-- {
+		frames = append(frames, stack.frames[i])
+	}
+	return frames
+}
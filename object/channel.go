@@ -0,0 +1,103 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Channel Object passing values between tasks, holding up to Capacity
+// values that have been sent but not yet received
+type Channel struct {
+	Capacity  int
+	values    chan Object
+	closed    chan struct{} // closed when the Channel is closed, values is never closed
+	closeOnce sync.Once
+}
+
+func (ch *Channel) Type() ObjectType {
+	return CHANNEL_OBJ
+}
+
+func (ch *Channel) Inspect() string {
+	return fmt.Sprintf("<channel %d>", ch.Capacity)
+}
+
+// Send Blocks until a value can be passed on or buffered, returns
+// a ValueError if the Channel is closed
+func (ch *Channel) Send(value Object) *Error {
+	select {
+	case <-ch.closed:
+		return NewKindError(VALUE_ERROR, "Send on a closed channel")
+	default:
+	}
+	select {
+	case ch.values <- value:
+		return nil
+	case <-ch.closed:
+		return NewKindError(VALUE_ERROR, "Send on a closed channel")
+	}
+}
+
+// Receive Blocks until a value is sent, returns false if the Channel
+// is closed and every value sent has been received
+func (ch *Channel) Receive() (Object, bool) {
+	select {
+	case value := <-ch.values:
+		return value, true
+	case <-ch.closed:
+		return ch.drain()
+	}
+}
+
+// drain Returns a value sent before the Channel was closed, if any is left
+func (ch *Channel) drain() (Object, bool) {
+	select {
+	case value := <-ch.values:
+		return value, true
+	default:
+		return nil, false
+	}
+}
+
+// Close Closes the Channel, waking the tasks blocked sending or receiving.
+// Returns a ValueError if it is already closed
+func (ch *Channel) Close() *Error {
+	closed := false
+	ch.closeOnce.Do(func() {
+		close(ch.closed)
+		closed = true
+	})
+	if !closed {
+		return NewKindError(VALUE_ERROR, "Channel is already closed")
+	}
+	return nil
+}
+
+// NewChannel Creates a new open Channel and returns a reference to it
+func NewChannel(capacity int) *Channel {
+	return &Channel{
+		Capacity: capacity,
+		values:   make(chan Object, capacity),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Select Blocks until a value is sent on one of the channels or one of them
+// is closed, returns the index of the channel and the value received as
+// Receive does. Values sent before a channel was closed are received first
+func Select(channels []*Channel) (int, Object, bool) {
+	cases := make([]reflect.SelectCase, 0, 2*len(channels))
+	for _, ch := range channels {
+		cases = append(cases,
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.values)},
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.closed)})
+	}
+	chosen, value, _ := reflect.Select(cases)
+	i := chosen / 2
+	if chosen%2 == 1 {
+		received, ok := channels[i].drain()
+		return i, received, ok
+	}
+	return i, value.Interface().(Object), true
+}
@@ -1,37 +1,234 @@
 package object
 
-// Environment Store of variables and there associated objects
+import (
+	"sort"
+	"sync"
+)
+
+// Environment Store of variables and there associated objects, safe
+// to use from the goroutines evaluating spawned tasks
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	mutex       sync.RWMutex // guards store, exported and constants
+	store       map[string]Object
+	outer       *Environment
+	exported    map[string]bool
+	constants   map[string]bool // names bound with const, which may not be declared again
+	noRedeclare bool            // if set, let bindings may not be declared again either
+	sandboxed   bool            // if set, code evaluated in the environment may not import modules
+	builtins    map[string]bool // names of the builtins available in a sandbox
+	stack       *CallStack      // calls being evaluated by the task evaluating code in the environment
+	memory      *Memory         // Memory the evaluation accounts allocations to, nil if it does not
 }
 
 // NewEnvironment Creates an empty environment and retruns a reference to it
 func NewEnvironment() *Environment {
-	return &Environment{
-		store: make(map[string]Object),
-		outer: nil,
+	return newEnvironment(nil, NewCallStack(), nil)
+}
+
+// newEnvironment Creates an empty environment enclosed in outer for code
+// evaluated with a call stack, accounting its allocations to memory
+func newEnvironment(outer *Environment, stack *CallStack, memory *Memory) *Environment {
+	env := &Environment{
+		store:     make(map[string]Object),
+		outer:     outer,
+		exported:  make(map[string]bool),
+		constants: make(map[string]bool),
+		stack:     stack,
+		memory:    memory,
 	}
+	env.allocate(ENVIRONMENT_SIZE)
+	return env
+}
+
+// NewNoRedeclareEnvironment Creates an empty environment in which no
+// declared name may be declared again, not even names bound with let
+func NewNoRedeclareEnvironment() *Environment {
+	env := NewEnvironment()
+	env.noRedeclare = true
+	return env
 }
 
-// Get Tries to get an object from the environment, returns an Error if unsuccessful
+// NewSandboxEnvironment Creates an empty environment for untrusted code,
+// which may only reach the host through the builtins bound in it. Of the
+// builtins available in every other environment only the named ones are
+func NewSandboxEnvironment(builtins ...string) *Environment {
+	env := NewEnvironment()
+	env.sandboxed = true
+	env.builtins = make(map[string]bool, len(builtins))
+	for _, name := range builtins {
+		env.builtins[name] = true
+	}
+	return env
+}
+
+// Sandboxed Checks if the environment or any of its outer environments is a sandbox
+func (env *Environment) Sandboxed() bool {
+	for current := env; current != nil; current = current.outer {
+		if current.sandboxed {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsBuiltin Checks if a builtin is available to code evaluated in the environment,
+// which is every builtin unless the environment is enclosed in a sandbox
+func (env *Environment) AllowsBuiltin(name string) bool {
+	for current := env; current != nil; current = current.outer {
+		if current.sandboxed {
+			return current.builtins[name]
+		}
+	}
+	return true
+}
+
+// Get Tries to get an object from the environment or any of its outer
+// environments, returns an Error if unsuccessful
 func (env *Environment) Get(name string) (Object, *Error) {
+	env.mutex.RLock()
 	obj, ok := env.store[name]
+	env.mutex.RUnlock()
+	if !ok && env.outer != nil {
+		return env.outer.Get(name)
+	}
 	if !ok {
-		return nil, NewErrorf("Identifier not found: %s", name)
+		return nil, NewKindErrorf(NAME_ERROR, "Identifier not found: %s", name)
 	}
 	return obj, nil
 }
 
 // Set Adds a object to the environment with a given name as a key
 func (env *Environment) Set(name string, obj Object) Object {
-	env.store[name] = obj
+	env.mutex.Lock()
+	bound := env.set(name, obj)
+	env.mutex.Unlock()
+	if !bound {
+		env.allocate(BINDING_SIZE + int64(len(name)))
+	}
 	return obj
 }
 
-// NewEnclosedEnvironment Creates a new environment with an outer environment set
+// set Binds a name while the environment is locked, returns true if it was already bound.
+// The allocation of new bindings is accounted after unlocking since measuring locks the environment
+func (env *Environment) set(name string, obj Object) bool {
+	_, bound := env.store[name]
+	env.store[name] = obj
+	return bound
+}
+
+// Declare Binds a name declared by a let, const or function statement, returns
+// an Error if the name is already bound in this environment by const, or by
+// anything in an environment that disallows redeclaration
+func (env *Environment) Declare(name string, obj Object, constant bool) (Object, *Error) {
+	env.mutex.Lock()
+	if _, bound := env.store[name]; bound {
+		if env.constants[name] {
+			env.mutex.Unlock()
+			return nil, NewKindErrorf(ASSIGNMENT_ERROR, "Cannot redeclare constant %s", name)
+		}
+		if constant || env.noRedeclare {
+			env.mutex.Unlock()
+			return nil, NewKindErrorf(ASSIGNMENT_ERROR, "Cannot redeclare %s", name)
+		}
+	}
+	if constant {
+		env.constants[name] = true
+	}
+	bound := env.set(name, obj)
+	env.mutex.Unlock()
+	if !bound {
+		env.allocate(BINDING_SIZE + int64(len(name)))
+	}
+	return obj, nil
+}
+
+// NewEnclosedEnvironment Creates a new environment with an outer environment set,
+// code evaluated in it is evaluated by the same task as code in the outer environment
 func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
-	env.outer = outer
-	return env
+	return NewCallEnvironment(outer, outer)
+}
+
+// NewCallEnvironment Creates an environment enclosed in outer, which may be nil,
+// for code evaluated by the task evaluating code in caller, e.g. a called function
+func NewCallEnvironment(outer, caller *Environment) *Environment {
+	return newEnvironment(outer, caller.stack, caller.memory)
+}
+
+// NewTaskEnvironment Creates an empty environment for a task spawned by code
+// evaluated in caller, with a call stack of its own and the Memory of caller
+func NewTaskEnvironment(caller *Environment) *Environment {
+	return newEnvironment(nil, NewCallStack(), caller.memory)
+}
+
+// CallStack Returns the calls being evaluated by the task evaluating code in the environment
+func (env *Environment) CallStack() *CallStack {
+	return env.stack
+}
+
+// Memory Returns the Memory allocations of code evaluated in the environment are accounted to
+func (env *Environment) Memory() *Memory {
+	return env.memory
+}
+
+// SetMemory Accounts the allocations of code evaluated in the environment to a Memory,
+// nil for none. Environments take the Memory when created, so it must be set before
+// any code is evaluated in the environment
+func (env *Environment) SetMemory(memory *Memory) {
+	env.memory = memory
+}
+
+// allocate Accounts an allocation to the Memory of the environment if it has one
+func (env *Environment) allocate(size int64) {
+	if env.memory != nil {
+		env.memory.allocate(size)
+	}
+}
+
+// Names Returns the names bound in the environment and its outer environments
+func (env *Environment) Names() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for current := env; current != nil; current = current.outer {
+		current.mutex.RLock()
+		for name := range current.store {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		current.mutex.RUnlock()
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Outer Returns the environment enclosing this one, nil for a top level environment
+func (env *Environment) Outer() *Environment {
+	return env.outer
+}
+
+// Bindings Returns a copy of the names bound in this environment, not
+// including the ones bound in its outer environments, and their values
+func (env *Environment) Bindings() map[string]Object {
+	env.mutex.RLock()
+	defer env.mutex.RUnlock()
+	bindings := make(map[string]Object, len(env.store))
+	for name, obj := range env.store {
+		bindings[name] = obj
+	}
+	return bindings
+}
+
+// Export Marks a name bound in the environment as accessible to importers
+func (env *Environment) Export(name string) {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+	env.exported[name] = true
+}
+
+// IsExported Checks if a name has been exported from the environment
+func (env *Environment) IsExported(name string) bool {
+	env.mutex.RLock()
+	defer env.mutex.RUnlock()
+	return env.exported[name]
 }
@@ -1,10 +1,39 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// ErrorKind Name of the category of an error
+type ErrorKind string
+
+const (
+	RUNTIME_ERROR    ErrorKind = "RuntimeError"
+	TYPE_ERROR       ErrorKind = "TypeError"
+	NAME_ERROR       ErrorKind = "NameError"
+	KEY_ERROR        ErrorKind = "KeyError"
+	VALUE_ERROR      ErrorKind = "ValueError"
+	ARGUMENT_ERROR   ErrorKind = "ArgumentError"
+	ARITHMETIC_ERROR ErrorKind = "ArithmeticError"
+	IMPORT_ERROR     ErrorKind = "ImportError"
+	ASSIGNMENT_ERROR ErrorKind = "AssignmentError"
+	MATCH_ERROR      ErrorKind = "MatchError"
+	ASSERTION_ERROR  ErrorKind = "AssertionError"
+	IO_ERROR         ErrorKind = "IOError"
+	PERMISSION_ERROR ErrorKind = "PermissionError"
+	MEMORY_ERROR     ErrorKind = "MemoryError"
+	THROWN_ERROR     ErrorKind = "Error"       // kind of errors thrown with a message
+	INTERRUPT_ERROR  ErrorKind = "Interrupted" // kind of errors stopping the evaluation from outside, e.g. by a debugger
+)
 
 // Error Object for wrapping an encountered error
 type Error struct {
-	Message string
+	Message  string
+	Kind     ErrorKind
+	Position token.Position // where the error was raised
+	Stack    []Frame        // calls being evaluated when the error was raised, innermost first, holding the called function and call position
 }
 
 func (err *Error) Type() ObjectType {
@@ -15,14 +44,44 @@ func (err *Error) Inspect() string {
 	return "ERROR: " + err.Message
 }
 
-// NewError Creates an error based on a message and returns a referece to it
-func NewError(message string) *Error {
+// Copy Returns a copy of the error that does not share its stack
+func (err *Error) Copy() *Error {
+	stack := make([]Frame, len(err.Stack))
+	copy(stack, err.Stack)
 	return &Error{
-		Message: message,
+		Message:  err.Message,
+		Kind:     err.Kind,
+		Position: err.Position,
+		Stack:    stack,
 	}
 }
 
+// Catchable Checks if code may catch the error, errors interrupting the evaluation
+// stop it regardless of any try expressions or assertions being evaluated
+func (err *Error) Catchable() bool {
+	return err.Kind != INTERRUPT_ERROR
+}
+
+// NewError Creates an error based on a message and returns a referece to it
+func NewError(message string) *Error {
+	return NewKindError(RUNTIME_ERROR, message)
+}
+
 // NewErrorf Formats an error messages and creates an Error object
 func NewErrorf(format string, a ...interface{}) *Error {
 	return NewError(fmt.Sprintf(format, a...))
 }
+
+// NewKindError Creates an error of a given kind and returns a referece to it
+func NewKindError(kind ErrorKind, message string) *Error {
+	return &Error{
+		Message: message,
+		Kind:    kind,
+		Stack:   make([]Frame, 0),
+	}
+}
+
+// NewKindErrorf Formats an error messages and creates an Error object of a given kind
+func NewKindErrorf(kind ErrorKind, format string, a ...interface{}) *Error {
+	return NewKindError(kind, fmt.Sprintf(format, a...))
+}
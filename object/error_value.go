@@ -0,0 +1,22 @@
+package object
+
+// ErrorValue Object holding a caught error as a value that can be
+// inspected, passed around and thrown again
+type ErrorValue struct {
+	Error *Error
+}
+
+func (errValue *ErrorValue) Type() ObjectType {
+	return ERROR_VALUE_OBJ
+}
+
+func (errValue *ErrorValue) Inspect() string {
+	return string(errValue.Error.Kind) + ": " + errValue.Error.Message
+}
+
+// NewErrorValue Wraps an error in an ErrorValue and returns a reference to it
+func NewErrorValue(err *Error) *ErrorValue {
+	return &ErrorValue{
+		Error: err,
+	}
+}
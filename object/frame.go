@@ -0,0 +1,69 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// MODULE_FRAME Name of the frame executing top level code
+const MODULE_FRAME = "<module>"
+
+// ANONYMOUS_FRAME Name of the frame of a function not bound to a name
+const ANONYMOUS_FRAME = "<anonymous>"
+
+// NATIVE_FILENAME File name shown for frames without a position in the source,
+// i.e. calls made by builtin functions
+const NATIVE_FILENAME = "<builtin>"
+
+// INPUT_FILENAME File name shown for frames in source code not read from a file
+const INPUT_FILENAME = "<input>"
+
+// Frame A function in the call stack and a position of the source code
+type Frame struct {
+	Function string
+	Position token.Position
+}
+
+// String Returns a position in function representation of the Frame
+func (frame Frame) String() string {
+	if !frame.Position.IsValid() {
+		return NATIVE_FILENAME + " in " + frame.Function
+	}
+	return frame.Position.String() + " in " + frame.Function
+}
+
+// Trace Returns the frames that were executing when the error was raised,
+// outermost first, each with the position it was executing
+func (err *Error) Trace() []Frame {
+	trace := make([]Frame, 0, len(err.Stack)+1)
+	function := MODULE_FRAME
+	for i := len(err.Stack) - 1; i >= 0; i-- {
+		trace = append(trace, Frame{Function: function, Position: err.Stack[i].Position})
+		function = err.Stack[i].Function
+	}
+	return append(trace, Frame{Function: function, Position: err.Position})
+}
+
+// Traceback Returns a description of the error and the frames that were
+// executing when it was raised, formatted like a Python traceback
+func (err *Error) Traceback() string {
+	var out bytes.Buffer
+	out.WriteString("Traceback (most recent call last):\n")
+	for _, frame := range err.Trace() {
+		pos := frame.Position
+		if !pos.IsValid() {
+			fmt.Fprintf(&out, "  File %q, in %s\n", NATIVE_FILENAME, frame.Function)
+			continue
+		}
+		filename := pos.Filename
+		if filename == "" {
+			filename = INPUT_FILENAME
+		}
+		fmt.Fprintf(&out, "  File %q, line %d, column %d, in %s\n",
+			filename, pos.Line, pos.Column, frame.Function)
+	}
+	out.WriteString(string(err.Kind) + ": " + err.Message)
+	return out.String()
+}
@@ -5,10 +5,13 @@ import (
 	"strings"
 
 	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/token"
 )
 
 // Function Object wrapping a function
 type Function struct {
+	Name       string         // name the function literal was bound to, if any
+	Position   token.Position // position of the function literal
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
@@ -18,6 +21,8 @@ type Function struct {
 // an environment and passes a reference to it
 func NewFunction(fnLit *ast.FunctionLiteral, env *Environment) *Function {
 	return &Function{
+		Name:       fnLit.Name,
+		Position:   fnLit.Pos(),
 		Parameters: fnLit.Parameters,
 		Body:       fnLit.Body,
 		Env:        env,
@@ -28,16 +33,19 @@ func (fn *Function) Type() ObjectType {
 	return FUNCTION_OBJ
 }
 
+// Inspect Returns the signature of the function, e.g. fn add(x, y)
 func (fn *Function) Inspect() string {
 	var out bytes.Buffer
 	params := make([]string, 0)
 	for _, param := range fn.Parameters {
 		params = append(params, param.String())
 	}
-	out.WriteString("fn (")
+	out.WriteString("fn")
+	if fn.Name != "" {
+		out.WriteString(" " + fn.Name)
+	}
+	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") {\n")
-	out.WriteString(fn.Body.String())
-	out.WriteString("\n}")
+	out.WriteString(")")
 	return out.String()
 }
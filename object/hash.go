@@ -0,0 +1,100 @@
+package object
+
+import (
+	"bytes"
+	"hash/fnv"
+	"strings"
+)
+
+// HashKey Comparable key identifying a Hashable object
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable Interface for objects that can be used as keys in a Hash
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+// HashKey Returns the key of an Integer in a Hash
+func (integer *Integer) HashKey() HashKey {
+	return HashKey{Type: integer.Type(), Value: uint64(integer.Value)}
+}
+
+// HashKey Returns the key of a Boolean in a Hash
+func (boolean *Boolean) HashKey() HashKey {
+	var value uint64
+	if boolean.Value {
+		value = 1
+	}
+	return HashKey{Type: boolean.Type(), Value: value}
+}
+
+// HashKey Returns the key of a String in a Hash
+func (str *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(str.Value))
+	return HashKey{Type: str.Type(), Value: h.Sum64()}
+}
+
+// HashPair Key and value stored in a Hash
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash Object mapping hashable keys to values, remembers the insertion order of keys
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	order []HashKey
+}
+
+func (hash *Hash) Type() ObjectType {
+	return HASH_OBJ
+}
+
+func (hash *Hash) Inspect() string {
+	var out bytes.Buffer
+	pairs := make([]string, 0, len(hash.order))
+	for _, pair := range hash.Entries() {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// Set Stores a value under a key, replacing any previous value
+func (hash *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := hash.Pairs[hashKey]; !ok {
+		hash.order = append(hash.order, hashKey)
+	}
+	hash.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+// Get Returns the value stored under a key
+func (hash *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := hash.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+// Entries Returns the pairs of the hash in insertion order
+func (hash *Hash) Entries() []HashPair {
+	entries := make([]HashPair, 0, len(hash.order))
+	for _, key := range hash.order {
+		entries = append(entries, hash.Pairs[key])
+	}
+	return entries
+}
+
+// NewHash Creates a new, empty Hash and returns a reference to it
+func NewHash() *Hash {
+	return &Hash{
+		Pairs: make(map[HashKey]HashPair),
+		order: make([]HashKey, 0),
+	}
+}
@@ -0,0 +1,44 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/CzarSimon/monkey/ast"
+)
+
+// Macro Object wrapping a macro defined in a program
+type Macro struct {
+	Name       string // name the macro literal was bound to
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+// NewMacro Creates a new macro based on a macro literal and the
+// environment it was defined in and returns a reference to it
+func NewMacro(name string, macroLit *ast.MacroLiteral, env *Environment) *Macro {
+	return &Macro{
+		Name:       name,
+		Parameters: macroLit.Parameters,
+		Body:       macroLit.Body,
+		Env:        env,
+	}
+}
+
+func (macro *Macro) Type() ObjectType {
+	return MACRO_OBJ
+}
+
+// Inspect Returns the signature of the macro, e.g. macro unless(cond, body)
+func (macro *Macro) Inspect() string {
+	var out bytes.Buffer
+	params := make([]string, 0, len(macro.Parameters))
+	for _, param := range macro.Parameters {
+		params = append(params, param.String())
+	}
+	out.WriteString("macro " + macro.Name + "(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+	return out.String()
+}
@@ -0,0 +1,274 @@
+package object
+
+import "sync"
+
+// Approximate sizes in bytes of the values accounted by a Memory
+const (
+	INTEGER_SIZE     = 16
+	STRING_SIZE      = 32 // plus the length of the string
+	ARRAY_SIZE       = 32 // plus ELEMENT_SIZE for each element
+	ELEMENT_SIZE     = 16
+	HASH_SIZE        = 96 // plus PAIR_SIZE for each pair
+	PAIR_SIZE        = 64
+	FUNCTION_SIZE    = 80
+	ENVIRONMENT_SIZE = 192 // plus BINDING_SIZE and the length of the name for each binding
+	BINDING_SIZE     = 48
+)
+
+// MEASURE_INTERVAL Minimum number of bytes allocated between two measurements of the memory in use
+const MEASURE_INTERVAL = 64 * 1024
+
+// Memory Approximate accounting of the memory used by values and environments.
+// Allocations are counted as they happen, the memory in use is measured by
+// sizing everything reachable from the environments in use each time about
+// as many bytes have been allocated as were in use at the last measurement.
+// Values only held by Go code, e.g. arguments of builtins, are not measured.
+// An evaluation is accounted to the Memory set on its top level environment,
+// which the environments created while evaluating it take
+type Memory struct {
+	Limit     int64 // bytes that may be in use, 0 for no limit
+	mutex     sync.Mutex
+	allocated int64                // bytes allocated in total
+	live      int64                // bytes in use at the last measurement
+	since     int64                // bytes allocated since the last measurement
+	peak      int64                // highest estimate of the bytes in use
+	exceeded  int64                // bytes in use when the limit was exceeded, 0 if it has not been
+	roots     map[*Environment]int // environments in use and how many times they have been entered
+}
+
+// NewMemory Creates a Memory enforcing a limit in bytes, 0 for no limit
+func NewMemory(limit int64) *Memory {
+	return &Memory{
+		Limit: limit,
+		roots: make(map[*Environment]int),
+	}
+}
+
+// CheckMemory Returns a MemoryError if the limit of the Memory of an
+// environment has been exceeded since the last check
+func CheckMemory(env *Environment) *Error {
+	if env.memory == nil {
+		return nil
+	}
+	return env.memory.check()
+}
+
+// ReserveMemory Returns a MemoryError if allocating size more bytes would
+// exceed the limit of the Memory of an environment. Builtins building large
+// values reserve the memory first, since values only held by a builtin while
+// it runs are not reachable from any environment
+func ReserveMemory(env *Environment, size int64) *Error {
+	if env.memory == nil {
+		return nil
+	}
+	return env.memory.reserve(size)
+}
+
+// Allocate Accounts a value created by code evaluated in an environment to its
+// Memory, not including the values it holds, which are accounted when created
+func Allocate(env *Environment, obj Object) {
+	if env.memory != nil {
+		env.memory.allocate(sizeOf(obj))
+	}
+}
+
+// AllocateAll Accounts a value created by native code called from an environment
+// to its Memory together with the values it holds, which may have been created
+// by the same code. Values held by functions are not included
+func AllocateAll(env *Environment, obj Object) {
+	if env.memory == nil {
+		return
+	}
+	sizer := &sizer{
+		objects:      make(map[Object]bool),
+		environments: make(map[*Environment]bool),
+		shallow:      true,
+	}
+	sizer.sizeObject(obj)
+	env.memory.allocate(sizer.size)
+}
+
+// EnterEnvironment Marks an environment as in use, e.g. while a function
+// called in it is evaluated, until it is left as many times as entered
+func EnterEnvironment(env *Environment) {
+	if env.memory != nil {
+		env.memory.enter(env)
+	}
+}
+
+// LeaveEnvironment Marks an environment entered with EnterEnvironment as no longer in use
+func LeaveEnvironment(env *Environment) {
+	if env.memory != nil {
+		env.memory.leave(env)
+	}
+}
+
+// Allocated Returns the number of bytes allocated in total
+func (m *Memory) Allocated() int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.allocated
+}
+
+// InUse Returns an estimate of the bytes in use, measured at the last
+// measurement plus the bytes allocated since
+func (m *Memory) InUse() int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.live + m.since
+}
+
+// Peak Returns the highest estimate of the bytes in use
+func (m *Memory) Peak() int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.peak
+}
+
+// allocate Counts an allocation, measuring the memory in use when enough
+// bytes have been allocated since the last measurement or the limit may be
+// exceeded. Near the limit measurements are made at least every 1/64 of it
+func (m *Memory) allocate(size int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.allocated += size
+	m.since += size
+	if m.live+m.since > m.peak {
+		m.peak = m.live + m.since
+	}
+	interval := m.live
+	if interval < MEASURE_INTERVAL {
+		interval = MEASURE_INTERVAL
+	}
+	overLimit := m.Limit > 0 && m.live+m.since > m.Limit && m.since >= m.Limit/64
+	if m.since >= interval || overLimit {
+		m.measure()
+	}
+}
+
+// measure Sizes the values reachable from the environments in use
+func (m *Memory) measure() {
+	sizer := &sizer{
+		objects:      make(map[Object]bool),
+		environments: make(map[*Environment]bool),
+	}
+	for env := range m.roots {
+		sizer.sizeEnvironment(env)
+	}
+	m.live, m.since = sizer.size, 0
+	if m.Limit > 0 && m.live > m.Limit {
+		m.exceeded = m.live
+	}
+}
+
+// check Returns a MemoryError if the limit has been exceeded and clears it
+func (m *Memory) check() *Error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.exceeded == 0 {
+		return nil
+	}
+	inUse := m.exceeded
+	m.exceeded = 0
+	return NewKindErrorf(MEMORY_ERROR, "Memory limit of %d bytes exceeded, %d bytes in use", m.Limit, inUse)
+}
+
+// reserve Checks that size more bytes may be allocated, measuring the memory in use if needed
+func (m *Memory) reserve(size int64) *Error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.Limit == 0 {
+		return nil
+	}
+	if m.live+m.since+size > m.Limit {
+		m.measure()
+	}
+	if m.live+size > m.Limit {
+		return NewKindErrorf(MEMORY_ERROR, "Memory limit of %d bytes exceeded, %d bytes in use and %d requested",
+			m.Limit, m.live, size)
+	}
+	return nil
+}
+
+// enter Adds an environment to the roots of measurements
+func (m *Memory) enter(env *Environment) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.roots[env]++
+}
+
+// leave Removes an environment from the roots of measurements once it has been left as many times as entered
+func (m *Memory) leave(env *Environment) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.roots[env] <= 1 {
+		delete(m.roots, env)
+	} else {
+		m.roots[env]--
+	}
+}
+
+// sizer Sums the sizes of values and environments, counting each once
+type sizer struct {
+	objects      map[Object]bool
+	environments map[*Environment]bool
+	shallow      bool // if set, the environments of functions and modules are not sized
+	size         int64
+}
+
+// sizeEnvironment Adds the size of an environment, its bindings and its outer environments
+func (s *sizer) sizeEnvironment(env *Environment) {
+	for ; env != nil && !s.environments[env]; env = env.outer {
+		s.environments[env] = true
+		s.size += ENVIRONMENT_SIZE
+		for name, obj := range env.Bindings() {
+			s.size += BINDING_SIZE + int64(len(name))
+			s.sizeObject(obj)
+		}
+	}
+}
+
+// sizeObject Adds the size of a value and the values it references
+func (s *sizer) sizeObject(obj Object) {
+	if obj == nil || s.objects[obj] {
+		return
+	}
+	s.objects[obj] = true
+	s.size += sizeOf(obj)
+	switch obj := obj.(type) {
+	case *Array:
+		for _, element := range obj.Elements {
+			s.sizeObject(element)
+		}
+	case *Hash:
+		for _, pair := range obj.Pairs {
+			s.sizeObject(pair.Key)
+			s.sizeObject(pair.Value)
+		}
+	case *Function:
+		if !s.shallow {
+			s.sizeEnvironment(obj.Env)
+		}
+	case *Module:
+		if !s.shallow {
+			s.sizeEnvironment(obj.Env)
+		}
+	}
+}
+
+// sizeOf Returns the size of a value, not including the values it references
+func sizeOf(obj Object) int64 {
+	switch obj := obj.(type) {
+	case *Integer:
+		return INTEGER_SIZE
+	case *String:
+		return STRING_SIZE + int64(len(obj.Value))
+	case *Array:
+		return ARRAY_SIZE + ELEMENT_SIZE*int64(len(obj.Elements))
+	case *Hash:
+		return HASH_SIZE + PAIR_SIZE*int64(len(obj.Pairs))
+	case *Function:
+		return FUNCTION_SIZE
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package object
+
+// Module Object representing an imported file and the environment it was evaluated in
+type Module struct {
+	Path string
+	Env  *Environment
+}
+
+func (module *Module) Type() ObjectType {
+	return MODULE_OBJ
+}
+
+func (module *Module) Inspect() string {
+	return "<module " + module.Path + ">"
+}
+
+// NewModule Creates a new Module object and returns a reference to it
+func NewModule(path string, env *Environment) *Module {
+	return &Module{
+		Path: path,
+		Env:  env,
+	}
+}
+
+// Member Returns the value of an exported binding of the module
+func (module *Module) Member(name string) (Object, *Error) {
+	if !module.Env.IsExported(name) {
+		return nil, NewKindErrorf(IMPORT_ERROR, "%s is not exported by module %s",
+			name, module.Path)
+	}
+	return module.Env.Get(name)
+}
@@ -7,6 +7,16 @@ const (
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
 	ERROR_OBJ        = "ERROR"
 	FUNCTION_OBJ     = "FUNCTION"
+	STRING_OBJ       = "STRING"
+	MODULE_OBJ       = "MODULE"
+	ARRAY_OBJ        = "ARRAY"
+	BUILTIN_OBJ      = "BUILTIN"
+	HASH_OBJ         = "HASH"
+	ERROR_VALUE_OBJ  = "ERROR_VALUE"
+	QUOTE_OBJ        = "QUOTE"
+	MACRO_OBJ        = "MACRO"
+	TASK_OBJ         = "TASK"
+	CHANNEL_OBJ      = "CHANNEL"
 )
 
 // ObjectType String denoting the type of an object
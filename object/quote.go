@@ -0,0 +1,26 @@
+package object
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+)
+
+// Quote Object wrapping an unevaluated AST node
+type Quote struct {
+	Node ast.Node
+}
+
+// NewQuote Creates a new quote of an AST node and returns a reference to it
+func NewQuote(node ast.Node) *Quote {
+	return &Quote{
+		Node: node,
+	}
+}
+
+func (quote *Quote) Type() ObjectType {
+	return QUOTE_OBJ
+}
+
+// Inspect Returns the quoted code, e.g. QUOTE((1 + 2))
+func (quote *Quote) Inspect() string {
+	return "QUOTE(" + quote.Node.String() + ")"
+}
@@ -0,0 +1,21 @@
+package object
+
+// String Object representing a string value
+type String struct {
+	Value string
+}
+
+func (str *String) Type() ObjectType {
+	return STRING_OBJ
+}
+
+func (str *String) Inspect() string {
+	return str.Value
+}
+
+// NewString Creates a new String object and returns a reference to it
+func NewString(value string) *String {
+	return &String{
+		Value: value,
+	}
+}
@@ -0,0 +1,39 @@
+package object
+
+// Task Object representing a function call evaluated by a goroutine of its own
+type Task struct {
+	done   chan struct{}
+	result Object
+}
+
+func (task *Task) Type() ObjectType {
+	return TASK_OBJ
+}
+
+func (task *Task) Inspect() string {
+	select {
+	case <-task.done:
+		return "<task done>"
+	default:
+		return "<task running>"
+	}
+}
+
+// Complete Stores the result of the call and wakes the waiting goroutines, must only be called once
+func (task *Task) Complete(result Object) {
+	task.result = result
+	close(task.done)
+}
+
+// Wait Blocks until the task is complete and returns its result
+func (task *Task) Wait() Object {
+	<-task.done
+	return task.result
+}
+
+// NewTask Creates a new running Task and returns a reference to it
+func NewTask() *Task {
+	return &Task{
+		done: make(chan struct{}),
+	}
+}
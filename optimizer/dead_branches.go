@@ -0,0 +1,90 @@
+package optimizer
+
+import "github.com/CzarSimon/monkey/ast"
+
+// eliminateDeadBranches ast.RewriteFunc replacing if expressions that have a
+// constant condition with the branch that is always taken
+func eliminateDeadBranches(node ast.Node) ast.Node {
+	switch node := node.(type) {
+	case *ast.IFExpression:
+		return simplifyIfExpression(node)
+	case *ast.Program:
+		node.Statements = spliceTakenBranches(node.Statements)
+	case *ast.BlockStatement:
+		node.Statements = spliceTakenBranches(node.Statements)
+	}
+	return node
+}
+
+// takenBranch Returns the branch always taken by an if expression, which is nil
+// if no branch is taken. The second value is false if the condition is not constant
+func takenBranch(ifExpr *ast.IFExpression) (*ast.BlockStatement, bool) {
+	truthy, ok := constantTruthiness(ifExpr.Condition)
+	if !ok {
+		return nil, false
+	}
+	if truthy {
+		return ifExpr.Consequence, true
+	}
+	return ifExpr.Alternative, true
+}
+
+// simplifyIfExpression Replaces an if expression by the single expression
+// of the branch it always takes, which is valid in any expression position
+func simplifyIfExpression(ifExpr *ast.IFExpression) ast.Node {
+	branch, ok := takenBranch(ifExpr)
+	if !ok || branch == nil || len(branch.Statements) != 1 {
+		return ifExpr
+	}
+	stmt, ok := branch.Statements[0].(*ast.ExpressionStatement)
+	if !ok || stmt.Expression == nil {
+		return ifExpr
+	}
+	return stmt.Expression
+}
+
+// spliceTakenBranches Replaces if statements with constant conditions by the
+// statements of the taken branch. Since blocks do not create a new environment
+// this only changes the result of the list if the if statement is the last one,
+// in which case it is kept unless the taken branch has statements of its own.
+// Branches declaring functions are kept, since their functions are hoisted
+// when the branch is evaluated rather than at the start of the enclosing list
+func spliceTakenBranches(stmts []ast.Statement) []ast.Statement {
+	spliced := make([]ast.Statement, 0, len(stmts))
+	for i, stmt := range stmts {
+		exprStmt, ok := stmt.(*ast.ExpressionStatement)
+		if !ok {
+			spliced = append(spliced, stmt)
+			continue
+		}
+		ifExpr, ok := exprStmt.Expression.(*ast.IFExpression)
+		if !ok {
+			spliced = append(spliced, stmt)
+			continue
+		}
+		branch, ok := takenBranch(ifExpr)
+		isLast := i == len(stmts)-1
+		switch {
+		case !ok || declaresFunctions(branch):
+			spliced = append(spliced, stmt)
+		case branch != nil && len(branch.Statements) > 0:
+			spliced = append(spliced, branch.Statements...)
+		case isLast:
+			spliced = append(spliced, stmt)
+		}
+	}
+	return spliced
+}
+
+// declaresFunctions Checks if a block has function statements of its own
+func declaresFunctions(block *ast.BlockStatement) bool {
+	if block == nil {
+		return false
+	}
+	for _, stmt := range block.Statements {
+		if _, ok := stmt.(*ast.FunctionStatement); ok {
+			return true
+		}
+	}
+	return false
+}
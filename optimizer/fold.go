@@ -0,0 +1,83 @@
+package optimizer
+
+import "github.com/CzarSimon/monkey/ast"
+
+// foldConstants ast.RewriteFunc replacing prefix and infix expressions on
+// constants with their value. Expressions that would produce a runtime error
+// (e.g. -true or 1 / 0) are left as they are so the error is kept
+func foldConstants(node ast.Node) ast.Node {
+	switch node := node.(type) {
+	case *ast.PrefixExpression:
+		return foldPrefixExpression(node)
+	case *ast.InfixExpression:
+		return foldInfixExpression(node)
+	}
+	return node
+}
+
+// foldPrefixExpression Folds ! and - applied to a constant
+func foldPrefixExpression(prefixExpr *ast.PrefixExpression) ast.Node {
+	switch prefixExpr.Operator {
+	case "!":
+		if truthy, ok := constantTruthiness(prefixExpr.Right); ok {
+			return newBoolean(!truthy, prefixExpr.Pos())
+		}
+	case "-":
+		if right, ok := prefixExpr.Right.(*ast.IntegerLiteral); ok {
+			return newIntegerLiteral(-right.Value, prefixExpr.Pos())
+		}
+	}
+	return prefixExpr
+}
+
+// foldInfixExpression Folds operators applied to two integer or two boolean constants
+func foldInfixExpression(infixExpr *ast.InfixExpression) ast.Node {
+	switch left := infixExpr.Left.(type) {
+	case *ast.IntegerLiteral:
+		if right, ok := infixExpr.Right.(*ast.IntegerLiteral); ok {
+			return foldIntegerInfixExpression(infixExpr, left.Value, right.Value)
+		}
+	case *ast.Boolean:
+		if right, ok := infixExpr.Right.(*ast.Boolean); ok {
+			return foldBooleanInfixExpression(infixExpr, left.Value, right.Value)
+		}
+	}
+	return infixExpr
+}
+
+// foldIntegerInfixExpression Computes the result of an integer infix operation
+func foldIntegerInfixExpression(infixExpr *ast.InfixExpression, left, right int64) ast.Node {
+	pos := infixExpr.Pos()
+	switch infixExpr.Operator {
+	case "+":
+		return newIntegerLiteral(left+right, pos)
+	case "-":
+		return newIntegerLiteral(left-right, pos)
+	case "*":
+		return newIntegerLiteral(left*right, pos)
+	case "/":
+		if right != 0 {
+			return newIntegerLiteral(left/right, pos)
+		}
+	case "<":
+		return newBoolean(left < right, pos)
+	case ">":
+		return newBoolean(left > right, pos)
+	case "==":
+		return newBoolean(left == right, pos)
+	case "!=":
+		return newBoolean(left != right, pos)
+	}
+	return infixExpr
+}
+
+// foldBooleanInfixExpression Computes the result of comparing two booleans
+func foldBooleanInfixExpression(infixExpr *ast.InfixExpression, left, right bool) ast.Node {
+	switch infixExpr.Operator {
+	case "==":
+		return newBoolean(left == right, infixExpr.Pos())
+	case "!=":
+		return newBoolean(left != right, infixExpr.Pos())
+	}
+	return infixExpr
+}
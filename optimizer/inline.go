@@ -0,0 +1,162 @@
+package optimizer
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// constant A let binding of a constant that is never rebound
+type constant struct {
+	value    ast.Expression
+	position token.Position
+	owner    ast.Node // the Program or FunctionLiteral in whose environment it is bound
+}
+
+// inlineConstants Replaces references to constant let bindings with their value.
+// A binding is only inlined if its name is bound exactly once in the program,
+// it is bound directly in a program or function body (not conditionally in an
+// if branch) and the reference is inside the same function and after the let.
+// Bindings at the top level of the program are only inlined if topLevel is set
+func inlineConstants(program *ast.Program, topLevel bool) {
+	constants := findConstants(program, topLevel)
+	if len(constants) == 0 {
+		return
+	}
+	targets := make(map[*ast.Identifier]ast.Expression)
+	ast.Walk(&inlineVisitor{
+		owners:    []ast.Node{program},
+		constants: constants,
+		targets:   targets,
+	}, program)
+	ast.Rewrite(program, func(node ast.Node) ast.Node {
+		id, ok := node.(*ast.Identifier)
+		if !ok {
+			return node
+		}
+		if value, ok := targets[id]; ok {
+			return copyLiteral(value, id.Pos())
+		}
+		return node
+	})
+}
+
+// findConstants Finds the let bindings of constants that can be inlined
+func findConstants(program *ast.Program, topLevel bool) map[string]*constant {
+	bindCount := make(map[string]int)
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.LetStatement:
+			for _, name := range node.Names() {
+				bindCount[name.Value]++
+			}
+		case *ast.FunctionLiteral:
+			for _, param := range node.Parameters {
+				bindCount[param.Value]++
+			}
+		case *ast.MacroLiteral:
+			for _, param := range node.Parameters {
+				bindCount[param.Value]++
+			}
+		case *ast.TryExpression:
+			bindCount[node.CatchParam.Value]++
+		case *ast.FunctionStatement:
+			bindCount[node.Name.Value]++
+		case *ast.MatchExpression:
+			for _, arm := range node.Arms {
+				ast.Inspect(arm.Pattern, func(patternNode ast.Node) bool {
+					if id, ok := patternNode.(*ast.Identifier); ok {
+						bindCount[id.Value]++
+					}
+					return true
+				})
+			}
+		}
+		return true
+	})
+	constants := make(map[string]*constant)
+	addConstants := func(owner ast.Node, stmts []ast.Statement) {
+		for _, stmt := range stmts {
+			letStmt, ok := stmt.(*ast.LetStatement)
+			if !ok || letStmt.Name == nil || !isConstant(letStmt.Value) || bindCount[letStmt.Name.Value] != 1 {
+				continue
+			}
+			constants[letStmt.Name.Value] = &constant{
+				value:    letStmt.Value,
+				position: letStmt.Name.Pos(),
+				owner:    owner,
+			}
+		}
+	}
+	if topLevel {
+		addConstants(program, program.Statements)
+	}
+	ast.Inspect(program, func(node ast.Node) bool {
+		if fn, ok := node.(*ast.FunctionLiteral); ok && fn.Body != nil {
+			addConstants(fn, fn.Body.Statements)
+		}
+		return true
+	})
+	return constants
+}
+
+// inlineVisitor ast.Visitor finding the identifiers that refer to constants
+type inlineVisitor struct {
+	owners    []ast.Node
+	constants map[string]*constant
+	targets   map[*ast.Identifier]ast.Expression
+}
+
+// Visit Records identifiers referring to a visible constant
+func (v *inlineVisitor) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.FunctionLiteral:
+		owners := make([]ast.Node, len(v.owners), len(v.owners)+1)
+		copy(owners, v.owners)
+		return &inlineVisitor{
+			owners:    append(owners, node),
+			constants: v.constants,
+			targets:   v.targets,
+		}
+	case *ast.FunctionStatement:
+		// hoisted functions can be called before the lets preceding
+		// them, so only their own constants are visible
+		if node.Function != nil {
+			ast.Walk(&inlineVisitor{
+				owners:    []ast.Node{},
+				constants: v.constants,
+				targets:   v.targets,
+			}, node.Function)
+		}
+		return nil
+	case *ast.LetStatement:
+		if node.Value != nil {
+			ast.Walk(v, node.Value)
+		}
+		return nil
+	case *ast.CallExpression:
+		if isQuote(node) {
+			return nil
+		}
+	case *ast.Identifier:
+		c, ok := v.constants[node.Value]
+		if ok && v.isVisible(c) && isAfter(node.Pos(), c.position) {
+			v.targets[node] = c.value
+		}
+	}
+	return v
+}
+
+// isVisible Checks if the constant is bound in the environment of an enclosing function
+func (v *inlineVisitor) isVisible(c *constant) bool {
+	for _, owner := range v.owners {
+		if owner == c.owner {
+			return true
+		}
+	}
+	return false
+}
+
+// isAfter Checks if position a comes after position b in the source
+func isAfter(a, b token.Position) bool {
+	return a.Line > b.Line || (a.Line == b.Line && a.Column > b.Column)
+}
@@ -0,0 +1,61 @@
+package optimizer
+
+import (
+	"strconv"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// newIntegerLiteral Creates an IntegerLiteral positioned at pos
+func newIntegerLiteral(value int64, pos token.Position) *ast.IntegerLiteral {
+	return &ast.IntegerLiteral{
+		Token: token.Token{
+			Type:     token.INT,
+			Literal:  strconv.FormatInt(value, 10),
+			Position: pos,
+		},
+		Value: value,
+	}
+}
+
+// newBoolean Creates a Boolean positioned at pos
+func newBoolean(value bool, pos token.Position) *ast.Boolean {
+	tok := token.Token{Type: token.FALSE, Literal: "false", Position: pos}
+	if value {
+		tok = token.Token{Type: token.TRUE, Literal: "true", Position: pos}
+	}
+	return ast.NewBoolean(tok)
+}
+
+// copyLiteral Creates a copy of a constant literal positioned at pos
+func copyLiteral(literal ast.Expression, pos token.Position) ast.Expression {
+	switch literal := literal.(type) {
+	case *ast.IntegerLiteral:
+		return newIntegerLiteral(literal.Value, pos)
+	case *ast.Boolean:
+		return newBoolean(literal.Value, pos)
+	}
+	return literal
+}
+
+// isConstant Checks if an expression is a literal with a value known before evaluation
+func isConstant(expr ast.Expression) bool {
+	switch expr.(type) {
+	case *ast.IntegerLiteral, *ast.Boolean:
+		return true
+	}
+	return false
+}
+
+// constantTruthiness Returns the truthiness of a constant expression
+// and whether the expression was constant
+func constantTruthiness(expr ast.Expression) (bool, bool) {
+	switch expr := expr.(type) {
+	case *ast.Boolean:
+		return expr.Value, true
+	case *ast.IntegerLiteral:
+		return true, true
+	}
+	return false, false
+}
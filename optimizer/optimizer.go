@@ -0,0 +1,75 @@
+package optimizer
+
+import "github.com/CzarSimon/monkey/ast"
+
+// maxIterations Upper bound on the number of times the passes are repeated
+const maxIterations = 10
+
+// quoteFunction Name of the call whose argument is code kept as written for macros
+const quoteFunction = "quote"
+
+// Optimize Rewrites a program into an equivalent one that does less work when
+// evaluated. Constant expressions are folded, if expressions with constant
+// conditions are replaced by the branch taken and let bindings of constants
+// that are never rebound are inlined. The passes are repeated until the program
+// stops changing since each of them can create work for the others. The
+// arguments of quote calls are left as written, unquoted code included
+func Optimize(program *ast.Program) *ast.Program {
+	return optimize(program, true)
+}
+
+// OptimizeIncremental Optimizes a program evaluated in an environment that
+// programs evaluated later may bind names in, e.g. an input of a repl. The
+// let bindings at its top level are not inlined since they may be rebound
+func OptimizeIncremental(program *ast.Program) *ast.Program {
+	return optimize(program, false)
+}
+
+// optimize Repeats the passes over a program, inlining top level let bindings if topLevel is set
+func optimize(program *ast.Program, topLevel bool) *ast.Program {
+	previous := program.String()
+	for i := 0; i < maxIterations; i++ {
+		ast.Rewrite(program, outsideQuotes(program, foldConstants))
+		inlineConstants(program, topLevel)
+		ast.Rewrite(program, outsideQuotes(program, eliminateDeadBranches))
+		current := program.String()
+		if current == previous {
+			break
+		}
+		previous = current
+	}
+	return program
+}
+
+// outsideQuotes Wraps an ast.RewriteFunc to leave the nodes in the arguments of quote calls unchanged
+func outsideQuotes(program *ast.Program, fn ast.RewriteFunc) ast.RewriteFunc {
+	quoted := make(map[ast.Node]bool)
+	ast.Inspect(program, func(node ast.Node) bool {
+		if !isQuote(node) {
+			return true
+		}
+		for _, arg := range node.(*ast.CallExpression).Arguments {
+			ast.Inspect(arg, func(argNode ast.Node) bool {
+				quoted[argNode] = true
+				return true
+			})
+		}
+		return false
+	})
+	return func(node ast.Node) ast.Node {
+		if quoted[node] {
+			return node
+		}
+		return fn(node)
+	}
+}
+
+// isQuote Checks if a node is a call to quote
+func isQuote(node ast.Node) bool {
+	call, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+	name, ok := call.Function.(*ast.Identifier)
+	return ok && name.Value == quoteFunction
+}
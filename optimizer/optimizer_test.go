@@ -0,0 +1,137 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60 * 60 * 24", "86400"},
+		{"!true", "false"},
+		{"!5", "false"},
+		{"--5", "5"},
+		{"1 < 2 == true", "true"},
+		{"true != false", "true"},
+		{"x * (2 + 3)", "(x * 5)"},
+		{"1 / 0", "(1 / 0)"},
+		{"-true", "(-true)"},
+		{"5 + true", "(5 + true)"},
+		{"if (true) { 10 } else { 20 }", "10"},
+		{"if (1 > 2) { 10 } else { 20 }", "20"},
+		{"let y = if (false) { 10 } else { x }; y", "let y = x;y"},
+		{"if (false) { 10 }; 5", "5"},
+		{"if (false) { 10 }", "if false 10"},
+		{"if (true) { let a = x; a }; a", "let a = x;aa"},
+		{"let x = 2 * 3; x + 1", "let x = 6;7"},
+		{"let x = 2; let x = 3; x", "let x = 2;let x = 3;x"},
+		{"let x = 2; let f = fn(x) { x }; x", "let x = 2;let f = fn(x) x;x"},
+		{"let f = fn() { x }; let x = 2; x", "let f = fn() x;let x = 2;2"},
+		{"let x = 2; let f = fn() { x * x }; f()", "let x = 2;let f = fn() 4;f()"},
+		{"let f = fn() { let x = 2; x }; x", "let f = fn() let x = 2;2;x"},
+		{"if (true) { let x = 1; } x", "let x = 1;1"},
+		{"let f = fn(a) { if (true) { return a; } 5 }; f(1)", "let f = fn(a) return a;5;f(1)"},
+		{"let e = 1; try { e } catch (e) { e }", "let e = 1;try { e } catch (e) { e }"},
+		{"f(); let x = 2; fn f() { let y = 3; x * y }", "f()let x = 2;fn f() let y = 3;(x * 3)"},
+		{"f(); if (true) { fn f() { 1 } }", "f()if true fn f() 1"},
+		{"let x = 1; quote(x + 1)", "let x = 1;quote((x + 1))"},
+		{"quote(2 * 3 + unquote(1 + 1))", "quote(((2 * 3) + unquote((1 + 1))))"},
+		{"quote(if (true) { 1 } else { 2 })", "quote(if true 1 else 2)"},
+		{"let y = 2; [quote(y), y * 3]", "let y = 2;[quote(y), 6]"},
+		{"if (false) { 1 } else { fn f() { 1 } f() }; 2", "if false 1 else fn f() 1f()2"},
+	}
+	for i, test := range tests {
+		program := testParse(t, test.input)
+		optimized := Optimize(program)
+		if optimized.String() != test.expected {
+			t.Errorf("%d - Wrong optimized program Expected=[ %s ] Got=[ %s ]",
+				i, test.expected, optimized.String())
+		}
+	}
+}
+
+func TestOptimizeIncremental(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 2 * 3; x + 1", "let x = 6;(x + 1)"},
+		{"let f = fn() { let y = 2; y * x }", "let f = fn() let y = 2;(2 * x);"},
+		{"if (true) { 1 + 1 }", "2"},
+	}
+	for i, test := range tests {
+		optimized := OptimizeIncremental(testParse(t, test.input))
+		if optimized.String() != test.expected {
+			t.Errorf("%d - Wrong optimized program Expected=[ %s ] Got=[ %s ]",
+				i, test.expected, optimized.String())
+		}
+	}
+}
+
+func TestOptimizedEvaluationIsUnchanged(t *testing.T) {
+	inputs := []string{
+		"60 * 60 * 24",
+		"!true",
+		"!!5",
+		"-(2 * 3) + 10 / 2",
+		"(1 < 2) == (3 > 2)",
+		"5 + true",
+		"-true",
+		"if (1 > 2) { 10 }",
+		"if (1 < 2) { 10 } else { 20 }",
+		"if (false) { 10 }; 5",
+		"let seconds = 60 * 60 * 24; seconds * 7",
+		"let double = fn(x) { x * 2 }; double(2 + 3)",
+		"let limit = 10; let check = fn(n) { if (n > limit) { return true; } false }; check(11)",
+		"let f = fn() { if (true) { return 1; } 2 }; f()",
+		"let x = 1; let f = fn() { x }; let x = 2; f()",
+		"let f = fn() { y }; let y = 3; f()",
+		"let g = fn() { let z = 4; z }; g() + 1",
+		"if (true) { let a = 1 }; a",
+		"if (false) { let b = 1 }; b",
+		"let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } }; fact(5)",
+		"let x = 2; try { throw \"a\" } catch (e) { x * 3 }",
+		"f(); let x = 2; fn f() { x }",
+		"let x = 2; fn f() { x }; f()",
+		"f(); if (true) { fn f() { 1 } }",
+		"if (true) { fn f() { 1 } f() }",
+		"if (false) { 1 } else { fn g() { 2 } g() }; g()",
+		"let x = 1; quote(x + 1)",
+		"let x = 1; quote(unquote(x + 1) * (2 + 3))",
+		"let twice = macro(e) { quote(unquote(e) + unquote(e)) }; let x = 2; twice(x * 3)",
+	}
+	for i, input := range inputs {
+		expected := testEvalProgram(testParse(t, input))
+		optimized := testEvalProgram(Optimize(testParse(t, input)))
+		if expected.Inspect() != optimized.Inspect() {
+			t.Errorf("%d - Optimization changed result of [ %s ] Expected=%s Got=%s",
+				i, input, expected.Inspect(), optimized.Inspect())
+		}
+	}
+}
+
+// testEvalProgram Evaluates a program in a new environment
+func testEvalProgram(program *ast.Program) object.Object {
+	evaluated := evaluator.Eval(program, object.NewEnvironment())
+	if evaluated == nil {
+		return evaluator.NULL
+	}
+	return evaluated
+}
+
+func testParse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors: %v", p.Errors())
+	}
+	return program
+}
@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/CzarSimon/monkey/token"
+)
+
+// ParseError Error found while parsing, positioned at the token that caused it
+type ParseError struct {
+	Position token.Position
+	Message  string
+}
+
+// Error Returns the message of the error prefixed by its position, e.g. file:line:col: message
+func (err *ParseError) Error() string {
+	if !err.Position.IsValid() {
+		return err.Message
+	}
+	return err.Position.String() + ": " + err.Message
+}
+
+// newParseError Creates a ParseError with a formated message positioned at pos
+func newParseError(pos token.Position, format string, a ...interface{}) *ParseError {
+	return &ParseError{
+		Position: pos,
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
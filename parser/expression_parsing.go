@@ -110,20 +110,127 @@ func (parser *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+// parseTryExpression Parses a TryExpression with a catch and an optional finally block
+func (parser *Parser) parseTryExpression() ast.Expression {
+	tryExpr := ast.NewTryExpression(parser.currentToken)
+	if err := parser.expectPeek(token.LBRACE); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	tryExpr.Body = parser.parseBlockStatement()
+	if err := parser.expectPeekSequence(token.CATCH, token.LPAREN, token.IDENT); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	tryExpr.CatchParam = ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal)
+	if err := parser.expectPeekSequence(token.RPAREN, token.LBRACE); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	tryExpr.Catch = parser.parseBlockStatement()
+	if parser.peekTokenIs(token.FINALLY) {
+		parser.nextToken()
+		if err := parser.expectPeek(token.LBRACE); err != nil {
+			parser.AddError(err)
+			return nil
+		}
+		tryExpr.Finally = parser.parseBlockStatement()
+	}
+	return tryExpr
+}
+
+// parseMatchExpression Parses a MatchExpression with comma separated arms
+func (parser *Parser) parseMatchExpression() ast.Expression {
+	matchExpr := ast.NewMatchExpression(parser.currentToken)
+	if err := parser.expectPeek(token.LPAREN); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	parser.nextToken()
+	matchExpr.Subject = parser.parseExpression(LOWEST)
+	if err := parser.expectPeekSequence(token.RPAREN, token.LBRACE); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	for !parser.peekTokenIs(token.RBRACE) {
+		parser.nextToken()
+		arm, err := parser.parseMatchArm()
+		if err != nil {
+			parser.AddError(err)
+			return nil
+		}
+		matchExpr.Arms = append(matchExpr.Arms, arm)
+		if !parser.peekTokenIs(token.RBRACE) {
+			if err := parser.expectPeek(token.COMMA); err != nil {
+				parser.AddError(err)
+				return nil
+			}
+		}
+	}
+	if err := parser.expectPeek(token.RBRACE); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	return matchExpr
+}
+
+// parseMatchArm Parses a pattern, an optional if guard and the body of a match arm
+func (parser *Parser) parseMatchArm() (ast.MatchArm, error) {
+	arm := ast.MatchArm{Pattern: parser.parseExpression(LOWEST)}
+	if err := checkPattern(arm.Pattern); err != nil {
+		return arm, err
+	}
+	if parser.peekTokenIs(token.IF) {
+		parser.nextToken()
+		parser.nextToken()
+		arm.Guard = parser.parseExpression(LOWEST)
+	}
+	if err := parser.expectPeek(token.ARROW); err != nil {
+		return arm, err
+	}
+	parser.nextToken()
+	arm.Body = parser.parseExpression(LOWEST)
+	return arm, nil
+}
+
 // parseFunctionLiteral Parses a FunctionLiteral
 func (parser *Parser) parseFunctionLiteral() ast.Expression {
 	fn := ast.NewFunctionLiteral(parser.currentToken)
-	if err := parser.expectPeek(token.LPAREN); err != nil {
+	if err := parser.parseFunctionParametersAndBody(fn); err != nil {
 		parser.AddError(err)
 		return nil
 	}
+	return fn
+}
+
+// parseFunctionParametersAndBody Parses the parameter list and body following
+// the current token into a FunctionLiteral
+func (parser *Parser) parseFunctionParametersAndBody(fn *ast.FunctionLiteral) error {
+	if err := parser.expectPeek(token.LPAREN); err != nil {
+		return err
+	}
 	fn.Parameters = parser.parseFunctionParameters()
 	if err := parser.expectPeek(token.LBRACE); err != nil {
+		return err
+	}
+	fn.Body = parser.parseBlockStatement()
+	return nil
+}
+
+// parseMacroLiteral Parses a MacroLiteral
+func (parser *Parser) parseMacroLiteral() ast.Expression {
+	macro := ast.NewMacroLiteral(parser.currentToken)
+	if err := parser.expectPeek(token.LPAREN); err != nil {
 		parser.AddError(err)
 		return nil
 	}
-	fn.Body = parser.parseBlockStatement()
-	return fn
+	macro.Parameters = parser.parseFunctionParameters()
+	if err := parser.expectPeek(token.LBRACE); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	macro.Body = parser.parseBlockStatement()
+	return macro
 }
 
 // parseFunctionParameters Parses a comma separated list of Identifiers as
@@ -153,27 +260,101 @@ func (parser *Parser) parseFunctionParameters() []*ast.Identifier {
 // parseCallExpression Parses a CallExpression
 func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	call := ast.NewCallExpression(parser.currentToken, function)
-	call.Arguments = parser.parseCallArguments()
+	call.Arguments = parser.parseExpressionList(token.RPAREN)
 	return call
 }
 
-// parseCallExpression Parses a comma separated list of function arguments
-func (parser *Parser) parseCallArguments() []ast.Expression {
-	args := make([]ast.Expression, 0)
-	if parser.peekTokenIs(token.RPAREN) {
+// parseExpressionList Parses a comma separated list of expressions
+// terminated by a token of the supplied type
+func (parser *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := make([]ast.Expression, 0)
+	if parser.peekTokenIs(end) {
 		parser.nextToken()
-		return args
+		return list
 	}
 	parser.nextToken()
-	args = append(args, parser.parseExpression(LOWEST))
+	list = append(list, parser.parseExpression(LOWEST))
 	for parser.peekTokenIs(token.COMMA) {
 		parser.nextToken()
 		parser.nextToken()
-		args = append(args, parser.parseExpression(LOWEST))
+		list = append(list, parser.parseExpression(LOWEST))
 	}
-	if err := parser.expectPeek(token.RPAREN); err != nil {
+	if err := parser.expectPeek(end); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	return list
+}
+
+// parseArrayLiteral Parses an ArrayLiteral
+func (parser *Parser) parseArrayLiteral() ast.Expression {
+	array := ast.NewArrayLiteral(parser.currentToken)
+	array.Elements = parser.parseExpressionList(token.RBRACKET)
+	return array
+}
+
+// parseHashLiteral Parses a HashLiteral of comma separated key: value pairs
+func (parser *Parser) parseHashLiteral() ast.Expression {
+	hash := ast.NewHashLiteral(parser.currentToken)
+	for !parser.peekTokenIs(token.RBRACE) {
+		parser.nextToken()
+		key := parser.parseExpression(LOWEST)
+		if err := parser.expectPeek(token.COLON); err != nil {
+			parser.AddError(err)
+			return nil
+		}
+		parser.nextToken()
+		value := parser.parseExpression(LOWEST)
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+		if !parser.peekTokenIs(token.RBRACE) {
+			if err := parser.expectPeek(token.COMMA); err != nil {
+				parser.AddError(err)
+				return nil
+			}
+		}
+	}
+	if err := parser.expectPeek(token.RBRACE); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	return hash
+}
+
+// parseIndexExpression Parses an IndexExpression
+func (parser *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	indexExpr := ast.NewIndexExpression(parser.currentToken, left)
+	parser.nextToken()
+	indexExpr.Index = parser.parseExpression(LOWEST)
+	if err := parser.expectPeek(token.RBRACKET); err != nil {
+		parser.AddError(err)
+		return nil
+	}
+	return indexExpr
+}
+
+// parseStringLiteral Parses a StringLiteral
+func (parser *Parser) parseStringLiteral() ast.Expression {
+	return ast.NewStringLiteral(parser.currentToken)
+}
+
+// parseImportExpression Parses an ImportExpression
+func (parser *Parser) parseImportExpression() ast.Expression {
+	importExpr := ast.NewImportExpression(parser.currentToken)
+	parser.nextToken()
+	importExpr.Path = parser.parseExpression(PREFIX)
+	if importExpr.Path == nil {
+		return nil
+	}
+	return importExpr
+}
+
+// parseMemberExpression Parses a MemberExpression
+func (parser *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	member := ast.NewMemberExpression(parser.currentToken, object)
+	if err := parser.expectPeek(token.IDENT); err != nil {
 		parser.AddError(err)
 		return nil
 	}
-	return args
+	member.Property = ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal)
+	return member
 }
@@ -1,9 +1,6 @@
 package parser
 
 import (
-	"errors"
-	"fmt"
-
 	"github.com/CzarSimon/monkey/ast"
 	"github.com/CzarSimon/monkey/lexer"
 	"github.com/CzarSimon/monkey/token"
@@ -19,6 +16,7 @@ const (
 	PRODUCT    // *
 	PREFIX     // -X or !X
 	CALL       // myFunc(X)
+	INDEX      // array[index] or module.member
 )
 
 // Parser A series of tokens into an abstract source tree (AST)
@@ -48,6 +46,13 @@ func New(lex *lexer.Lexer) *Parser {
 	parser.registerPrefix(token.IF, parser.parseIfExpression)
 	parser.registerPrefix(token.LPAREN, parser.parseGroupedExpression)
 	parser.registerPrefix(token.FUNCTION, parser.parseFunctionLiteral)
+	parser.registerPrefix(token.STRING, parser.parseStringLiteral)
+	parser.registerPrefix(token.IMPORT, parser.parseImportExpression)
+	parser.registerPrefix(token.LBRACKET, parser.parseArrayLiteral)
+	parser.registerPrefix(token.LBRACE, parser.parseHashLiteral)
+	parser.registerPrefix(token.TRY, parser.parseTryExpression)
+	parser.registerPrefix(token.MATCH, parser.parseMatchExpression)
+	parser.registerPrefix(token.MACRO, parser.parseMacroLiteral)
 	parser.registerInfix(token.PLUS, parser.parseInfixExpression)
 	parser.registerInfix(token.MINUS, parser.parseInfixExpression)
 	parser.registerInfix(token.DIVIDE, parser.parseInfixExpression)
@@ -57,6 +62,8 @@ func New(lex *lexer.Lexer) *Parser {
 	parser.registerInfix(token.LT, parser.parseInfixExpression)
 	parser.registerInfix(token.GT, parser.parseInfixExpression)
 	parser.registerInfix(token.LPAREN, parser.parseCallExpression)
+	parser.registerInfix(token.DOT, parser.parseMemberExpression)
+	parser.registerInfix(token.LBRACKET, parser.parseIndexExpression)
 	parser.nextToken()
 	parser.nextToken()
 	return parser
@@ -92,6 +99,17 @@ func (parser *Parser) expectPeek(tokenType token.TokenType) error {
 	return parser.peekError(tokenType)
 }
 
+// expectPeekSequence Advances over a sequence of tokens of the supplied types,
+// returns an error for the first token not matching its expected type
+func (parser *Parser) expectPeekSequence(tokenTypes ...token.TokenType) error {
+	for _, tokenType := range tokenTypes {
+		if err := parser.expectPeek(tokenType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // currentTokenIs Checks if currentToken is of a supplied type
 func (parser *Parser) currentTokenIs(tokenType token.TokenType) bool {
 	return parser.currentToken.Type == tokenType
@@ -107,15 +125,19 @@ func (parser *Parser) Errors() []error {
 	return parser.errors
 }
 
-// AddError Adds a parse error to the parsers list of errors
+// AddError Adds a parse error to the parsers list of errors, errors
+// that are not a *ParseError are positioned at the current token
 func (parser *Parser) AddError(err error) {
+	if _, ok := err.(*ParseError); !ok {
+		err = newParseError(parser.currentToken.Position, "%s", err)
+	}
 	parser.errors = append(parser.errors, err)
 }
 
 // peekError Adds an error caused by unexpected token type
 func (parser *Parser) peekError(tokenType token.TokenType) error {
-	return errors.New(fmt.Sprintf(
-		"peekToken: Expected type=%s Got=%s", tokenType, parser.peekToken.Type))
+	return newParseError(parser.peekToken.Position,
+		"peekToken: Expected type=%s Got=%s", tokenType, parser.peekToken.Type)
 }
 
 // registerPrefix Adds a prefix function to a particular token type
@@ -131,7 +153,7 @@ func (parser *Parser) getCurrentTokensPrefixFn() prefixParseFn {
 // noPrefixParseFnError Creates and adds an error when no prefixParseFn is
 // found for a given token type
 func (parser *Parser) noPrefixParseFnError(tokenType token.TokenType) {
-	err := fmt.Errorf("No prefixParseFn for TokenType=%s found", tokenType)
+	err := newParseError(parser.currentToken.Position, "No prefixParseFn for TokenType=%s found", tokenType)
 	parser.AddError(err)
 }
 
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/CzarSimon/monkey/ast"
@@ -104,7 +105,7 @@ func TestReturnStatement(t *testing.T) {
 			continue
 		}
 		if returnStmt.TokenLiteral() != "return" {
-			t.Errorf("returnStmt.TokenLiteral not 'return' Got=‰q", returnStmt.TokenLiteral())
+			t.Errorf("returnStmt.TokenLiteral not 'return' Got=%q", returnStmt.TokenLiteral())
 		}
 	}
 }
@@ -268,6 +269,8 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a + b + c * d / f + g)",
 			"add((((a + b) + ((c * d) / f)) + g))",
 		},
+		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
+		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
 	}
 	for _, test := range tests {
 		program := testParseProgram(t, test.input, []string{})
@@ -354,6 +357,28 @@ func TestIfElseExpression(t *testing.T) {
 	}
 }
 
+func TestBlockStatementWithMultipleStatements(t *testing.T) {
+	input := "fn(x) { let y = x * 2; return y; }"
+	program := testParseProgram(t, input, []string{})
+	testNumberOfStatemets(t, program, 1)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	fn, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.FunctionLiteral, Got=%T", stmt.Expression)
+	}
+	if len(fn.Body.Statements) != 2 {
+		t.Fatalf("Unexpected number of statements in fn.Body Expected=2 Got=%d",
+			len(fn.Body.Statements))
+	}
+	if !testLetStatement(t, fn.Body.Statements[0], "y") {
+		return
+	}
+	if _, ok := fn.Body.Statements[1].(*ast.ReturnStatement); !ok {
+		t.Fatalf("fn.Body.Statements[1] not an *ast.ReturnStatement, Got=%T",
+			fn.Body.Statements[1])
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := "fn(x, y) { x + y; }"
 	program := testParseProgram(t, input, []string{})
@@ -399,7 +424,7 @@ func TestFunctionParameterParsing(t *testing.T) {
 		stmt := program.Statements[0].(*ast.ExpressionStatement)
 		fn := stmt.Expression.(*ast.FunctionLiteral)
 		if len(fn.Parameters) != len(test.expectedParams) {
-			t.Error("Wrong number of fn.Prameters Expected=%d Got=%d",
+			t.Errorf("Wrong number of fn.Prameters Expected=%d Got=%d",
 				len(test.expectedParams), len(fn.Parameters))
 		}
 		for i, ident := range test.expectedParams {
@@ -408,6 +433,89 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestConstStatementParsing(t *testing.T) {
+	program := testParseProgram(t, "const limit = 10; let x = 1;", []string{})
+	testNumberOfStatemets(t, program, 2)
+	constStmt, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.LetStatement Got=%T", program.Statements[0])
+	}
+	if !constStmt.IsConst() || constStmt.String() != "const limit = 10;" {
+		t.Errorf("Wrong const statement Got=%s", constStmt.String())
+	}
+	if program.Statements[1].(*ast.LetStatement).IsConst() {
+		t.Errorf("let statement reported as const")
+	}
+	testParseProgram(t, "const 1", []string{"peekToken: Expected type=IDENT Got=INT"})
+}
+
+func TestDestructuringLetParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		names    []string
+	}{
+		{"let [a, b] = xs;", "let [a, b] = xs;", []string{"a", "b"}},
+		{"let [a, _, ...rest] = xs;", "let [a, _, ...rest] = xs;", []string{"a", "rest"}},
+		{"let [...all] = xs;", "let [...all] = xs;", []string{"all"}},
+		{"let [] = xs;", "let [] = xs;", []string{}},
+		{"const {name, age} = person;", "const {name, age} = person;", []string{"name", "age"}},
+		{"export let {x} = p;", "export let {x} = p;", []string{"x"}},
+	}
+	for _, test := range tests {
+		program := testParseProgram(t, test.input, []string{})
+		if program.String() != test.expected {
+			t.Errorf("Wrong program Expected=%s Got=%s", test.expected, program.String())
+		}
+		var letStmt *ast.LetStatement
+		switch stmt := program.Statements[0].(type) {
+		case *ast.LetStatement:
+			letStmt = stmt
+		case *ast.ExportStatement:
+			letStmt = stmt.Statement
+		}
+		names := make([]string, 0)
+		for _, name := range letStmt.Names() {
+			names = append(names, name.Value)
+		}
+		if strings.Join(names, ",") != strings.Join(test.names, ",") {
+			t.Errorf("Wrong names Expected=%v Got=%v", test.names, names)
+		}
+	}
+	testParseProgram(t, "let [a, 1", []string{"peekToken: Expected type=IDENT Got=INT"})
+	testParseProgram(t, "let [...a, b", []string{"peekToken: Expected type=] Got=,",
+		"No prefixParseFn for TokenType=, found"})
+	testParseProgram(t, "let {a b", []string{"peekToken: Expected type=, Got=IDENT"})
+}
+
+func TestFunctionStatementParsing(t *testing.T) {
+	program := testParseProgram(t, "fn add(x, y) { x + y }; add(1, 2)", []string{})
+	testNumberOfStatemets(t, program, 2)
+	stmt, ok := program.Statements[0].(*ast.FunctionStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.FunctionStatement Got=%T", program.Statements[0])
+	}
+	testIdentifier(t, stmt.Name, "add")
+	if stmt.Function.Name != "add" || len(stmt.Function.Parameters) != 2 {
+		t.Errorf("Wrong function Got=%+v", stmt.Function)
+	}
+	if stmt.String() != "fn add(x, y) (x + y)" {
+		t.Errorf("Wrong stmt.String() Got=%s", stmt.String())
+	}
+	program = testParseProgram(t, "let double = fn(x) { x * 2 }; let y = fn(x) { x }(1);", []string{})
+	fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if fn.Name != "double" {
+		t.Errorf("Wrong fn.Name Expected=double Got=%s", fn.Name)
+	}
+	if program.String() != "let double = fn(x) (x * 2);let y = fn(x) x(1);" {
+		t.Errorf("Wrong program Got=%s", program.String())
+	}
+	program = testParseProgram(t, "fn(x) { x }(1)", []string{})
+	if _, ok := program.Statements[0].(*ast.ExpressionStatement); !ok {
+		t.Errorf("Anonymous fn statement not parsed as ast.ExpressionStatement Got=%T", program.Statements[0])
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5)"
 	program := testParseProgram(t, input, []string{})
@@ -433,6 +541,187 @@ func TestCallExpressionParsing(t *testing.T) {
 	testInfixExpression(t, call.Arguments[2], 4, "+", 5)
 }
 
+func TestArrayLiteralParsing(t *testing.T) {
+	program := testParseProgram(t, "[1, 2 * 2, 3 + 3]", []string{})
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.ArrayLiteral Got=%T", stmt.Expression)
+	}
+	if len(array.Elements) != 3 {
+		t.Fatalf("Wrong number of elements Expected=3 Got=%d", len(array.Elements))
+	}
+	testIntegerLiteral(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, "*", 2)
+	testInfixExpression(t, array.Elements[2], 3, "+", 3)
+	program = testParseProgram(t, "[]", []string{})
+	stmt = program.Statements[0].(*ast.ExpressionStatement)
+	if array := stmt.Expression.(*ast.ArrayLiteral); len(array.Elements) != 0 {
+		t.Errorf("Wrong number of elements Expected=0 Got=%d", len(array.Elements))
+	}
+}
+
+func TestHashLiteralParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{}`, `{}`},
+		{`{"one": 1, "two": 2}`, `{"one": 1, "two": 2}`},
+		{`{"a": 1 + 2, true: b * c, 3: [1]}`, `{"a": (1 + 2), true: (b * c), 3: [1]}`},
+		{`{"a": {"b": 1}}["a"]`, `({"a": {"b": 1}}["a"])`},
+	}
+	for _, test := range tests {
+		program := testParseProgram(t, test.input, []string{})
+		if program.String() != test.expected {
+			t.Errorf("Wrong program Expected=%s Got=%s", test.expected, program.String())
+		}
+	}
+	program := testParseProgram(t, `{"one": 1, "two": 2}`, []string{})
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.HashLiteral Got=%T", stmt.Expression)
+	}
+	if len(hash.Pairs) != 2 {
+		t.Fatalf("Wrong number of pairs Expected=2 Got=%d", len(hash.Pairs))
+	}
+	testIntegerLiteral(t, hash.Pairs[1].Value, 2)
+	testParseProgram(t, `{"a" 1}`, []string{
+		"peekToken: Expected type=: Got=INT", "No prefixParseFn for TokenType=} found"})
+}
+
+func TestTryExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { f() } catch (e) { 0 }`, `try { f() } catch (e) { 0 }`},
+		{`try { f() } catch (err) { g(err) } finally { h() }`, `try { f() } catch (err) { g(err) } finally { h() }`},
+		{`let x = try { 1 } catch (e) { 2 };`, `let x = try { 1 } catch (e) { 2 };`},
+		{`throw "oops";`, `throw "oops";`},
+		{`throw error("a", "ValueError")`, `throw error("a", "ValueError");`},
+	}
+	for _, test := range tests {
+		program := testParseProgram(t, test.input, []string{})
+		if program.String() != test.expected {
+			t.Errorf("Wrong program Expected=%s Got=%s", test.expected, program.String())
+		}
+	}
+	program := testParseProgram(t, `try { a } catch (e) { b } finally { c }`, []string{})
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	tryExpr, ok := stmt.Expression.(*ast.TryExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.TryExpression Got=%T", stmt.Expression)
+	}
+	testIdentifier(t, tryExpr.CatchParam, "e")
+	if tryExpr.Finally == nil || tryExpr.Finally.String() != "c" {
+		t.Errorf("Wrong finally block Got=%+v", tryExpr.Finally)
+	}
+	testParseProgram(t, `try { a }`, []string{"peekToken: Expected type=CATCH Got=EOF"})
+	testParseProgram(t, `try { a } catch (1)`, []string{"peekToken: Expected type=IDENT Got=INT", "No prefixParseFn for TokenType=) found"})
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (x) { 1 => "one", _ => "other" }`, `match (x) { 1 => "one", _ => "other" }`},
+		{`match (x) { -1 => a, n if n > 0 => n, }`, `match (x) { (-1) => a, n if (n > 0) => n }`},
+		{`match (p) { [a, [b]] => a + b, {"x": x, 1: true} => x }`,
+			`match (p) { [a, [b]] => (a + b), {"x": x, 1: true} => x }`},
+		{`let y = match (f(1)) { n => n * 2 };`, `let y = match (f(1)) { n => (n * 2) };`},
+	}
+	for _, test := range tests {
+		program := testParseProgram(t, test.input, []string{})
+		if program.String() != test.expected {
+			t.Errorf("Wrong program Expected=%s Got=%s", test.expected, program.String())
+		}
+	}
+	program := testParseProgram(t, `match (x) { a if a => 1 }`, []string{})
+	matchExpr, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.MatchExpression Got=%T", program.Statements[0])
+	}
+	testIdentifier(t, matchExpr.Subject, "x")
+	if len(matchExpr.Arms) != 1 {
+		t.Fatalf("Wrong number of arms Expected=1 Got=%d", len(matchExpr.Arms))
+	}
+	testIdentifier(t, matchExpr.Arms[0].Pattern, "a")
+	testIdentifier(t, matchExpr.Arms[0].Guard, "a")
+	testParseProgram(t, `match (x) { f(x) => 1 }`, []string{"Invalid pattern: f(x)",
+		"No prefixParseFn for TokenType==> found", "No prefixParseFn for TokenType=} found"})
+	testParseProgram(t, `match (x) { {y: 1} => 1 }`, []string{"Invalid hash pattern key: y",
+		"No prefixParseFn for TokenType==> found", "No prefixParseFn for TokenType=} found"})
+	testParseProgram(t, `match (x) { 1 2 }`, []string{"peekToken: Expected type==> Got=INT",
+		"No prefixParseFn for TokenType=} found"})
+}
+
+func TestMacroLiteralParsing(t *testing.T) {
+	program := testParseProgram(t, `macro(x, y) { x + y; }`, []string{})
+	testNumberOfStatemets(t, program, 1)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.MacroLiteral Got=%T", stmt.Expression)
+	}
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("Wrong number of parameters Expected=2 Got=%d", len(macro.Parameters))
+	}
+	testIdentifier(t, macro.Parameters[0], "x")
+	testIdentifier(t, macro.Parameters[1], "y")
+	if macro.String() != "macro(x, y) (x + y)" {
+		t.Errorf("Wrong macro Got=%s", macro.String())
+	}
+	testParseProgram(t, "macro x", []string{"peekToken: Expected type=( Got=IDENT"})
+}
+
+func TestIndexExpressionParsing(t *testing.T) {
+	program := testParseProgram(t, "myArray[1 + 1]", []string{})
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	indexExpr, ok := stmt.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.IndexExpression Got=%T", stmt.Expression)
+	}
+	testIdentifier(t, indexExpr.Left, "myArray")
+	testInfixExpression(t, indexExpr.Index, 1, "+", 1)
+	testParseProgram(t, "a[1", []string{"peekToken: Expected type=] Got=EOF"})
+}
+
+func TestModuleParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"hello world"`, `"hello world"`},
+		{`let lib = import "lib/math.monkey";`, `let lib = import "lib/math.monkey";`},
+		{`lib.square(2) + 1`, `(lib.square(2) + 1)`},
+		{`a.b.c`, `a.b.c`},
+		{`-lib.x`, `(-lib.x)`},
+		{`export let x = 5;`, `export let x = 5;`},
+		{`export const x = 5;`, `export const x = 5;`},
+	}
+	for _, test := range tests {
+		program := testParseProgram(t, test.input, []string{})
+		if program.String() != test.expected {
+			t.Errorf("Wrong program Expected=%s Got=%s", test.expected, program.String())
+		}
+	}
+	program := testParseProgram(t, `import "a"`, []string{})
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	importExpr, ok := stmt.Expression.(*ast.ImportExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression not an *ast.ImportExpression, Got=%T", stmt.Expression)
+	}
+	path, ok := importExpr.Path.(*ast.StringLiteral)
+	if !ok || path.Value != "a" {
+		t.Fatalf("importExpr.Path wrong, Got=%+v", importExpr.Path)
+	}
+	testParseProgram(t, "lib.5", []string{"peekToken: Expected type=IDENT Got=INT"})
+	testParseProgram(t, "export x", []string{"peekToken: Expected type=LET Got=IDENT"})
+}
+
 func testIntegerLiteral(t *testing.T, exp ast.Expression, value int64) bool {
 	intLiteral, ok := exp.(*ast.IntegerLiteral)
 	if !ok {
@@ -451,13 +740,51 @@ func testIntegerLiteral(t *testing.T, exp ast.Expression, value int64) bool {
 	return true
 }
 
+func TestParseErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1;\nlet = 5;", "2:5"},
+		{"let [a, 1", "1:9"},
+		{"match (x) { 1 2 }", "1:15"},
+	}
+	for i, test := range tests {
+		p := New(lexer.New(test.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Fatalf("%d - Expected parse errors", i)
+		}
+		parseErr, ok := p.Errors()[0].(*ParseError)
+		if !ok {
+			t.Fatalf("%d - Expected *ParseError Got=%T", i, p.Errors()[0])
+		}
+		if parseErr.Position.String() != test.expected {
+			t.Errorf("%d - Wrong position Expected=%s Got=%s", i, test.expected, parseErr.Position)
+		}
+		if parseErr.Error() != test.expected+": "+parseErr.Message {
+			t.Errorf("%d - Expected the error to start with its position Got=%s", i, parseErr.Error())
+		}
+	}
+	p := New(lexer.NewWithFilename("let = 5;", "main.monkey"))
+	p.ParseProgram()
+	expected := "main.monkey:1:5: peekToken: Expected type=IDENT Got=="
+	if len(p.Errors()) == 0 || p.Errors()[0].Error() != expected {
+		t.Errorf("Wrong error Expected=%s Got=%v", expected, p.Errors())
+	}
+}
+
 func checkParserErrors(t *testing.T, parser *Parser, expectedErrors []string) {
 	errors := parser.Errors()
 	if len(errors) == len(expectedErrors) {
 		for i, err := range errors {
-			if err.Error() != expectedErrors[i] {
+			message := err.Error()
+			if parseErr, ok := err.(*ParseError); ok {
+				message = parseErr.Message
+			}
+			if message != expectedErrors[i] {
 				t.Fatalf("%d. - Wrong error. Expected=[ %s ] but Got=[ %s ]",
-					i, expectedErrors[i], err.Error())
+					i, expectedErrors[i], message)
 			}
 		}
 		return
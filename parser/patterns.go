@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// parseArrayPattern Parses an ArrayPattern of comma separated identifiers,
+// optionally ending with a ...rest identifier
+func (parser *Parser) parseArrayPattern() (ast.Pattern, error) {
+	pattern := ast.NewArrayPattern(parser.currentToken)
+	for !parser.peekTokenIs(token.RBRACKET) {
+		if parser.peekTokenIs(token.ELLIPSIS) {
+			parser.nextToken()
+			if err := parser.expectPeek(token.IDENT); err != nil {
+				return nil, err
+			}
+			pattern.Rest = ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal)
+			break
+		}
+		if err := parser.expectPeek(token.IDENT); err != nil {
+			return nil, err
+		}
+		pattern.Elements = append(pattern.Elements,
+			ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal))
+		if !parser.peekTokenIs(token.RBRACKET) {
+			if err := parser.expectPeek(token.COMMA); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := parser.expectPeek(token.RBRACKET); err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// parseHashPattern Parses a HashPattern of comma separated identifiers
+func (parser *Parser) parseHashPattern() (ast.Pattern, error) {
+	pattern := ast.NewHashPattern(parser.currentToken)
+	for !parser.peekTokenIs(token.RBRACE) {
+		if err := parser.expectPeek(token.IDENT); err != nil {
+			return nil, err
+		}
+		pattern.Keys = append(pattern.Keys,
+			ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal))
+		if !parser.peekTokenIs(token.RBRACE) {
+			if err := parser.expectPeek(token.COMMA); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := parser.expectPeek(token.RBRACE); err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// checkPattern Checks that an expression can be used as a pattern, i.e. that it is
+// a literal, an identifier or an array or hash literal of patterns with literal keys
+func checkPattern(pattern ast.Expression) error {
+	switch pattern := pattern.(type) {
+	case nil, *ast.Identifier, *ast.IntegerLiteral, *ast.StringLiteral, *ast.Boolean:
+		return nil
+	case *ast.PrefixExpression:
+		if _, ok := pattern.Right.(*ast.IntegerLiteral); ok && pattern.Operator == "-" {
+			return nil
+		}
+	case *ast.ArrayLiteral:
+		for _, element := range pattern.Elements {
+			if err := checkPattern(element); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.HashLiteral:
+		for _, pair := range pattern.Pairs {
+			if !isLiteralKey(pair.Key) {
+				return newParseError(pair.Key.Pos(), "Invalid hash pattern key: %s", pair.Key)
+			}
+			if err := checkPattern(pair.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return newParseError(pattern.Pos(), "Invalid pattern: %s", pattern)
+}
+
+// isLiteralKey Checks if an expression is a literal usable as hash key in a pattern
+func isLiteralKey(key ast.Expression) bool {
+	switch key.(type) {
+	case *ast.IntegerLiteral, *ast.StringLiteral, *ast.Boolean:
+		return true
+	default:
+		return false
+	}
+}
@@ -13,4 +13,6 @@ var precedences = map[token.TokenType]int{
 	token.DIVIDE:   PRODUCT,
 	token.MULTIPLY: PRODUCT,
 	token.LPAREN:   CALL,
+	token.DOT:      INDEX,
+	token.LBRACKET: INDEX,
 }
@@ -8,33 +8,89 @@ import (
 // parseStatement Parsers a statement from input
 func (parser *Parser) parseStatement() (ast.Statement, error) {
 	switch parser.currentToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return parser.parseLetStatement()
 	case token.RETRUN:
 		return parser.parseReturnStatement()
+	case token.EXPORT:
+		return parser.parseExportStatement()
+	case token.THROW:
+		return parser.parseThrowStatement()
+	case token.FUNCTION:
+		if parser.peekTokenIs(token.IDENT) {
+			return parser.parseFunctionStatement()
+		}
+		return parser.parseExpressionStatement()
 	default:
 		return parser.parseExpressionStatement()
 	}
 }
 
-// parseLetStatement Parses a LetStatement
+// parseLetStatement Parses a LetStatement declared with let or const
 func (parser *Parser) parseLetStatement() (*ast.LetStatement, error) {
 	stmt := ast.NewLetStatement(parser.currentToken)
-	if err := parser.expectPeek(token.IDENT); err != nil {
+	var err error
+	switch {
+	case parser.peekTokenIs(token.LBRACKET):
+		parser.nextToken()
+		stmt.Pattern, err = parser.parseArrayPattern()
+	case parser.peekTokenIs(token.LBRACE):
+		parser.nextToken()
+		stmt.Pattern, err = parser.parseHashPattern()
+	default:
+		err = parser.expectPeek(token.IDENT)
+		stmt.Name = ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal)
+	}
+	if err != nil {
 		return nil, err
 	}
-	stmt.Name = ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal)
 	if err := parser.expectPeek(token.ASSIGN); err != nil {
 		return nil, err
 	}
 	parser.nextToken()
 	stmt.Value = parser.parseExpression(LOWEST)
+	if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil {
+		fn.Name = stmt.Name.Value
+	}
+	for parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+	}
+	return stmt, nil
+}
+
+// parseFunctionStatement Parses a FunctionStatement declaring a named function
+func (parser *Parser) parseFunctionStatement() (*ast.FunctionStatement, error) {
+	stmt := ast.NewFunctionStatement(parser.currentToken)
+	fn := ast.NewFunctionLiteral(parser.currentToken)
+	parser.nextToken()
+	stmt.Name = ast.NewIdentifier(parser.currentToken, parser.currentToken.Literal)
+	fn.Name = stmt.Name.Value
+	if err := parser.parseFunctionParametersAndBody(fn); err != nil {
+		return nil, err
+	}
+	stmt.Function = fn
 	for parser.peekTokenIs(token.SEMICOLON) {
 		parser.nextToken()
 	}
 	return stmt, nil
 }
 
+// parseExportStatement Parses an ExportStatement
+func (parser *Parser) parseExportStatement() (*ast.ExportStatement, error) {
+	stmt := ast.NewExportStatement(parser.currentToken)
+	if parser.peekTokenIs(token.CONST) {
+		parser.nextToken()
+	} else if err := parser.expectPeek(token.LET); err != nil {
+		return nil, err
+	}
+	letStmt, err := parser.parseLetStatement()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Statement = letStmt
+	return stmt, nil
+}
+
 // parseReturnStatement Parses a ReturnStatement
 func (parser *Parser) parseReturnStatement() (*ast.ReturnStatement, error) {
 	stmt := ast.NewReturnStatement(parser.currentToken)
@@ -46,6 +102,17 @@ func (parser *Parser) parseReturnStatement() (*ast.ReturnStatement, error) {
 	return stmt, nil
 }
 
+// parseThrowStatement Parses a ThrowStatement
+func (parser *Parser) parseThrowStatement() (*ast.ThrowStatement, error) {
+	stmt := ast.NewThrowStatement(parser.currentToken)
+	parser.nextToken()
+	stmt.Value = parser.parseExpression(LOWEST)
+	for parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+	}
+	return stmt, nil
+}
+
 // parseExpressionStatement Parses an ExpressionStatement
 func (parser *Parser) parseExpressionStatement() (*ast.ExpressionStatement, error) {
 	stmt := ast.NewExpressionStatement(parser.currentToken)
@@ -60,7 +127,7 @@ func (parser *Parser) parseExpressionStatement() (*ast.ExpressionStatement, erro
 func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := ast.NewBlockStatement(parser.currentToken)
 	parser.nextToken()
-	if !parser.currentTokenIs(token.RBRACE) && !parser.currentTokenIs(token.EOF) {
+	for !parser.currentTokenIs(token.RBRACE) && !parser.currentTokenIs(token.EOF) {
 		stmt, err := parser.parseStatement()
 		if err != nil {
 			parser.AddError(err)
@@ -0,0 +1,189 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strconv"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+// Field numbers of the messages of the pprof profile.proto format
+const (
+	PROFILE_SAMPLE_TYPE    = 1
+	PROFILE_SAMPLE         = 2
+	PROFILE_LOCATION       = 4
+	PROFILE_FUNCTION       = 5
+	PROFILE_STRING_TABLE   = 6
+	PROFILE_TIME_NANOS     = 9
+	PROFILE_DURATION_NANOS = 10
+	PROFILE_PERIOD_TYPE    = 11
+	PROFILE_PERIOD         = 12
+	VALUE_TYPE_TYPE        = 1
+	VALUE_TYPE_UNIT        = 2
+	SAMPLE_LOCATION_ID     = 1
+	SAMPLE_VALUE           = 2
+	LOCATION_ID            = 1
+	LOCATION_LINE          = 4
+	LINE_FUNCTION_ID       = 1
+	LINE_LINE              = 2
+	FUNCTION_ID            = 1
+	FUNCTION_NAME          = 2
+	FUNCTION_SYSTEM_NAME   = 3
+	FUNCTION_FILENAME      = 4
+	FUNCTION_START_LINE    = 5
+)
+
+// Protocol buffer wire types
+const (
+	WIRE_VARINT = 0
+	WIRE_BYTES  = 2
+)
+
+// WritePprof Writes the profile in the gzipped protocol buffer format read
+// by pprof. Each function is a location and each call stack a sample with
+// the calls, nodes evaluated and nanoseconds spent in its innermost function
+func (profiler *Profiler) WritePprof(w io.Writer) error {
+	strings := newStringTable()
+	var profile protobuf
+	for _, valueType := range [][2]string{{"calls", "count"}, {"nodes", "count"}, {"time", "nanoseconds"}} {
+		profile.message(PROFILE_SAMPLE_TYPE, encodeValueType(strings, valueType[0], valueType[1]))
+	}
+	for _, sample := range profiler.Samples() {
+		var msg protobuf
+		locations := make([]uint64, 0, len(sample.Stack))
+		for _, function := range sample.Stack {
+			locations = append(locations, uint64(function.id))
+		}
+		msg.packedVarints(SAMPLE_LOCATION_ID, locations)
+		msg.packedVarints(SAMPLE_VALUE, []uint64{uint64(sample.Calls), uint64(sample.Nodes), uint64(sample.Time)})
+		profile.message(PROFILE_SAMPLE, &msg)
+	}
+	for _, function := range profiler.profiles {
+		var line, location protobuf
+		line.varintField(LINE_FUNCTION_ID, uint64(function.id))
+		line.varintField(LINE_LINE, uint64(function.Position.Line))
+		location.varintField(LOCATION_ID, uint64(function.id))
+		location.message(LOCATION_LINE, &line)
+		profile.message(PROFILE_LOCATION, &location)
+	}
+	for _, function := range profiler.profiles {
+		var msg protobuf
+		msg.varintField(FUNCTION_ID, uint64(function.id))
+		name := pprofName(function)
+		msg.varintField(FUNCTION_NAME, strings.index(name))
+		msg.varintField(FUNCTION_SYSTEM_NAME, strings.index(name))
+		msg.varintField(FUNCTION_FILENAME, strings.index(function.Position.Filename))
+		msg.varintField(FUNCTION_START_LINE, uint64(function.Position.Line))
+		profile.message(PROFILE_FUNCTION, &msg)
+	}
+	periodType := encodeValueType(strings, "time", "nanoseconds")
+	for _, str := range strings.strings {
+		profile.bytesField(PROFILE_STRING_TABLE, []byte(str))
+	}
+	profile.varintField(PROFILE_TIME_NANOS, uint64(profiler.start.UnixNano()))
+	profile.varintField(PROFILE_DURATION_NANOS, uint64(profiler.duration))
+	profile.message(PROFILE_PERIOD_TYPE, periodType)
+	profile.varintField(PROFILE_PERIOD, 1)
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(profile.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// pprofName Returns the name of a function shown by pprof. The angle brackets
+// around names like <module> are removed since pprof strips them as C++ template
+// arguments, anonymous functions are told apart by the line defining them
+func pprofName(function *FunctionProfile) string {
+	switch function.Name {
+	case object.MODULE_FRAME:
+		return "module"
+	case object.ANONYMOUS_FRAME:
+		return "anonymous:" + strconv.Itoa(function.Position.Line)
+	}
+	return function.Name
+}
+
+// encodeValueType Encodes a ValueType message describing a sample value
+func encodeValueType(strings *stringTable, valueType, unit string) *protobuf {
+	var msg protobuf
+	msg.varintField(VALUE_TYPE_TYPE, strings.index(valueType))
+	msg.varintField(VALUE_TYPE_UNIT, strings.index(unit))
+	return &msg
+}
+
+// stringTable Strings referenced by index in a profile, the first one is always empty
+type stringTable struct {
+	strings []string
+	indexes map[string]uint64
+}
+
+func newStringTable() *stringTable {
+	return &stringTable{
+		strings: []string{""},
+		indexes: map[string]uint64{"": 0},
+	}
+}
+
+// index Returns the index of a string, adding it to the table if missing
+func (table *stringTable) index(str string) uint64 {
+	if i, ok := table.indexes[str]; ok {
+		return i
+	}
+	i := uint64(len(table.strings))
+	table.strings = append(table.strings, str)
+	table.indexes[str] = i
+	return i
+}
+
+// protobuf Buffer encoding protocol buffer fields, fields with default values are left out
+type protobuf struct {
+	bytes.Buffer
+}
+
+// varint Writes an unsigned integer in the base 128 varint encoding
+func (buf *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		buf.WriteByte(byte(x) | 0x80)
+		x >>= 7
+	}
+	buf.WriteByte(byte(x))
+}
+
+// tag Writes the key of a field
+func (buf *protobuf) tag(field, wireType int) {
+	buf.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+// varintField Writes an integer field unless it is 0
+func (buf *protobuf) varintField(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	buf.tag(field, WIRE_VARINT)
+	buf.varint(x)
+}
+
+// bytesField Writes a length delimited field, repeated strings are written even if empty
+func (buf *protobuf) bytesField(field int, data []byte) {
+	buf.tag(field, WIRE_BYTES)
+	buf.varint(uint64(len(data)))
+	buf.Write(data)
+}
+
+// packedVarints Writes a repeated integer field in the packed encoding
+func (buf *protobuf) packedVarints(field int, xs []uint64) {
+	var packed protobuf
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	buf.bytesField(field, packed.Bytes())
+}
+
+// message Writes an embedded message field
+func (buf *protobuf) message(field int, msg *protobuf) {
+	buf.bytesField(field, msg.Bytes())
+}
@@ -0,0 +1,245 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// FunctionProfile Measurements of the calls of a function
+type FunctionProfile struct {
+	Name      string
+	Position  token.Position // position of the definition, not valid for builtins
+	Calls     int
+	Inclusive time.Duration // time spent in the function and the functions it called
+	Exclusive time.Duration // time spent in the function itself
+	Nodes     int           // nodes evaluated in the function itself
+	id        int           // id of the function in pprof profiles, starting at 1
+	active    int           // calls in progress, inclusive time is only counted for the outermost
+}
+
+// Sample Measurements of the time spent with a call stack
+type Sample struct {
+	Stack []*FunctionProfile // innermost first
+	Calls int                // calls made to the innermost function from the rest of the stack
+	Time  time.Duration      // time spent in the innermost function
+	Nodes int                // nodes evaluated in the innermost function
+}
+
+// functionKey Identifies a function by its name and the position of its definition
+type functionKey struct {
+	name     string
+	position token.Position
+}
+
+// frame A call in progress
+type frame struct {
+	profile *FunctionProfile
+	sample  *Sample
+	key     string // ids of the functions in the call stack
+	start   time.Time
+}
+
+// Profiler evaluator.Hook measuring the calls, time spent and nodes evaluated
+// in each function of a program. Top level code is measured as a function
+// named object.MODULE_FRAME
+type Profiler struct {
+	functions map[functionKey]*FunctionProfile
+	profiles  []*FunctionProfile // in order of the first call
+	samples   map[string]*Sample
+	stacks    []string // keys of the samples in order of creation
+	frames    []*frame
+	start     time.Time
+	last      time.Time // when time was last charged to the innermost frame
+	duration  time.Duration
+	clock     func() time.Time
+}
+
+// New Creates a Profiler for a program read from filename
+func New(filename string) *Profiler {
+	profiler := &Profiler{
+		functions: make(map[functionKey]*FunctionProfile),
+		profiles:  make([]*FunctionProfile, 0),
+		samples:   make(map[string]*Sample),
+		stacks:    make([]string, 0),
+		clock:     time.Now,
+	}
+	module := profiler.profile(functionKey{
+		name:     object.MODULE_FRAME,
+		position: token.Position{Filename: filename, Line: 1, Column: 1},
+	})
+	module.Calls = 1
+	profiler.frames = []*frame{{profile: module, sample: profiler.sample("", module), key: ""}}
+	return profiler
+}
+
+// Run Evaluates a program in an environment while profiling it
+func (profiler *Profiler) Run(program ast.Node, env *object.Environment) object.Object {
+	defer evaluator.SetHook(evaluator.SetHook(profiler))
+	profiler.Start()
+	defer profiler.Stop()
+	return evaluator.Eval(program, env)
+}
+
+// Start Starts measuring the time spent in top level code
+func (profiler *Profiler) Start() {
+	profiler.start = profiler.clock()
+	profiler.last = profiler.start
+	profiler.frames[0].start = profiler.start
+}
+
+// Stop Stops measuring time, the time since Start is the duration of the profile
+func (profiler *Profiler) Stop() {
+	now := profiler.clock()
+	profiler.charge(now)
+	profiler.duration = now.Sub(profiler.start)
+	profiler.frames[0].profile.Inclusive = profiler.duration
+}
+
+// Duration Returns the time between Start and Stop
+func (profiler *Profiler) Duration() time.Duration {
+	return profiler.duration
+}
+
+// Functions Returns the profiles of the functions called, the ones
+// with the most exclusive time first
+func (profiler *Profiler) Functions() []*FunctionProfile {
+	profiles := make([]*FunctionProfile, len(profiler.profiles))
+	copy(profiles, profiler.profiles)
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Exclusive > profiles[j].Exclusive
+	})
+	return profiles
+}
+
+// Samples Returns the measurements of each call stack seen, in the order they were first seen
+func (profiler *Profiler) Samples() []*Sample {
+	samples := make([]*Sample, 0, len(profiler.stacks))
+	for _, key := range profiler.stacks {
+		samples = append(samples, profiler.samples[key])
+	}
+	return samples
+}
+
+// BeforeStatement Does nothing, statements are counted as nodes
+func (profiler *Profiler) BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	return nil
+}
+
+// BeforeNode Counts a node evaluated in the innermost function
+func (profiler *Profiler) BeforeNode(node ast.Node) {
+	top := profiler.frames[len(profiler.frames)-1]
+	top.profile.Nodes++
+	top.sample.Nodes++
+}
+
+// BeforeCall Charges the time spent so far to the caller and starts measuring the call
+func (profiler *Profiler) BeforeCall(fn object.Object, args []object.Object, pos token.Position) {
+	now := profiler.clock()
+	profiler.charge(now)
+	key := functionKey{name: evaluator.FunctionName(fn)}
+	if function, ok := fn.(*object.Function); ok {
+		key.position = function.Position
+	}
+	profile := profiler.profile(key)
+	profile.Calls++
+	profile.active++
+	top := profiler.frames[len(profiler.frames)-1]
+	stackKey := top.key + "/" + strconv.Itoa(profile.id)
+	sample := profiler.sample(stackKey, profile)
+	sample.Calls++
+	profiler.frames = append(profiler.frames, &frame{profile: profile, sample: sample, key: stackKey, start: now})
+}
+
+// AfterCall Charges the time spent so far to the called function and ends the call
+func (profiler *Profiler) AfterCall(fn object.Object, result object.Object) {
+	now := profiler.clock()
+	profiler.charge(now)
+	call := profiler.frames[len(profiler.frames)-1]
+	profiler.frames = profiler.frames[:len(profiler.frames)-1]
+	call.profile.active--
+	if call.profile.active == 0 {
+		call.profile.Inclusive += now.Sub(call.start)
+	}
+}
+
+// charge Adds the time since the last charge to the innermost frame
+func (profiler *Profiler) charge(now time.Time) {
+	elapsed := now.Sub(profiler.last)
+	profiler.last = now
+	top := profiler.frames[len(profiler.frames)-1]
+	top.profile.Exclusive += elapsed
+	top.sample.Time += elapsed
+}
+
+// profile Returns the profile of a function, creating it when first called
+func (profiler *Profiler) profile(key functionKey) *FunctionProfile {
+	if profile, ok := profiler.functions[key]; ok {
+		return profile
+	}
+	profile := &FunctionProfile{Name: key.name, Position: key.position, id: len(profiler.profiles) + 1}
+	profiler.functions[key] = profile
+	profiler.profiles = append(profiler.profiles, profile)
+	return profile
+}
+
+// sample Returns the sample of a call stack, creating it when first seen.
+// The stack is made of the innermost function and the stack of the caller
+func (profiler *Profiler) sample(key string, profile *FunctionProfile) *Sample {
+	if sample, ok := profiler.samples[key]; ok {
+		return sample
+	}
+	stack := []*FunctionProfile{profile}
+	if len(profiler.frames) > 0 {
+		stack = append(stack, profiler.frames[len(profiler.frames)-1].sample.Stack...)
+	}
+	sample := &Sample{Stack: stack}
+	profiler.samples[key] = sample
+	profiler.stacks = append(profiler.stacks, key)
+	return sample
+}
+
+// WriteSummary Writes a table of the functions with the most exclusive time, at most limit
+// functions are listed if limit is positive
+func (profiler *Profiler) WriteSummary(w io.Writer, limit int) {
+	functions := profiler.Functions()
+	nodes := 0
+	for _, profile := range functions {
+		nodes += profile.Nodes
+	}
+	fmt.Fprintf(w, "Total: %s, %d nodes evaluated\n", formatDuration(profiler.duration), nodes)
+	fmt.Fprintf(w, "%10s %6s %10s %6s %8s %8s  %s\n", "flat", "flat%", "cum", "cum%", "calls", "nodes", "function")
+	if limit > 0 && len(functions) > limit {
+		functions = functions[:limit]
+	}
+	for _, profile := range functions {
+		location := object.NATIVE_FILENAME
+		if profile.Position.IsValid() {
+			location = profile.Position.String()
+		}
+		fmt.Fprintf(w, "%10s %6s %10s %6s %8d %8d  %s %s\n",
+			formatDuration(profile.Exclusive), profiler.percentage(profile.Exclusive),
+			formatDuration(profile.Inclusive), profiler.percentage(profile.Inclusive),
+			profile.Calls, profile.Nodes, profile.Name, location)
+	}
+}
+
+// percentage Returns a duration as a percentage of the duration of the profile
+func (profiler *Profiler) percentage(d time.Duration) string {
+	if profiler.duration <= 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(d)/float64(profiler.duration))
+}
+
+// formatDuration Formats a duration in milliseconds
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,189 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+const testSource = `let double = fn(x) { x * 2 };
+let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } };
+double(1);
+fact(3);`
+
+// profileTestSource Profiles the test source with a clock advancing a millisecond each time it is read
+func profileTestSource(t *testing.T) *Profiler {
+	p := parser.New(lexer.New(testSource))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors: %v", p.Errors())
+	}
+	profiler := New("test.monkey")
+	now := time.Unix(0, 0)
+	profiler.clock = func() time.Time {
+		current := now
+		now = now.Add(time.Millisecond)
+		return current
+	}
+	if result := profiler.Run(program, object.NewEnvironment()); result.Inspect() != "6" {
+		t.Fatalf("Wrong result Expected=6 Got=%s", result.Inspect())
+	}
+	return profiler
+}
+
+func TestProfiler(t *testing.T) {
+	profiler := profileTestSource(t)
+	if profiler.Duration() != 9*time.Millisecond {
+		t.Errorf("Wrong duration Expected=9ms Got=%s", profiler.Duration())
+	}
+	expected := []struct {
+		name                 string
+		position             string
+		calls                int
+		inclusive, exclusive time.Duration
+	}{
+		{"fact", "2:12", 3, 5 * time.Millisecond, 5 * time.Millisecond},
+		{"<module>", "test.monkey:1:1", 1, 9 * time.Millisecond, 3 * time.Millisecond},
+		{"double", "1:14", 1, time.Millisecond, time.Millisecond},
+	}
+	functions := profiler.Functions()
+	if len(functions) != len(expected) {
+		t.Fatalf("Wrong number of functions Expected=%d Got=%d", len(expected), len(functions))
+	}
+	for i, test := range expected {
+		profile := functions[i]
+		if profile.Name != test.name || profile.Position.String() != test.position || profile.Calls != test.calls ||
+			profile.Inclusive != test.inclusive || profile.Exclusive != test.exclusive {
+			t.Errorf("%d - Wrong profile Expected=%+v Got=%+v", i, test, profile)
+		}
+	}
+	if functions[2].Nodes != 5 {
+		t.Errorf("Wrong number of nodes evaluated in double Expected=5 Got=%d", functions[2].Nodes)
+	}
+
+	expectedSamples := []string{
+		"<module> calls=0",
+		"double,<module> calls=1",
+		"fact,<module> calls=1",
+		"fact,fact,<module> calls=1",
+		"fact,fact,fact,<module> calls=1",
+	}
+	samples := profiler.Samples()
+	for i, sample := range samples {
+		names := make([]string, 0, len(sample.Stack))
+		for _, function := range sample.Stack {
+			names = append(names, function.Name)
+		}
+		description := fmt.Sprintf("%s calls=%d", strings.Join(names, ","), sample.Calls)
+		if i >= len(expectedSamples) || description != expectedSamples[i] {
+			t.Errorf("%d - Wrong sample Got=%s", i, description)
+		}
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	profiler := profileTestSource(t)
+	nodes := 0
+	for _, profile := range profiler.Functions() {
+		nodes += profile.Nodes
+	}
+	var out bytes.Buffer
+	profiler.WriteSummary(&out, 2)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a total, a header and 2 functions Got=%q", out.String())
+	}
+	if lines[0] != fmt.Sprintf("Total: 9.00ms, %d nodes evaluated", nodes) {
+		t.Errorf("Wrong total Got=%q", lines[0])
+	}
+	fields := strings.Fields(lines[2])
+	expected := []string{"5.00ms", "55.6%", "5.00ms", "55.6%", "3", fields[5], "fact", "2:12"}
+	if strings.Join(fields, " ") != strings.Join(expected, " ") {
+		t.Errorf("Wrong row Expected=%v Got=%v", expected, fields)
+	}
+	if !strings.HasSuffix(lines[3], "<module> test.monkey:1:1") {
+		t.Errorf("Wrong row Got=%q", lines[3])
+	}
+}
+
+func TestWritePprof(t *testing.T) {
+	profiler := profileTestSource(t)
+	var out bytes.Buffer
+	if err := profiler.WritePprof(&out); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	gz, err := gzip.NewReader(&out)
+	if err != nil {
+		t.Fatalf("Profile is not gzipped: %s", err)
+	}
+	data, _ := io.ReadAll(gz)
+	fields := decodeFields(t, data)
+	counts := make(map[uint64]int)
+	for _, field := range fields {
+		counts[field.number]++
+	}
+	if counts[PROFILE_SAMPLE_TYPE] != 3 || counts[PROFILE_SAMPLE] != 5 ||
+		counts[PROFILE_LOCATION] != 3 || counts[PROFILE_FUNCTION] != 3 {
+		t.Errorf("Wrong number of fields Got=%v", counts)
+	}
+	strs := make([]string, 0)
+	for _, field := range fields {
+		if field.number == PROFILE_STRING_TABLE {
+			strs = append(strs, string(field.data))
+		}
+	}
+	table := strings.Join(strs, ",") + ","
+	if !strings.HasPrefix(table, ",calls,count,nodes,time,nanoseconds,") ||
+		!strings.Contains(table, ",module,") || !strings.Contains(table, ",fact,") {
+		t.Errorf("Wrong string table Got=%q", table)
+	}
+}
+
+// protoField Field decoded from a protocol buffer message, data holds length delimited values
+type protoField struct {
+	number uint64
+	value  uint64
+	data   []byte
+}
+
+// decodeFields Decodes the top level fields of a protocol buffer message
+func decodeFields(t *testing.T, data []byte) []protoField {
+	fields := make([]protoField, 0)
+	for len(data) > 0 {
+		key, n := decodeVarint(data)
+		data = data[n:]
+		field := protoField{number: key >> 3}
+		switch key & 7 {
+		case WIRE_VARINT:
+			field.value, n = decodeVarint(data)
+			data = data[n:]
+		case WIRE_BYTES:
+			length, n := decodeVarint(data)
+			field.data = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			t.Fatalf("Unexpected wire type %d", key&7)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func decodeVarint(data []byte) (uint64, int) {
+	var x uint64
+	for i, b := range data {
+		x |= uint64(b&0x7f) << (7 * uint(i))
+		if b < 0x80 {
+			return x, i + 1
+		}
+	}
+	return x, len(data)
+}
@@ -0,0 +1,45 @@
+package repl
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// completer Returns the possible completions of a word prefix
+type completer func(prefix string) []string
+
+// newEnvCompleter Creates a completer suggesting keywords, the supplied
+// global names and the names currently bound in the environment
+func newEnvCompleter(env *object.Environment, globals []string) completer {
+	return func(prefix string) []string {
+		candidates := make([]string, 0)
+		seen := make(map[string]bool)
+		words := append(token.Keywords(), globals...)
+		words = append(words, env.Names()...)
+		for _, word := range words {
+			if strings.HasPrefix(word, prefix) && !seen[word] {
+				seen[word] = true
+				candidates = append(candidates, word)
+			}
+		}
+		sort.Strings(candidates)
+		return candidates
+	}
+}
+
+// commonPrefix Returns the longest prefix shared by all the supplied words
+func commonPrefix(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
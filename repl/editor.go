@@ -0,0 +1,272 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CzarSimon/monkey/lexer"
+)
+
+// errInterrupted Returned when the user cancels the current input with ctrl-c
+var errInterrupted = errors.New("Interrupted")
+
+// Control characters handled by the editor
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyLineFeed  = 10
+	keyCtrlK     = 11
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// lineReader Source of input lines for the repl
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+	Close() error
+}
+
+// editor lineReader with emacs style line editing, history and tab completion
+type editor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	history  *History
+	complete completer
+	term     *terminal // set when the editor controls the terminal mode of in
+
+	buffer       []rune
+	cursor       int
+	prompt       string
+	historyIndex int
+	pending      string // input being edited before browsing the history
+}
+
+// newEditor Creates an editor reading keys from in and rendering to out
+func newEditor(in io.Reader, out io.Writer, history *History, complete completer) *editor {
+	return &editor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		history:  history,
+		complete: complete,
+	}
+}
+
+// ReadLine Reads a line of input, switching the terminal to raw mode while editing
+func (e *editor) ReadLine(prompt string) (string, error) {
+	if e.term != nil {
+		if err := e.term.makeRaw(); err == nil {
+			defer e.term.restore()
+		}
+	}
+	e.buffer = make([]rune, 0)
+	e.cursor = 0
+	e.prompt = prompt
+	e.historyIndex = e.history.Len()
+	e.pending = ""
+	e.refresh()
+	for {
+		char, _, err := e.in.ReadRune()
+		if err != nil {
+			io.WriteString(e.out, "\r\n")
+			return "", err
+		}
+		done, err := e.handleKey(char)
+		if err != nil {
+			io.WriteString(e.out, "\r\n")
+			return "", err
+		}
+		if done {
+			io.WriteString(e.out, "\r\n")
+			line := string(e.buffer)
+			e.history.Add(line)
+			return line, nil
+		}
+	}
+}
+
+// Close Persists the history
+func (e *editor) Close() error {
+	return e.history.Save()
+}
+
+// handleKey Applies a key press to the buffer, returns true when the line is submitted
+func (e *editor) handleKey(char rune) (bool, error) {
+	switch char {
+	case keyEnter, keyLineFeed:
+		return true, nil
+	case keyCtrlC:
+		return false, errInterrupted
+	case keyCtrlD:
+		if len(e.buffer) == 0 {
+			return false, io.EOF
+		}
+		e.deleteAt(e.cursor)
+	case keyBackspace, keyDelete:
+		if e.cursor > 0 {
+			e.cursor--
+			e.deleteAt(e.cursor)
+		}
+	case keyCtrlA:
+		e.cursor = 0
+	case keyCtrlE:
+		e.cursor = len(e.buffer)
+	case keyCtrlB:
+		e.moveCursor(-1)
+	case keyCtrlF:
+		e.moveCursor(1)
+	case keyCtrlK:
+		e.buffer = e.buffer[:e.cursor]
+	case keyCtrlU:
+		e.buffer = e.buffer[e.cursor:]
+		e.cursor = 0
+	case keyCtrlP:
+		e.browseHistory(-1)
+	case keyCtrlN:
+		e.browseHistory(1)
+	case keyTab:
+		e.completeWord()
+	case keyEscape:
+		e.handleEscapeSequence()
+	default:
+		if char >= ' ' {
+			e.insert(char)
+		}
+	}
+	e.refresh()
+	return false, nil
+}
+
+// handleEscapeSequence Handles the arrow, home, end and delete keys
+func (e *editor) handleEscapeSequence() {
+	next, _, err := e.in.ReadRune()
+	if err != nil || (next != '[' && next != 'O') {
+		return
+	}
+	key, _, err := e.in.ReadRune()
+	if err != nil {
+		return
+	}
+	switch key {
+	case 'A':
+		e.browseHistory(-1)
+	case 'B':
+		e.browseHistory(1)
+	case 'C':
+		e.moveCursor(1)
+	case 'D':
+		e.moveCursor(-1)
+	case 'H':
+		e.cursor = 0
+	case 'F':
+		e.cursor = len(e.buffer)
+	case '1', '3', '4', '7', '8':
+		if tilde, _, err := e.in.ReadRune(); err != nil || tilde != '~' {
+			return
+		}
+		switch key {
+		case '1', '7':
+			e.cursor = 0
+		case '4', '8':
+			e.cursor = len(e.buffer)
+		case '3':
+			e.deleteAt(e.cursor)
+		}
+	}
+}
+
+// insert Inserts a character at the cursor
+func (e *editor) insert(char rune) {
+	e.buffer = append(e.buffer, 0)
+	copy(e.buffer[e.cursor+1:], e.buffer[e.cursor:])
+	e.buffer[e.cursor] = char
+	e.cursor++
+}
+
+// deleteAt Removes the character at a position in the buffer
+func (e *editor) deleteAt(pos int) {
+	if pos < 0 || pos >= len(e.buffer) {
+		return
+	}
+	e.buffer = append(e.buffer[:pos], e.buffer[pos+1:]...)
+}
+
+// moveCursor Moves the cursor a number of characters within the buffer
+func (e *editor) moveCursor(delta int) {
+	e.cursor += delta
+	if e.cursor < 0 {
+		e.cursor = 0
+	}
+	if e.cursor > len(e.buffer) {
+		e.cursor = len(e.buffer)
+	}
+}
+
+// browseHistory Replaces the buffer with an older (-1) or newer (1) history entry
+func (e *editor) browseHistory(direction int) {
+	index := e.historyIndex + direction
+	if index < 0 || index > e.history.Len() {
+		return
+	}
+	if e.historyIndex == e.history.Len() {
+		e.pending = string(e.buffer)
+	}
+	e.historyIndex = index
+	if index == e.history.Len() {
+		e.buffer = []rune(e.pending)
+	} else {
+		e.buffer = []rune(e.history.Entry(index))
+	}
+	e.cursor = len(e.buffer)
+}
+
+// completeWord Completes the word before the cursor. If the candidates do not
+// share a longer prefix than the word they are listed below the input
+func (e *editor) completeWord() {
+	start := e.cursor
+	for start > 0 && lexer.IsIdentifierChar(e.buffer[start-1]) {
+		start--
+	}
+	prefix := string(e.buffer[start:e.cursor])
+	candidates := e.complete(prefix)
+	switch len(candidates) {
+	case 0:
+		io.WriteString(e.out, "\a")
+		return
+	case 1:
+		e.insertString(strings.TrimPrefix(candidates[0], prefix))
+		return
+	}
+	if common := commonPrefix(candidates); len(common) > len(prefix) {
+		e.insertString(strings.TrimPrefix(common, prefix))
+		return
+	}
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+}
+
+// insertString Inserts a string at the cursor
+func (e *editor) insertString(str string) {
+	for _, char := range str {
+		e.insert(char)
+	}
+}
+
+// refresh Redraws the prompt and buffer and positions the cursor
+func (e *editor) refresh() {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(e.buffer))
+	if back := len(e.buffer) - e.cursor; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
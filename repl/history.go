@@ -0,0 +1,96 @@
+package repl
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HISTORY_FILE Name of the file in the home directory where history is kept,
+// can be overridden with the MONKEY_HISTORY environment variable
+const HISTORY_FILE = ".monkey_history"
+
+// MAX_HISTORY Maximum number of entries kept in the history
+const MAX_HISTORY = 1000
+
+// History Previously entered lines, optionally persisted to a file
+type History struct {
+	entries []string
+	path    string
+}
+
+// NewHistory Creates a history persisted at the supplied path and loads
+// any entries already stored there. An empty path disables persistence
+func NewHistory(path string) *History {
+	history := &History{
+		entries: make([]string, 0),
+		path:    path,
+	}
+	history.load()
+	return history
+}
+
+// DefaultHistoryPath Returns the path of the history file of the current user
+func DefaultHistoryPath() string {
+	if path := os.Getenv("MONKEY_HISTORY"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, HISTORY_FILE)
+}
+
+// Add Appends a line to the history, skipping blank lines and direct repetitions
+func (history *History) Add(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(history.entries); n > 0 && history.entries[n-1] == line {
+		return
+	}
+	history.entries = append(history.entries, line)
+	if len(history.entries) > MAX_HISTORY {
+		history.entries = history.entries[len(history.entries)-MAX_HISTORY:]
+	}
+}
+
+// Len Returns the number of entries in the history
+func (history *History) Len() int {
+	return len(history.entries)
+}
+
+// Entry Returns the entry at index i, where 0 is the oldest entry
+func (history *History) Entry(i int) string {
+	return history.entries[i]
+}
+
+// Save Writes the history to its file
+func (history *History) Save() error {
+	if history.path == "" {
+		return nil
+	}
+	content := strings.Join(history.entries, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return os.WriteFile(history.path, []byte(content), 0600)
+}
+
+// load Reads the entries stored in the history file, a missing file is not an error
+func (history *History) load() {
+	if history.path == "" {
+		return
+	}
+	file, err := os.Open(history.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		history.Add(scanner.Text())
+	}
+}
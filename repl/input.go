@@ -0,0 +1,22 @@
+package repl
+
+import (
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// isIncomplete Checks if the input opens more braces or parentheses than it
+// closes, in which case more lines are needed before it can be parsed
+func isIncomplete(input string) bool {
+	lex := lexer.New(input)
+	depth := 0
+	for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		switch tok.Type {
+		case token.LBRACE, token.LPAREN:
+			depth++
+		case token.RBRACE, token.RPAREN:
+			depth--
+		}
+	}
+	return depth > 0
+}
@@ -0,0 +1,179 @@
+package repl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// META_COMMAND_PREFIX Prefix of inputs handled by the repl rather than the evaluator
+const META_COMMAND_PREFIX = ":"
+
+// metaCommand A command controlling or inspecting the repl session
+type metaCommand struct {
+	usage       string
+	description string
+	run         func(s *session, arg string)
+}
+
+// metaCommands Commands available in the repl mapped by name
+var metaCommands map[string]metaCommand
+
+func init() {
+	metaCommands = map[string]metaCommand{
+		"env":      {":env", "List the bindings in the environment and their types", (*session).listBindings},
+		"reset":    {":reset", "Remove all bindings from the environment", (*session).reset},
+		"load":     {":load file.monkey", "Evaluate a file in the environment", (*session).load},
+		"ast":      {":ast expr", "Print the syntax tree of an expression", (*session).printAST},
+		"tokens":   {":tokens expr", "Print the tokens of an expression", (*session).printTokens},
+		"type":     {":type expr", "Print the type an expression evaluates to", (*session).printType},
+		"optimize": {":optimize [on|off]", "Turn the optimization of inputs on or off", (*session).setOptimize},
+		"quit":     {":quit", "Exit the repl", (*session).exit},
+		"help":     {":help", "List the available commands", (*session).printHelp},
+	}
+}
+
+// isMetaCommand Checks if the input is a command for the repl
+func isMetaCommand(input string) bool {
+	return strings.HasPrefix(strings.TrimSpace(input), META_COMMAND_PREFIX)
+}
+
+// runMetaCommand Parses the command name and argument from input and runs the command
+func (s *session) runMetaCommand(input string) {
+	input = strings.TrimPrefix(strings.TrimSpace(input), META_COMMAND_PREFIX)
+	name, arg := input, ""
+	if i := strings.IndexAny(input, " \t\n"); i != -1 {
+		name, arg = input[:i], strings.TrimSpace(input[i:])
+	}
+	command, ok := metaCommands[name]
+	if !ok {
+		fmt.Fprintf(s.out, "Unknown command: %s%s, type :help for a list of commands\n",
+			META_COMMAND_PREFIX, name)
+		return
+	}
+	command.run(s, arg)
+}
+
+// listBindings Prints every binding in the environment with its type
+func (s *session) listBindings(arg string) {
+	for _, name := range s.env.Names() {
+		value, _ := s.env.Get(name)
+		fmt.Fprintf(s.out, "%s: %s\n", name, value.Type())
+	}
+}
+
+// reset Replaces the environment and the defined macros with empty ones
+func (s *session) reset(arg string) {
+	s.env = object.NewEnvironment()
+	s.macroEnv = object.NewEnvironment()
+}
+
+// load Evaluates the contents of a file in the environment
+func (s *session) load(arg string) {
+	if arg == "" {
+		fmt.Fprintln(s.out, "Usage: :load file.monkey")
+		return
+	}
+	source, err := os.ReadFile(arg)
+	if err != nil {
+		fmt.Fprintln(s.out, err)
+		return
+	}
+	s.evalSource(string(source), arg)
+}
+
+// printAST Prints the syntax tree of the argument, one node per line,
+// as it is evaluated when optimization is turned on
+func (s *session) printAST(arg string) {
+	program, ok := s.parse(arg)
+	if !ok {
+		return
+	}
+	depth := 0
+	ast.Inspect(s.optimizeProgram(program), func(node ast.Node) bool {
+		if node == nil {
+			depth--
+			return false
+		}
+		fmt.Fprintf(s.out, "%s%s %s\n", strings.Repeat("  ", depth), nodeKind(node), node.String())
+		depth++
+		return true
+	})
+}
+
+// setOptimize Turns the optimization of inputs on or off and prints whether it is on
+func (s *session) setOptimize(arg string) {
+	switch arg {
+	case "on":
+		s.optimize = true
+	case "off":
+		s.optimize = false
+	case "":
+	default:
+		fmt.Fprintln(s.out, "Usage: :optimize [on|off]")
+		return
+	}
+	if s.optimize {
+		fmt.Fprintln(s.out, "Optimization is on")
+	} else {
+		fmt.Fprintln(s.out, "Optimization is off")
+	}
+}
+
+// printTokens Prints the tokens of the argument with their positions
+func (s *session) printTokens(arg string) {
+	lex := lexer.New(arg)
+	for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		fmt.Fprintf(s.out, "%s\t%s\t%q\n", tok.Position, tok.Type, tok.Literal)
+	}
+}
+
+// printType Evaluates the argument in an enclosed environment, so that no bindings
+// are added to the session, and prints the type of the result
+func (s *session) printType(arg string) {
+	program, ok := s.parse(arg)
+	if !ok {
+		return
+	}
+	expanded, ok := s.expandMacros(program)
+	if !ok {
+		return
+	}
+	evaluated := evaluator.Eval(expanded, object.NewEnclosedEnvironment(s.env))
+	if evaluated == nil {
+		evaluated = evaluator.NULL
+	}
+	io.WriteString(s.out, string(evaluated.Type()))
+	io.WriteString(s.out, "\n")
+}
+
+// exit Ends the session
+func (s *session) exit(arg string) {
+	s.quit = true
+}
+
+// printHelp Prints the available commands
+func (s *session) printHelp(arg string) {
+	names := make([]string, 0, len(metaCommands))
+	for name := range metaCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		command := metaCommands[name]
+		fmt.Fprintf(s.out, "%-20s %s\n", command.usage, command.description)
+	}
+}
+
+// nodeKind Returns the name of the type of an AST node
+func nodeKind(node ast.Node) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+}
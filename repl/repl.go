@@ -5,38 +5,181 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/CzarSimon/monkey/ast"
 	"github.com/CzarSimon/monkey/evaluator"
 	"github.com/CzarSimon/monkey/lexer"
 	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/optimizer"
 	"github.com/CzarSimon/monkey/parser"
 )
 
 const PROMPT = ">> "
 
+// CONTINUATION_PROMPT Prompt shown while reading the rest of an incomplete input
+const CONTINUATION_PROMPT = ".. "
+
 // Start Starts the repl
 func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
-	for {
-		fmt.Print(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
+	s := newSession(out)
+	reader := newLineReader(in, out, s)
+	defer reader.Close()
+	for !s.quit {
+		input, err := readInput(reader)
+		if err == errInterrupted {
+			continue
+		}
+		if err != nil {
 			return
 		}
-		line := scanner.Text()
-		lex := lexer.New(line)
-		p := parser.New(lex)
-		program := p.ParseProgram()
-		if len(p.Errors()) != 0 {
-			printParseErrors(out, p.Errors())
+		if isMetaCommand(input) {
+			s.runMetaCommand(input)
 			continue
 		}
-		evaluated := evaluator.Eval(program, env)
-		if evaluated != nil {
-			io.WriteString(out, evaluated.Inspect())
-			io.WriteString(out, "\n")
+		s.eval(input)
+	}
+}
+
+// session State of a repl session
+type session struct {
+	env      *object.Environment
+	macroEnv *object.Environment // macros defined in the session
+	out      io.Writer
+	optimize bool // if set, inputs are optimized before they are evaluated
+	quit     bool
+}
+
+// newSession Creates a session with an empty environment writing to out
+func newSession(out io.Writer) *session {
+	return &session{
+		env:      object.NewEnvironment(),
+		macroEnv: object.NewEnvironment(),
+		out:      out,
+	}
+}
+
+// eval Parses and evaluates input in the session environment and prints the result
+func (s *session) eval(input string) {
+	s.evalSource(input, "")
+}
+
+// evalSource Parses and evaluates source code read from a file in the session
+// environment and prints the result, errors are printed with a traceback
+func (s *session) evalSource(source, filename string) {
+	program, ok := s.parseSource(source, filename)
+	if !ok {
+		return
+	}
+	expanded, ok := s.expandMacros(program)
+	if !ok {
+		return
+	}
+	evaluated := evaluator.Eval(s.optimizeProgram(expanded), s.env)
+	if err, ok := evaluated.(*object.Error); ok {
+		s.printError(err)
+	} else if evaluated != nil {
+		io.WriteString(s.out, evaluated.Inspect())
+		io.WriteString(s.out, "\n")
+	}
+}
+
+// parse Parses input, printing any parse errors
+func (s *session) parse(input string) (*ast.Program, bool) {
+	return s.parseSource(input, "")
+}
+
+// parseSource Parses source code read from a file, printing any parse errors
+func (s *session) parseSource(source, filename string) (*ast.Program, bool) {
+	p := parser.New(lexer.NewWithFilename(source, filename))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		printParseErrors(s.out, p.Errors())
+		return nil, false
+	}
+	return program, true
+}
+
+// expandMacros Defines the macros of a program in the session and expands
+// the macro calls in it, errors raised by a macro are printed
+func (s *session) expandMacros(program *ast.Program) (ast.Node, bool) {
+	evaluator.DefineMacros(program, s.macroEnv)
+	expanded, err := evaluator.ExpandMacros(program, s.macroEnv)
+	if err != nil {
+		s.printError(err)
+		return nil, false
+	}
+	return expanded, true
+}
+
+// optimizeProgram Optimizes a program if optimization is turned on in the session
+func (s *session) optimizeProgram(node ast.Node) ast.Node {
+	if program, ok := node.(*ast.Program); ok && s.optimize {
+		return optimizer.OptimizeIncremental(program)
+	}
+	return node
+}
+
+// printError Prints the traceback of an error
+func (s *session) printError(err *object.Error) {
+	io.WriteString(s.out, err.Traceback())
+	io.WriteString(s.out, "\n")
+}
+
+// complete Completer suggesting keywords, builtins and names bound in the current environment
+func (s *session) complete(prefix string) []string {
+	return newEnvCompleter(s.env, evaluator.BuiltinNames())(prefix)
+}
+
+// newLineReader Creates a line editor if in is a terminal, otherwise a plain line scanner
+func newLineReader(in io.Reader, out io.Writer, s *session) lineReader {
+	term, ok := openTerminal(in)
+	if !ok {
+		return newScannerReader(in, out)
+	}
+	e := newEditor(in, out, NewHistory(DefaultHistoryPath()), s.complete)
+	e.term = term
+	return e
+}
+
+// readInput Reads lines until the braces and parentheses of the input are balanced
+func readInput(reader lineReader) (string, error) {
+	input, err := reader.ReadLine(PROMPT)
+	for err == nil && isIncomplete(input) {
+		var line string
+		line, err = reader.ReadLine(CONTINUATION_PROMPT)
+		input += "\n" + line
+	}
+	return input, err
+}
+
+// scannerReader lineReader reading lines from a non interactive input
+type scannerReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// newScannerReader Creates a scannerReader reading from in and writing prompts to out
+func newScannerReader(in io.Reader, out io.Writer) *scannerReader {
+	return &scannerReader{
+		scanner: bufio.NewScanner(in),
+		out:     out,
+	}
+}
+
+// ReadLine Prints the prompt and reads the next line
+func (reader *scannerReader) ReadLine(prompt string) (string, error) {
+	io.WriteString(reader.out, prompt)
+	if !reader.scanner.Scan() {
+		if err := reader.scanner.Err(); err != nil {
+			return "", err
 		}
+		return "", io.EOF
 	}
+	return reader.scanner.Text(), nil
+}
+
+// Close Nothing to release for a scannerReader
+func (reader *scannerReader) Close() error {
+	return nil
 }
 
 func printParseErrors(out io.Writer, errors []error) {
@@ -0,0 +1,217 @@
+package repl
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+func TestIsIncomplete(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"let x = 5;", false},
+		{"let add = fn(x, y) {", true},
+		{"let add = fn(x, y) {\n x + y", true},
+		{"let add = fn(x, y) {\n x + y\n};", false},
+		{"add(1,", true},
+		{"add(1, 2)", false},
+		{"}", false},
+		{"// {", false},
+	}
+	for i, test := range tests {
+		if isIncomplete(test.input) != test.expected {
+			t.Errorf("%d - isIncomplete(%q) wrong Expected=%t", i, test.input, test.expected)
+		}
+	}
+}
+
+func TestStartWithMultilineInput(t *testing.T) {
+	input := "let add = fn(x, y) {\n  x + y\n};\nadd(2,\n 3)\n"
+	var out bytes.Buffer
+	Start(strings.NewReader(input), &out)
+	expected := ">> .. .. >> .. 5\n>> "
+	if out.String() != expected {
+		t.Fatalf("Wrong output Expected=%q Got=%q", expected, out.String())
+	}
+}
+
+func TestEditorEditing(t *testing.T) {
+	tests := []struct {
+		keys     string
+		expected string
+	}{
+		{"let x = 5;\r", "let x = 5;"},
+		{"abc\x7f\x7fd\r", "ad"},
+		{"bc\x01a\x05d\r", "abcd"},
+		{"ac\x1b[Db\r", "abc"},
+		{"ab\x1b[D\x1b[Dc\x1b[C\x1b[3~\r", "ca"},
+		{"abcd\x02\x02\x0b\r", "ab"},
+		{"abcd\x02\x02\x15\r", "cd"},
+		{"ab\x1b[Hc\x1b[Fd\n", "cabd"},
+	}
+	for i, test := range tests {
+		e := newEditor(strings.NewReader(test.keys), io.Discard, NewHistory(""), noCompletions)
+		line, err := e.ReadLine(PROMPT)
+		if err != nil {
+			t.Fatalf("%d - Unexpected error: %s", i, err)
+		}
+		if line != test.expected {
+			t.Errorf("%d - Wrong line Expected=%q Got=%q", i, test.expected, line)
+		}
+	}
+}
+
+func TestEditorControlKeys(t *testing.T) {
+	e := newEditor(strings.NewReader("ab\x03"), io.Discard, NewHistory(""), noCompletions)
+	if _, err := e.ReadLine(PROMPT); err != errInterrupted {
+		t.Errorf("Expected errInterrupted on ctrl-c Got=%v", err)
+	}
+	e = newEditor(strings.NewReader("\x04"), io.Discard, NewHistory(""), noCompletions)
+	if _, err := e.ReadLine(PROMPT); err != io.EOF {
+		t.Errorf("Expected io.EOF on ctrl-d Got=%v", err)
+	}
+	e = newEditor(strings.NewReader("ab\x02\x04\r"), io.Discard, NewHistory(""), noCompletions)
+	if line, _ := e.ReadLine(PROMPT); line != "a" {
+		t.Errorf("Expected ctrl-d to delete under cursor Got=%q", line)
+	}
+}
+
+func TestEditorHistory(t *testing.T) {
+	keys := "first\rsecond\r\x1b[A\x1b[A\r\x1b[A\x1b[A\x1b[Bx\r"
+	e := newEditor(strings.NewReader(keys), io.Discard, NewHistory(""), noCompletions)
+	expected := []string{"first", "second", "first", "firstx"}
+	for i, expectedLine := range expected {
+		line, err := e.ReadLine(PROMPT)
+		if err != nil {
+			t.Fatalf("%d - Unexpected error: %s", i, err)
+		}
+		if line != expectedLine {
+			t.Errorf("%d - Wrong line Expected=%q Got=%q", i, expectedLine, line)
+		}
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := newEditor(strings.NewReader("let x = 1;\r\rx\r"), io.Discard, NewHistory(path), noCompletions)
+	for i := 0; i < 3; i++ {
+		e.ReadLine(PROMPT)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Unexpected error saving history: %s", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %s", err)
+	}
+	if string(content) != "let x = 1;\nx\n" {
+		t.Fatalf("Wrong history file content Got=%q", string(content))
+	}
+	history := NewHistory(path)
+	if history.Len() != 2 || history.Entry(0) != "let x = 1;" || history.Entry(1) != "x" {
+		t.Fatalf("Wrong history loaded Got=%+v", history.entries)
+	}
+}
+
+func TestEditorCompletion(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("fibonacci", object.NewInteger(1))
+	env.Set("filter", object.NewInteger(2))
+	complete := newEnvCompleter(env, []string{"puts"})
+	tests := []struct {
+		keys     string
+		expected string
+	}{
+		{"fib\t(1)\r", "fibonacci(1)"},
+		{"fi\tb\t\r", "fibonacci"},
+		{"le\t x = 1\r", "let x = 1"},
+		{"re\t\r", "return"},
+		{"zz\t\r", "zz"},
+		{"pu\t(1)\r", "puts(1)"},
+	}
+	for i, test := range tests {
+		e := newEditor(strings.NewReader(test.keys), io.Discard, NewHistory(""), complete)
+		line, _ := e.ReadLine(PROMPT)
+		if line != test.expected {
+			t.Errorf("%d - Wrong line Expected=%q Got=%q", i, test.expected, line)
+		}
+	}
+	candidates := complete("f")
+	expected := []string{"false", "fibonacci", "filter", "finally", "fn"}
+	if strings.Join(candidates, ",") != strings.Join(expected, ",") {
+		t.Errorf("Wrong candidates Expected=%v Got=%v", expected, candidates)
+	}
+}
+
+func noCompletions(prefix string) []string {
+	return nil
+}
+
+func TestMetaCommands(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "lib.monkey")
+	os.WriteFile(file, []byte("let double = fn(x) { x * 2 };\n"), 0600)
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{":env", ""},
+		{"let a = 5; let b = true;\n:env", "a: INTEGER\nb: BOOLEAN\n"},
+		{":load " + file + "\ndouble(4)", "8\n"},
+		{"let a = 5;\n:reset\na", "Traceback (most recent call last):\n" +
+			"  File \"<input>\", line 1, column 1, in <module>\nNameError: Identifier not found: a\n"},
+		{":type 1 < 2", "BOOLEAN\n"},
+		{":type let c = 1;\n:env", "NULL\n"},
+		{":type fn(x) {\n x\n}", "FUNCTION\n"},
+		{":tokens let x = 1;", "1:1\tLET\t\"let\"\n1:5\tIDENT\t\"x\"\n1:7\t=\t\"=\"\n1:9\tINT\t\"1\"\n1:10\t;\t\";\"\n"},
+		{":ast -a + 2", "Program ((-a) + 2)\n  ExpressionStatement ((-a) + 2)\n    InfixExpression ((-a) + 2)\n" +
+			"      PrefixExpression (-a)\n        Identifier a\n      IntegerLiteral 2\n"},
+		{"let double = macro(x) { quote(unquote(x) * 2) };\ndouble(4)", "8\n"},
+		{"let m = macro() { quote(1) };\n:reset\nm()", "Traceback (most recent call last):\n" +
+			"  File \"<input>\", line 1, column 1, in <module>\nNameError: Identifier not found: m\n"},
+		{":quit\n1", ""},
+		{":nope", "Unknown command: :nope, type :help for a list of commands\n"},
+		{":load", "Usage: :load file.monkey\n"},
+		{":optimize", "Optimization is off\n"},
+		{":optimize on\n:ast 60 * 60", "Optimization is on\nProgram 3600\n  ExpressionStatement 3600\n    IntegerLiteral 3600\n"},
+		{":optimize on\nlet x = 2; let f = fn() { x }; let g = fn() { let y = 3; y };\nlet x = 4; f() + g()",
+			"Optimization is on\n7\n"},
+		{":optimize on\n:optimize off\n:ast 1 + 2", "Optimization is on\nOptimization is off\n" +
+			"Program (1 + 2)\n  ExpressionStatement (1 + 2)\n    InfixExpression (1 + 2)\n      IntegerLiteral 1\n      IntegerLiteral 2\n"},
+		{":optimize maybe", "Usage: :optimize [on|off]\n"},
+	}
+	for i, test := range tests {
+		var out bytes.Buffer
+		Start(strings.NewReader(test.input), &out)
+		output := strings.Replace(out.String(), PROMPT, "", -1)
+		output = strings.Replace(output, CONTINUATION_PROMPT, "", -1)
+		if output != test.expected {
+			t.Errorf("%d - Wrong output Expected=%q Got=%q", i, test.expected, output)
+		}
+	}
+}
+
+func TestTraceback(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "lib.monkey")
+	os.WriteFile(file, []byte("let check = fn(x) {\n  if (x > 2) { throw \"too big\" }\n  x\n};\n"), 0600)
+	input := ":load " + file + "\nlet apply = fn(f, x) { f(x) };\nmap([1, 2, 3], fn(x) { apply(check, x) })"
+	var out bytes.Buffer
+	Start(strings.NewReader(input), &out)
+	output := strings.Replace(out.String(), PROMPT, "", -1)
+	expected := "Traceback (most recent call last):\n" +
+		"  File \"<input>\", line 1, column 4, in <module>\n" +
+		"  File \"<builtin>\", in map\n" +
+		"  File \"<input>\", line 1, column 29, in <anonymous>\n" +
+		"  File \"<input>\", line 1, column 25, in apply\n" +
+		"  File \"" + file + "\", line 2, column 16, in check\n" +
+		"Error: too big\n"
+	if output != expected {
+		t.Fatalf("Wrong traceback Expected=%q Got=%q", expected, output)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package repl
+
+import "io"
+
+// terminal Placeholder on platforms where raw terminal mode is not supported
+type terminal struct{}
+
+// openTerminal Line editing is not supported on this platform
+func openTerminal(in io.Reader) (*terminal, bool) {
+	return nil, false
+}
+
+func (term *terminal) makeRaw() error { return nil }
+
+func (term *terminal) restore() error { return nil }
@@ -0,0 +1,72 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package repl
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminal Controls the mode of a terminal file descriptor
+type terminal struct {
+	fd       uintptr
+	original syscall.Termios
+}
+
+// openTerminal Returns a terminal if the supplied reader is one
+func openTerminal(in io.Reader) (*terminal, bool) {
+	file, ok := in.(*os.File)
+	if !ok {
+		return nil, false
+	}
+	term := &terminal{fd: file.Fd()}
+	if err := term.getAttributes(&term.original); err != nil {
+		return nil, false
+	}
+	return term, true
+}
+
+// makeRaw Disables echo, line buffering and signal handling so every key
+// press is delivered to the editor as is
+func (term *terminal) makeRaw() error {
+	if err := term.getAttributes(&term.original); err != nil {
+		return err
+	}
+	raw := term.original
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	return term.setAttributes(&raw)
+}
+
+// restore Resets the terminal to the mode it had before makeRaw was called
+func (term *terminal) restore() error {
+	return term.setAttributes(&term.original)
+}
+
+// getAttributes Reads the terminal attributes into termios
+func (term *terminal) getAttributes(termios *syscall.Termios) error {
+	return term.ioctl(ioctlReadTermios, termios)
+}
+
+// setAttributes Applies the attributes in termios to the terminal
+func (term *terminal) setAttributes(termios *syscall.Termios) error {
+	return term.ioctl(ioctlWriteTermios, termios)
+}
+
+// ioctl Performs a termios ioctl request on the terminal
+func (term *terminal) ioctl(request uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, term.fd, request, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
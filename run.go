@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/coverage"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/optimizer"
+	"github.com/CzarSimon/monkey/parser"
+	"github.com/CzarSimon/monkey/profiler"
+	"github.com/CzarSimon/monkey/tracer"
+)
+
+// runCommand Evaluates a monkey file and prints the result of its last
+// expression unless it is null, exits with 1 on parse or runtime errors
+// after printing a traceback of the error
+func runCommand(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	searchPath := flags.String("path", "", "list of directories searched for imported modules")
+	noRedeclare := flags.Bool("no-redeclare", false, "make redeclaring a top level let binding an error")
+	optimize := flags.Bool("optimize", false, "fold constant expressions, eliminate dead branches and inline constants before evaluating")
+	profile := flags.String("profile", "", "file a pprof profile of the evaluation is written to, a summary is printed to stderr")
+	cover := flags.String("cover", "", "file an LCOV coverage report is written to, a summary is printed to stderr")
+	trace := flags.Bool("trace", false, "print each evaluated node and its value to stderr")
+	traceFunc := flags.String("trace-func", "", "only trace nodes evaluated while the named function is called, implies -trace")
+	traceFile := flags.String("trace-file", "", "file the trace is written to as JSON lines instead of stderr, implies -trace")
+	memoryLimit := flags.Int64("memory-limit", 0, "bytes the values of the program may use before a MemoryError is raised, 0 for no limit")
+	memoryStats := flags.Bool("memory", false, "print the peak memory usage to stderr, implied by -memory-limit")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: monkey run [-path dirs] [-no-redeclare] [-optimize] [-profile file] [-cover file] "+
+			"[-trace] [-trace-func name] [-trace-file file] [-memory] [-memory-limit bytes] file.monkey")
+		return 2
+	}
+	if *searchPath != "" {
+		evaluator.Modules.SearchPath = append(filepath.SplitList(*searchPath), evaluator.Modules.SearchPath...)
+	}
+	program, ok := loadProgram(flags.Arg(0))
+	if !ok {
+		return 1
+	}
+	if expanded, ok := program.(*ast.Program); ok && *optimize {
+		program = optimizer.Optimize(expanded)
+	}
+	env := object.NewEnvironment()
+	if *noRedeclare {
+		env = object.NewNoRedeclareEnvironment()
+	}
+	hooks := make([]evaluator.Hook, 0)
+	var p *profiler.Profiler
+	if *profile != "" {
+		p = profiler.New(flags.Arg(0))
+		hooks = append(hooks, p)
+	}
+	var c *coverage.Coverage
+	if *cover != "" {
+		c = coverage.New()
+		hooks = append(hooks, c)
+	}
+	if *trace || *traceFunc != "" || *traceFile != "" {
+		t, closeTrace, err := newTracer(*traceFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer closeTrace()
+		t.Function = *traceFunc
+		hooks = append(hooks, t)
+	}
+	if len(hooks) > 0 {
+		defer evaluator.SetHook(evaluator.SetHook(evaluator.Hooks(hooks...)))
+	}
+	var memory *object.Memory
+	if *memoryStats || *memoryLimit > 0 {
+		memory = object.NewMemory(*memoryLimit)
+		env.SetMemory(memory)
+	}
+	if p != nil {
+		p.Start()
+	}
+	result := evaluator.Eval(program, env)
+	if memory != nil {
+		fmt.Fprintf(os.Stderr, "Peak memory usage: %d bytes, %d bytes allocated\n", memory.Peak(), memory.Allocated())
+	}
+	if p != nil {
+		p.Stop()
+		if err := writeProfile(p, *profile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if c != nil {
+		if err := writeCoverage(c, *cover); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if err, ok := result.(*object.Error); ok {
+		fmt.Fprintln(os.Stderr, err.Traceback())
+		return 1
+	}
+	if result != nil && result != evaluator.NULL {
+		fmt.Println(result.Inspect())
+	}
+	return 0
+}
+
+// PROFILE_SUMMARY_LIMIT Number of functions listed in the summary of a profile
+const PROFILE_SUMMARY_LIMIT = 20
+
+// writeProfile Writes a pprof profile to a file and prints a summary of the hot functions to stderr
+func writeProfile(p *profiler.Profiler, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := p.WritePprof(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	p.WriteSummary(os.Stderr, PROFILE_SUMMARY_LIMIT)
+	return nil
+}
+
+// newTracer Creates a Tracer writing text to stderr, or JSON lines to a file
+// if filename is not empty. The returned function closes the file
+func newTracer(filename string) (*tracer.Tracer, func(), error) {
+	if filename == "" {
+		return tracer.New(os.Stderr, tracer.TEXT), func() {}, nil
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := bufio.NewWriter(file)
+	closeTrace := func() {
+		out.Flush()
+		file.Close()
+	}
+	return tracer.New(out, tracer.JSON_LINES), closeTrace, nil
+}
+
+// writeCoverage Writes an LCOV coverage report to a file and prints a summary of it to stderr
+func writeCoverage(c *coverage.Coverage, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := c.WriteLCOV(file, ""); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	c.WriteSummary(os.Stderr)
+	return nil
+}
+
+// loadProgram Reads and parses a monkey file and expands its macros, errors
+// are printed to stderr and reported by returning false
+func loadProgram(filename string) (ast.Node, bool) {
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
+	}
+	p := parser.New(lexer.NewWithFilename(string(source), filename))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, err := range p.Errors() {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return nil, false
+	}
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, expandErr := evaluator.ExpandMacros(program, macroEnv)
+	if expandErr != nil {
+		fmt.Fprintln(os.Stderr, expandErr.Traceback())
+		return nil, false
+	}
+	return expanded, true
+}
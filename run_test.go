@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunOptimize(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.monkey")
+	source := "let day = 60 * 60 * 24;\nlet f = fn(n) { if (true) { n * day } else { 0 } };\nf(2)\n"
+	if err := os.WriteFile(file, []byte(source), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args   []string
+		infix  int
+		ifExpr int
+	}{
+		{[]string{}, 3, 1},
+		{[]string{"-optimize"}, 1, 0},
+	}
+	for _, test := range tests {
+		trace := filepath.Join(dir, "trace.jsonl")
+		args := append(test.args, "-trace-file", trace, file)
+		output, code := captureStdout(t, func() int { return runCommand(args) })
+		if code != 0 || output != "172800\n" {
+			t.Errorf("%v - Wrong result Expected=172800 Got=%q exit code %d", test.args, output, code)
+		}
+		events, err := os.ReadFile(trace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		infix := strings.Count(string(events), `"kind":"InfixExpression"`)
+		ifExpr := strings.Count(string(events), `"kind":"IFExpression"`)
+		if infix != test.infix || ifExpr != test.ifExpr {
+			t.Errorf("%v - Wrong evaluated nodes Expected=%d infix and %d if expressions Got=%d and %d",
+				test.args, test.infix, test.ifExpr, infix, ifExpr)
+		}
+	}
+}
+
+// captureStdout Returns what a function prints to stdout and the exit code it returns
+func captureStdout(t *testing.T, fn func() int) (string, int) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	code := fn()
+	os.Stdout = stdout
+	w.Close()
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output), code
+}
@@ -0,0 +1,132 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/object"
+)
+
+// FS_CAPABILITY Name of the capability granting read access to files
+const FS_CAPABILITY = "fs"
+
+// fileSystem Read only view of the files below a root directory
+type fileSystem struct {
+	root string
+}
+
+// FileSystem Grants read access to the files below root with read_file(path),
+// list_dir(path) and file_exists(path). Paths are relative to root, paths
+// leaving root, directly or through symbolic links, raise a PermissionError
+func FileSystem(root string) Capability {
+	fs := &fileSystem{root: root}
+	return NewCapability(FS_CAPABILITY, map[string]object.BuiltinFunction{
+		"read_file":   fs.readFile,
+		"list_dir":    fs.listDir,
+		"file_exists": fs.fileExists,
+	})
+}
+
+// readFile Returns the content of a file
+func (fs *fileSystem) readFile(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("read_file", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	path := args[0].(*object.String).Value
+	resolved, err := fs.resolve(path)
+	if err != nil {
+		return err
+	}
+	content, readErr := os.ReadFile(resolved)
+	if readErr != nil {
+		return ioError("Could not read", path, readErr)
+	}
+	return object.NewString(string(content))
+}
+
+// listDir Returns the sorted names of the entries of a directory
+func (fs *fileSystem) listDir(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("list_dir", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	path := args[0].(*object.String).Value
+	resolved, err := fs.resolve(path)
+	if err != nil {
+		return err
+	}
+	entries, readErr := os.ReadDir(resolved)
+	if readErr != nil {
+		return ioError("Could not list", path, readErr)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	elements := make([]object.Object, 0, len(names))
+	for _, name := range names {
+		elements = append(elements, object.NewString(name))
+	}
+	return object.NewArray(elements)
+}
+
+// fileExists Checks if a file or directory exists
+func (fs *fileSystem) fileExists(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkArgs("file_exists", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+	_, err := fs.resolve(args[0].(*object.String).Value)
+	if err != nil && err.Kind == object.IO_ERROR {
+		return evaluator.FALSE
+	}
+	if err != nil {
+		return err
+	}
+	return evaluator.TRUE
+}
+
+// resolve Returns the path of a file below the root with symbolic links
+// evaluated. Paths outside the root raise a PermissionError before their
+// existence is checked, missing files raise an IOError
+func (fs *fileSystem) resolve(path string) (string, *object.Error) {
+	if filepath.IsAbs(path) || filepath.VolumeName(path) != "" {
+		return "", object.NewKindErrorf(object.PERMISSION_ERROR, "Access denied to %s: path must be relative", path)
+	}
+	root, err := filepath.Abs(fs.root)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return "", ioError("Could not access", path, err)
+	}
+	joined := filepath.Join(root, path)
+	if !within(root, joined) {
+		return "", object.NewKindErrorf(object.PERMISSION_ERROR, "Access denied to %s", path)
+	}
+	resolved, err := filepath.EvalSymlinks(joined)
+	if err != nil {
+		return "", ioError("Could not access", path, err)
+	}
+	if !within(root, resolved) {
+		return "", object.NewKindErrorf(object.PERMISSION_ERROR, "Access denied to %s", path)
+	}
+	return resolved, nil
+}
+
+// within Checks if path is root or below it, both must be clean absolute paths
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ioError Creates an IOError for an operation on a path, the underlying
+// error is described without the host path it may hold
+func ioError(operation, path string, err error) *object.Error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		err = pathErr.Err
+	}
+	return object.NewKindErrorf(object.IO_ERROR, "%s %s: %s", operation, path, err)
+}
@@ -0,0 +1,114 @@
+package sandbox
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/object"
+)
+
+const (
+	ENV_CAPABILITY         = "env"         // name of the capability granting access to environment variables
+	CLOCK_CAPABILITY       = "clock"       // name of the capability granting access to the current time
+	RANDOM_CAPABILITY      = "random"      // name of the capability granting access to random numbers
+	OUTPUT_CAPABILITY      = "output"      // name of the capability granting printing of values
+	CONCURRENCY_CAPABILITY = "concurrency" // name of the capability granting the running of tasks
+)
+
+// concurrencyBuiltins Builtins granted by the concurrency capability
+var concurrencyBuiltins = []string{"spawn", "await", "channel", "send", "recv", "close", "select"}
+
+// Env Grants read access to the named environment variables with getenv(name),
+// which returns null for variables that are unset or not named
+func Env(names ...string) Capability {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+	return NewCapability(ENV_CAPABILITY, map[string]object.BuiltinFunction{
+		"getenv": func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArgs("getenv", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			name := args[0].(*object.String).Value
+			if !allowed[name] {
+				return evaluator.NULL
+			}
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				return evaluator.NULL
+			}
+			return object.NewString(value)
+		},
+	})
+}
+
+// Clock Grants access to the current time with now(), which returns the
+// milliseconds since the Unix epoch. A nil clock reads the system clock
+func Clock(clock func() time.Time) Capability {
+	if clock == nil {
+		clock = time.Now
+	}
+	return NewCapability(CLOCK_CAPABILITY, map[string]object.BuiltinFunction{
+		"now": func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArgs("now", args); err != nil {
+				return err
+			}
+			return object.NewInteger(clock().UnixNano() / int64(time.Millisecond))
+		},
+	})
+}
+
+// Random Grants access to random numbers with random(n), which returns an
+// integer in [0, n). A nil source is seeded with the current time
+func Random(source rand.Source) Capability {
+	if source == nil {
+		source = rand.NewSource(time.Now().UnixNano())
+	}
+	random := rand.New(source)
+	var mutex sync.Mutex
+	return NewCapability(RANDOM_CAPABILITY, map[string]object.BuiltinFunction{
+		"random": func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArgs("random", args, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			n := args[0].(*object.Integer).Value
+			if n <= 0 {
+				return object.NewKindErrorf(object.VALUE_ERROR, "Argument to random must be positive Got=%d", n)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			return object.NewInteger(random.Int63n(n))
+		},
+	})
+}
+
+// Output Grants printing values with puts(values...), which writes each of them on a line of out
+func Output(out io.Writer) Capability {
+	var mutex sync.Mutex
+	return NewCapability(OUTPUT_CAPABILITY, map[string]object.BuiltinFunction{
+		"puts": func(env *object.Environment, args ...object.Object) object.Object {
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, arg := range args {
+				fmt.Fprintln(out, arg.Inspect())
+			}
+			return evaluator.NULL
+		},
+	})
+}
+
+// Concurrency Grants running functions in tasks of their own with spawn and
+// await, and communicating between them with channel, send, recv, close and select
+func Concurrency() Capability {
+	fns := make(map[string]object.BuiltinFunction, len(concurrencyBuiltins))
+	for _, name := range concurrencyBuiltins {
+		fns[name] = evaluator.Builtin(name).Fn
+	}
+	return NewCapability(CONCURRENCY_CAPABILITY, fns)
+}
@@ -0,0 +1,58 @@
+package sandbox
+
+import (
+	"github.com/CzarSimon/monkey/object"
+)
+
+// Capability Group of builtins granting scripts access to a part of the host
+type Capability struct {
+	Name     string
+	Builtins []*object.Builtin
+}
+
+// NewCapability Creates a Capability from a set of builtin functions
+func NewCapability(name string, fns map[string]object.BuiltinFunction) Capability {
+	capability := Capability{Name: name, Builtins: make([]*object.Builtin, 0, len(fns))}
+	for fnName, fn := range fns {
+		capability.Builtins = append(capability.Builtins, object.NewBuiltin(fnName, fn))
+	}
+	return capability
+}
+
+// pureBuiltins Builtins that only compute on their arguments, which are available in every sandbox
+var pureBuiltins = []string{
+	"len",
+	"map", "filter", "reduce", "range", "sort", "zip", "keys", "values", "contains",
+	"split", "join", "trim", "upper", "lower", "replace", "index_of", "substr", "format", "to_string", "parse_int",
+	"error", "error_message", "error_kind", "error_stack",
+	"assert", "assert_eq", "assert_error",
+}
+
+// NewEnvironment Creates an environment for untrusted scripts in which the
+// builtins of the granted capabilities are bound. Every other builtin is
+// absent, referencing it raises a NameError, except those only computing
+// on their arguments, e.g. len and map. Modules cannot be imported
+func NewEnvironment(capabilities ...Capability) *object.Environment {
+	host := object.NewSandboxEnvironment(pureBuiltins...)
+	for _, capability := range capabilities {
+		for _, builtin := range capability.Builtins {
+			host.Set(builtin.Name, builtin)
+		}
+	}
+	return object.NewEnclosedEnvironment(host)
+}
+
+// checkArgs Checks that a builtin got arguments of the expected types
+func checkArgs(name string, args []object.Object, types ...object.ObjectType) *object.Error {
+	if len(args) != len(types) {
+		return object.NewKindErrorf(object.ARGUMENT_ERROR, "Wrong number of arguments to %s Expected=%d Got=%d",
+			name, len(types), len(args))
+	}
+	for i, expected := range types {
+		if args[i].Type() != expected {
+			return object.NewKindErrorf(object.TYPE_ERROR, "Argument %d to %s must be %s Got=%s",
+				i+1, name, expected, args[i].Type())
+		}
+	}
+	return nil
+}
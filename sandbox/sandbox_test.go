@@ -0,0 +1,261 @@
+package sandbox
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+// run Evaluates a script in an environment
+func run(t *testing.T, input string, env *object.Environment) object.Object {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors in %q: %v", input, p.Errors())
+	}
+	return evaluator.Eval(program, env)
+}
+
+// expectError Checks that a result is an error of a kind, returning it
+func expectError(t *testing.T, input string, result object.Object, kind object.ErrorKind) *object.Error {
+	err, ok := result.(*object.Error)
+	if !ok || err.Kind != kind {
+		t.Errorf("%s - Expected %s Got=%s", input, kind, result.Inspect())
+		return nil
+	}
+	return err
+}
+
+// newRoot Creates a root directory with a data file, a secret file outside
+// the root and symbolic links from the root to the secret and its directory
+func newRoot(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	secrets := filepath.Join(dir, "secrets")
+	for _, d := range []string{filepath.Join(root, "data"), secrets} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(root, "data", "a.txt"), []byte("hello"), 0600)
+	secret := filepath.Join(secrets, "secret.txt")
+	os.WriteFile(secret, []byte("password"), 0600)
+	os.Symlink(secret, filepath.Join(root, "secret_link"))
+	os.Symlink(secrets, filepath.Join(root, "secrets_link"))
+	os.Symlink("data", filepath.Join(root, "data_link"))
+	return root, secret
+}
+
+var capabilityBuiltins = map[string]string{
+	"read_file":   FS_CAPABILITY,
+	"list_dir":    FS_CAPABILITY,
+	"file_exists": FS_CAPABILITY,
+	"getenv":      ENV_CAPABILITY,
+	"now":         CLOCK_CAPABILITY,
+	"random":      RANDOM_CAPABILITY,
+	"puts":        OUTPUT_CAPABILITY,
+	"spawn":       CONCURRENCY_CAPABILITY,
+	"await":       CONCURRENCY_CAPABILITY,
+	"channel":     CONCURRENCY_CAPABILITY,
+	"send":        CONCURRENCY_CAPABILITY,
+	"recv":        CONCURRENCY_CAPABILITY,
+	"close":       CONCURRENCY_CAPABILITY,
+	"select":      CONCURRENCY_CAPABILITY,
+}
+
+func TestUngrantedCapabilitiesAreAbsent(t *testing.T) {
+	root, _ := newRoot(t)
+	capabilities := []Capability{FileSystem(root), Env(), Clock(nil), Random(nil), Output(os.Stdout), Concurrency()}
+	for _, granted := range capabilities {
+		env := NewEnvironment(granted)
+		names := strings.Join(env.Names(), ",")
+		for name, capability := range capabilityBuiltins {
+			result := run(t, name, env)
+			if capability == granted.Name {
+				if _, ok := result.(*object.Builtin); !ok {
+					t.Errorf("%s - Expected %s to be bound Got=%s", granted.Name, name, result.Inspect())
+				}
+				continue
+			}
+			expectError(t, name, result, object.NAME_ERROR)
+			if strings.Contains(","+names+",", ","+name+",") {
+				t.Errorf("%s - Expected %s not to be bound Got=%s", granted.Name, name, names)
+			}
+			caught := run(t, "try { "+name+"(\"x\") } catch (e) { error_kind(e) }", env)
+			if caught.Inspect() != "NameError" {
+				t.Errorf("%s - Expected calling %s to raise a NameError Got=%s", granted.Name, name, caught.Inspect())
+			}
+		}
+	}
+	env := NewEnvironment()
+	if names := env.Names(); len(names) != 0 {
+		t.Errorf("Expected nothing to be bound in an environment without capabilities Got=%v", names)
+	}
+	if result := run(t, "len(map([1, 2], fn(x) { x }))", env); result.Inspect() != "2" {
+		t.Errorf("Expected pure builtins to be available Got=%s", result.Inspect())
+	}
+}
+
+func TestCapabilitiesDoNotLeak(t *testing.T) {
+	root, _ := newRoot(t)
+	granted := NewEnvironment(FileSystem(root))
+	run(t, "let read_file = fn(x) { \"shadowed\" };", granted)
+	other := NewEnvironment(Clock(nil))
+	expectError(t, "read_file", run(t, "read_file", other), object.NAME_ERROR)
+	expectError(t, "read_file", run(t, "read_file", object.NewEnvironment()), object.NAME_ERROR)
+	if result := run(t, `read_file("data/a.txt")`, NewEnvironment(FileSystem(root))); result.Inspect() != "hello" {
+		t.Errorf("Expected a new environment not to see bindings of another Got=%s", result.Inspect())
+	}
+}
+
+func TestImportsAreDenied(t *testing.T) {
+	root, _ := newRoot(t)
+	module := filepath.Join(root, "lib.monkey")
+	os.WriteFile(module, []byte("export let x = 1;"), 0600)
+	env := NewEnvironment(FileSystem(root))
+	tests := []string{
+		`import("` + module + `")`,
+		`import("lib.monkey")`,
+		`let load = fn() { import("lib.monkey") }; load()`,
+		`map(["lib.monkey"], fn(path) { import(path) })`,
+	}
+	for _, input := range tests {
+		expectError(t, input, run(t, input, env), object.PERMISSION_ERROR)
+	}
+	if result := run(t, `let lib = import("`+module+`"); lib.x`, object.NewEnvironment()); result.Inspect() != "1" {
+		t.Errorf("Expected imports outside of a sandbox to work Got=%s", result.Inspect())
+	}
+}
+
+func TestFileSystem(t *testing.T) {
+	root, secret := newRoot(t)
+	env := NewEnvironment(FileSystem(root))
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`read_file("data/a.txt")`, "hello"},
+		{`read_file("./data/../data/a.txt")`, "hello"},
+		{`read_file("data_link/a.txt")`, "hello"},
+		{`list_dir(".")`, "[data, data_link, secret_link, secrets_link]"},
+		{`list_dir("data")`, "[a.txt]"},
+		{`file_exists("data/a.txt")`, "true"},
+		{`file_exists("data/missing.txt")`, "false"},
+		{`if (file_exists("data/missing.txt")) { 1 } else { 2 }`, "2"},
+		{`if (file_exists("data/a.txt")) { 1 } else { 2 }`, "1"},
+		{`!file_exists("data/missing.txt")`, "true"},
+		{`file_exists("data/missing.txt") == false`, "true"},
+		{`file_exists("data/a.txt") == true`, "true"},
+		{`try { read_file("missing.txt") } catch (e) { error_kind(e) }`, "IOError"},
+		{`try { read_file("../secrets/secret.txt") } catch (e) { error_kind(e) }`, "PermissionError"},
+	}
+	for _, test := range tests {
+		if result := run(t, test.input, env); result.Inspect() != test.expected {
+			t.Errorf("%s - Expected=%s Got=%s", test.input, test.expected, result.Inspect())
+		}
+	}
+	escapes := []string{
+		`read_file("` + secret + `")`,
+		`read_file("../secrets/secret.txt")`,
+		`read_file("data/../../secrets/secret.txt")`,
+		`read_file("..")`,
+		`read_file("secret_link")`,
+		`read_file("secrets_link/secret.txt")`,
+		`list_dir("..")`,
+		`list_dir("secrets_link")`,
+		`list_dir("/")`,
+		`file_exists("../secrets/secret.txt")`,
+		`file_exists("../secrets/missing.txt")`,
+		`file_exists("secret_link")`,
+	}
+	for _, input := range escapes {
+		err := expectError(t, input, run(t, input, env), object.PERMISSION_ERROR)
+		if err != nil && strings.Contains(err.Message, filepath.Dir(root)) && !strings.Contains(input, filepath.Dir(root)) {
+			t.Errorf("%s - Expected the error not to reveal host paths Got=%s", input, err.Message)
+		}
+	}
+	err := expectError(t, "missing", run(t, `read_file("missing.txt")`, env), object.IO_ERROR)
+	if err != nil && strings.Contains(err.Message, root) {
+		t.Errorf("Expected the error not to reveal the root Got=%s", err.Message)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("MONKEY_ALLOWED", "visible")
+	t.Setenv("MONKEY_SECRET", "hidden")
+	env := NewEnvironment(Env("MONKEY_ALLOWED", "MONKEY_UNSET"))
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`getenv("MONKEY_ALLOWED")`, "visible"},
+		{`getenv("MONKEY_SECRET")`, "null"},
+		{`getenv("MONKEY_UNSET")`, "null"},
+		{`getenv("PATH")`, "null"},
+	}
+	for _, test := range tests {
+		if result := run(t, test.input, env); result.Inspect() != test.expected {
+			t.Errorf("%s - Expected=%s Got=%s", test.input, test.expected, result.Inspect())
+		}
+	}
+}
+
+func TestClockAndRandom(t *testing.T) {
+	clock := func() time.Time { return time.Unix(1600000000, 250000000) }
+	env := NewEnvironment(Clock(clock), Random(rand.NewSource(42)))
+	if result := run(t, "now()", env); result.Inspect() != "1600000000250" {
+		t.Errorf("Wrong time Expected=1600000000250 Got=%s", result.Inspect())
+	}
+	expected := rand.New(rand.NewSource(42))
+	for i := 0; i < 5; i++ {
+		result := run(t, "random(10)", env)
+		if n := result.(*object.Integer).Value; n != expected.Int63n(10) {
+			t.Errorf("%d - Wrong random number Got=%d", i, n)
+		}
+	}
+	expectError(t, "random(0)", run(t, "random(0)", env), object.VALUE_ERROR)
+	expectError(t, "now(1)", run(t, "now(1)", env), object.ARGUMENT_ERROR)
+}
+
+func TestBuiltinsAreAllowListed(t *testing.T) {
+	env := NewEnvironment()
+	for _, input := range []string{`puts("x")`, `spawn(fn() { 1 })`, `channel(1)`} {
+		expectError(t, input, run(t, input, env), object.NAME_ERROR)
+		expectError(t, input, run(t, "let f = fn() { "+input+" }; f()", env), object.NAME_ERROR)
+	}
+	known := make(map[string]bool)
+	for _, name := range evaluator.BuiltinNames() {
+		known[name] = true
+	}
+	for _, name := range pureBuiltins {
+		if !known[name] {
+			t.Errorf("Expected %s to be a builtin", name)
+		}
+		if result := run(t, name, env); result.Type() != object.BUILTIN_OBJ {
+			t.Errorf("Expected %s to be available Got=%s", name, result.Inspect())
+		}
+	}
+	if result := run(t, "puts", object.NewEnvironment()); result.Type() != object.BUILTIN_OBJ {
+		t.Errorf("Expected puts to be available outside of a sandbox Got=%s", result.Inspect())
+	}
+}
+
+func TestOutputAndConcurrency(t *testing.T) {
+	var out strings.Builder
+	env := NewEnvironment(Output(&out), Concurrency())
+	input := "let ch = channel(1); let t = spawn(fn() { send(ch, 21) }); await(t); puts(recv(ch) * 2, \"done\")"
+	if result := run(t, input, env); result != evaluator.NULL {
+		t.Errorf("Unexpected result Got=%s", result.Inspect())
+	}
+	if out.String() != "42\ndone\n" {
+		t.Errorf("Wrong output Expected=%q Got=%q", "42\ndone\n", out.String())
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/CzarSimon/monkey/coverage"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/testrunner"
+)
+
+// testCommand Runs the test functions of the test files found in the supplied
+// paths, the current directory by default, exits with 1 if any test did not pass
+func testCommand(args []string) int {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	searchPath := flags.String("path", "", "list of directories searched for imported modules")
+	format := flags.String("format", "human", "format of the report, human or junit")
+	output := flags.String("o", "", "file the report is written to instead of stdout")
+	run := flags.String("run", "", "regular expression selecting the tests to run")
+	verbose := flags.Bool("v", false, "list passing tests in human reports")
+	cover := flags.String("cover", "", "file an LCOV coverage report of the code under test is written to, a summary is printed to stderr")
+	flags.Parse(args)
+	if *format != "human" && *format != "junit" {
+		fmt.Fprintln(os.Stderr, "Usage: monkey test [-path dirs] [-format human|junit] [-o file] [-run regexp] [-v] [-cover file] [paths...]")
+		return 2
+	}
+	if *searchPath != "" {
+		evaluator.Modules.SearchPath = append(filepath.SplitList(*searchPath), evaluator.Modules.SearchPath...)
+	}
+	var filter *regexp.Regexp
+	if *run != "" {
+		var err error
+		if filter, err = regexp.Compile(*run); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := testrunner.Discover(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "No test files found")
+		return 0
+	}
+	var c *coverage.Coverage
+	if *cover != "" {
+		c = coverage.New()
+		c.Exclude = func(filename string) bool {
+			return strings.HasSuffix(filename, testrunner.TEST_FILE_SUFFIX)
+		}
+		defer evaluator.SetHook(evaluator.SetHook(c))
+	}
+	results := testrunner.New(filter).Run(files)
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+	if *format == "junit" {
+		if err := testrunner.WriteJUnit(out, results); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		testrunner.WriteHuman(out, results, *verbose)
+	}
+	if c != nil {
+		if err := writeCoverage(c, *cover); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if !testrunner.Summarize(results).OK() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,220 @@
+package testrunner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/CzarSimon/monkey/object"
+)
+
+// Summary Number of tests with each status
+type Summary struct {
+	Passed   int
+	Failed   int
+	Errored  int
+	Duration time.Duration
+}
+
+// Summarize Counts the results with each status
+func Summarize(results []Result) Summary {
+	var summary Summary
+	for _, result := range results {
+		switch result.Status {
+		case PASS:
+			summary.Passed++
+		case FAIL:
+			summary.Failed++
+		case ERROR:
+			summary.Errored++
+		}
+		summary.Duration += result.Duration
+	}
+	return summary
+}
+
+// Total Returns the number of tests run
+func (summary Summary) Total() int {
+	return summary.Passed + summary.Failed + summary.Errored
+}
+
+// OK Checks if every test passed
+func (summary Summary) OK() bool {
+	return summary.Failed == 0 && summary.Errored == 0
+}
+
+// String Returns the counts of the summary, e.g. 3 passed, 1 failed
+func (summary Summary) String() string {
+	counts := []string{fmt.Sprintf("%d passed", summary.Passed)}
+	if summary.Failed > 0 {
+		counts = append(counts, fmt.Sprintf("%d failed", summary.Failed))
+	}
+	if summary.Errored > 0 {
+		counts = append(counts, fmt.Sprintf("%d errored", summary.Errored))
+	}
+	return strings.Join(counts, ", ")
+}
+
+// WriteHuman Writes a report of the results for people, listing the tests that
+// did not pass with their errors and a summary of each file. Passing tests are
+// listed as well if verbose is set
+func WriteHuman(w io.Writer, results []Result, verbose bool) {
+	for _, file := range groupByFile(results) {
+		for _, result := range file.results {
+			if result.Status == PASS && !verbose {
+				continue
+			}
+			fmt.Fprintf(w, "--- %s: %s (%.2fs)\n", result.Status, result.Name, result.Duration.Seconds())
+			if result.Err != nil {
+				fmt.Fprintf(w, "    %s\n", describeError(result.Err))
+			}
+			if result.Status == ERROR {
+				for _, frame := range testFrames(result.Err) {
+					fmt.Fprintf(w, "        at %s\n", frame)
+				}
+			}
+		}
+		summary := Summarize(file.results)
+		status := "ok  "
+		if !summary.OK() {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "%s %s (%s)\n", status, file.name, summary)
+	}
+	summary := Summarize(results)
+	status := "PASS"
+	if !summary.OK() {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w, "%s: %d tests, %s\n", status, summary.Total(), summary)
+}
+
+// junitTestSuites Root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite Tests of a file in a JUnit XML report
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase Test in a JUnit XML report
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+// junitFailure Failure or error of a test in a JUnit XML report
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit Writes a JUnit XML report of the results with a test suite for each file
+func WriteJUnit(w io.Writer, results []Result) error {
+	summary := Summarize(results)
+	report := junitTestSuites{
+		Tests:    summary.Total(),
+		Failures: summary.Failed,
+		Errors:   summary.Errored,
+		Time:     formatSeconds(summary.Duration),
+		Suites:   make([]junitTestSuite, 0),
+	}
+	for _, file := range groupByFile(results) {
+		fileSummary := Summarize(file.results)
+		suite := junitTestSuite{
+			Name:     file.name,
+			Tests:    fileSummary.Total(),
+			Failures: fileSummary.Failed,
+			Errors:   fileSummary.Errored,
+			Time:     formatSeconds(fileSummary.Duration),
+			Cases:    make([]junitTestCase, 0, len(file.results)),
+		}
+		for _, result := range file.results {
+			testCase := junitTestCase{Name: result.Name, ClassName: file.name, Time: formatSeconds(result.Duration)}
+			if result.Err != nil {
+				failure := &junitFailure{
+					Message: result.Err.Message,
+					Type:    string(result.Err.Kind),
+					Text:    describeError(result.Err),
+				}
+				if result.Status == FAIL {
+					testCase.Failure = failure
+				} else {
+					testCase.Error = failure
+				}
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		report.Suites = append(report.Suites, suite)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// describeError Returns the position, kind and message of an error
+func describeError(err *object.Error) string {
+	return fmt.Sprintf("%s: %s: %s", err.Position, err.Kind, err.Message)
+}
+
+// testFrames Returns the frames executing when an error was raised in a test,
+// innermost first, leaving out the frame of the runner calling the test
+func testFrames(err *object.Error) []object.Frame {
+	trace := err.Trace()
+	frames := make([]object.Frame, 0, len(trace))
+	for i := len(trace) - 1; i >= 0; i-- {
+		if i == 0 && !trace[i].Position.IsValid() {
+			continue
+		}
+		frames = append(frames, trace[i])
+	}
+	return frames
+}
+
+// fileResults Results of the tests of a file
+type fileResults struct {
+	name    string
+	results []Result
+}
+
+// groupByFile Groups results by file keeping the order the files were run in
+func groupByFile(results []Result) []fileResults {
+	files := make([]fileResults, 0)
+	for _, result := range results {
+		if len(files) == 0 || files[len(files)-1].name != result.File {
+			files = append(files, fileResults{name: result.File})
+		}
+		files[len(files)-1].results = append(files[len(files)-1].results, result)
+	}
+	return files
+}
+
+// formatSeconds Formats a duration as seconds with millisecond precision
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,192 @@
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+// TEST_FILE_SUFFIX Suffix of the files holding tests
+const TEST_FILE_SUFFIX = "_test.monkey"
+
+// TEST_FUNCTION_PREFIX Prefix of the names of top level test functions
+const TEST_FUNCTION_PREFIX = "test_"
+
+// SETUP_TEST Name of the result reported when a test file cannot be parsed
+const SETUP_TEST = "<module>"
+
+// Status Outcome of a test
+type Status string
+
+const (
+	PASS  Status = "PASS"
+	FAIL  Status = "FAIL"  // the test raised an AssertionError
+	ERROR Status = "ERROR" // the test raised any other error
+)
+
+// Result Outcome of running a test function
+type Result struct {
+	File     string
+	Name     string
+	Status   Status
+	Err      *object.Error // error raised by a test that did not pass
+	Duration time.Duration
+}
+
+// Runner Runs the test functions of test files
+type Runner struct {
+	Filter *regexp.Regexp // only tests with matching names are run, nil runs every test
+}
+
+// New Creates a Runner running the tests matched by filter, nil matches every test
+func New(filter *regexp.Regexp) *Runner {
+	return &Runner{Filter: filter}
+}
+
+// Discover Returns the test files among the supplied paths, directories
+// are searched recursively for files ending with TEST_FILE_SUFFIX
+func Discover(paths []string) ([]string, error) {
+	files := make([]string, 0)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(file, TEST_FILE_SUFFIX) {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// RunFile Runs the test functions of a file in the order they are declared.
+// Each test is run after evaluating the file in a fresh environment
+func (runner *Runner) RunFile(filename string) []Result {
+	program, err := loadTestFile(filename)
+	if err != nil {
+		return []Result{{File: filename, Name: SETUP_TEST, Status: ERROR, Err: err}}
+	}
+	results := make([]Result, 0)
+	for _, name := range TestNames(program) {
+		if runner.Filter != nil && !runner.Filter.MatchString(name) {
+			continue
+		}
+		results = append(results, runTest(filename, name, program))
+	}
+	return results
+}
+
+// Run Runs the tests of every file
+func (runner *Runner) Run(files []string) []Result {
+	results := make([]Result, 0)
+	for _, file := range files {
+		results = append(results, runner.RunFile(file)...)
+	}
+	return results
+}
+
+// TestNames Returns the names of the test functions bound by the top
+// level let and function statements of a program
+func TestNames(program *ast.Program) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, stmt := range program.Statements {
+		if exportStmt, ok := stmt.(*ast.ExportStatement); ok && exportStmt.Statement != nil {
+			stmt = exportStmt.Statement
+		}
+		var name string
+		switch stmt := stmt.(type) {
+		case *ast.FunctionStatement:
+			name = stmt.Name.Value
+		case *ast.LetStatement:
+			if _, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil {
+				name = stmt.Name.Value
+			}
+		}
+		if strings.HasPrefix(name, TEST_FUNCTION_PREFIX) && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// runTest Runs a test function and measures how long it takes
+func runTest(filename, name string, program *ast.Program) Result {
+	start := time.Now()
+	status, err := callTest(name, program)
+	return Result{File: filename, Name: name, Status: status, Err: err, Duration: time.Since(start)}
+}
+
+// callTest Evaluates a program in a fresh environment and calls one of its test functions
+func callTest(name string, program *ast.Program) (Status, *object.Error) {
+	env := object.NewEnvironment()
+	if err, ok := evaluator.Eval(program, env).(*object.Error); ok {
+		return statusOf(err), err
+	}
+	fn, err := env.Get(name)
+	if err != nil {
+		return ERROR, err
+	}
+	if err, ok := evaluator.Call(env, fn, []object.Object{}).(*object.Error); ok {
+		return statusOf(err), err
+	}
+	return PASS, nil
+}
+
+// statusOf Returns the status of a test that raised an error
+func statusOf(err *object.Error) Status {
+	if err.Kind == object.ASSERTION_ERROR {
+		return FAIL
+	}
+	return ERROR
+}
+
+// loadTestFile Reads and parses a test file and expands its macros
+func loadTestFile(filename string) (*ast.Program, *object.Error) {
+	source, readErr := os.ReadFile(filename)
+	if readErr != nil {
+		return nil, object.NewErrorf("Could not read %s: %s", filename, readErr)
+	}
+	p := parser.New(lexer.NewWithFilename(string(source), filename))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		parseErr, ok := p.Errors()[0].(*parser.ParseError)
+		if !ok {
+			return nil, object.NewErrorf("%s", p.Errors()[0])
+		}
+		err := object.NewError(parseErr.Message)
+		err.Position = parseErr.Position
+		return nil, err
+	}
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, err := evaluator.ExpandMacros(program, macroEnv)
+	if err != nil {
+		return nil, err
+	}
+	return expanded.(*ast.Program), nil
+}
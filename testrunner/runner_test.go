@@ -0,0 +1,159 @@
+package testrunner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const mathTests = `const limit = 10;
+let add = fn(a, b) { a + b };
+fn test_add() {
+  assert_eq(add(1, 2), 3)
+}
+let test_fail = fn() {
+  assert(add(1, 1) > limit, "sum too small")
+};
+let check = fn(x) { 1 / x };
+let test_error = fn() {
+  check(0)
+};
+let helper_test = fn() { assert(false) };
+`
+
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		if err := os.WriteFile(path, []byte(source), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDiscover(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"a_test.monkey":        "",
+		"lib.monkey":           "",
+		"nested/b_test.monkey": "",
+		"nested/c.monkey":      "",
+	})
+	files, err := Discover([]string{dir, filepath.Join(dir, "lib.monkey")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []string{
+		filepath.Join(dir, "a_test.monkey"),
+		filepath.Join(dir, "lib.monkey"),
+		filepath.Join(dir, "nested", "b_test.monkey"),
+	}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("Wrong files Expected=%v Got=%v", expected, files)
+	}
+	if _, err := Discover([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Errorf("Expected an error for a missing path")
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"math_test.monkey":   mathTests,
+		"broken_test.monkey": "let = fn() { 1 };",
+	})
+	file := filepath.Join(dir, "math_test.monkey")
+	results := New(nil).RunFile(file)
+	expected := []struct {
+		name     string
+		status   Status
+		position string
+		message  string
+	}{
+		{"test_add", PASS, "", ""},
+		{"test_fail", FAIL, file + ":7:9", "sum too small: Assertion failed"},
+		{"test_error", ERROR, file + ":9:23", "Division by zero"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Wrong number of results Expected=%d Got=%+v", len(expected), results)
+	}
+	for i, test := range expected {
+		result := results[i]
+		if result.Name != test.name || result.Status != test.status || result.File != file {
+			t.Errorf("%d - Wrong result Expected=%s %s Got=%s %s", i, test.name, test.status, result.Name, result.Status)
+		}
+		if test.status == PASS {
+			if result.Err != nil {
+				t.Errorf("%d - Unexpected error: %s", i, result.Err.Message)
+			}
+			continue
+		}
+		if result.Err.Position.String() != test.position || result.Err.Message != test.message {
+			t.Errorf("%d - Wrong error Expected=%s %s Got=%s %s", i, test.position, test.message,
+				result.Err.Position, result.Err.Message)
+		}
+	}
+
+	filtered := New(regexp.MustCompile("fail|error$")).RunFile(file)
+	if len(filtered) != 2 || filtered[0].Name != "test_fail" || filtered[1].Name != "test_error" {
+		t.Errorf("Wrong filtered results Got=%+v", filtered)
+	}
+
+	broken := New(nil).RunFile(filepath.Join(dir, "broken_test.monkey"))
+	if len(broken) != 1 || broken[0].Name != SETUP_TEST || broken[0].Status != ERROR || !broken[0].Err.Position.IsValid() {
+		t.Errorf("Expected a setup error Got=%+v", broken)
+	}
+}
+
+func TestReports(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"math_test.monkey": mathTests})
+	file := filepath.Join(dir, "math_test.monkey")
+	results := New(nil).Run([]string{file})
+	for i := range results {
+		results[i].Duration = 0
+	}
+	var human bytes.Buffer
+	WriteHuman(&human, results, true)
+	expectedHuman := "--- PASS: test_add (0.00s)\n" +
+		"--- FAIL: test_fail (0.00s)\n" +
+		"    " + file + ":7:9: AssertionError: sum too small: Assertion failed\n" +
+		"--- ERROR: test_error (0.00s)\n" +
+		"    " + file + ":9:23: ArithmeticError: Division by zero\n" +
+		"        at " + file + ":9:23 in check\n" +
+		"        at " + file + ":11:8 in test_error\n" +
+		"FAIL " + file + " (1 passed, 1 failed, 1 errored)\n" +
+		"FAIL: 3 tests, 1 passed, 1 failed, 1 errored\n"
+	if human.String() != expectedHuman {
+		t.Errorf("Wrong human report\nExpected=%q\nGot=%q", expectedHuman, human.String())
+	}
+
+	var junit bytes.Buffer
+	if err := WriteJUnit(&junit, results); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expectedJUnit := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" errors="1" time="0.000">
+  <testsuite name="` + file + `" tests="3" failures="1" errors="1" time="0.000">
+    <testcase name="test_add" classname="` + file + `" time="0.000"></testcase>
+    <testcase name="test_fail" classname="` + file + `" time="0.000">
+      <failure message="sum too small: Assertion failed" type="AssertionError">` + file + `:7:9: AssertionError: sum too small: Assertion failed</failure>
+    </testcase>
+    <testcase name="test_error" classname="` + file + `" time="0.000">
+      <error message="Division by zero" type="ArithmeticError">` + file + `:9:23: ArithmeticError: Division by zero</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if junit.String() != expectedJUnit {
+		t.Errorf("Wrong JUnit report\nExpected=%s\nGot=%s", expectedJUnit, junit.String())
+	}
+
+	var passing bytes.Buffer
+	WriteHuman(&passing, results[:1], false)
+	if passing.String() != "ok   "+file+" (1 passed)\nPASS: 1 tests, 1 passed\n" {
+		t.Errorf("Wrong report of passing tests Got=%q", passing.String())
+	}
+}
@@ -1,12 +1,40 @@
 package token
 
+import (
+	"fmt"
+	"sort"
+)
+
 // TokenType Represents a possible type of tokens
 type TokenType string
 
 // Token Lexer representation of source code componentes
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type     TokenType
+	Literal  string
+	Position Position
+}
+
+// Position Line and column where a token starts in the source code, both starting at 1,
+// and the name of the file the source was read from if any
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// String Returns a file:line:column representation of the Position,
+// or line:column if the Position has no file name
+func (pos Position) String() string {
+	if pos.Filename != "" {
+		return fmt.Sprintf("%s:%d:%d", pos.Filename, pos.Line, pos.Column)
+	}
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// IsValid Checks if the Position has been set
+func (pos Position) IsValid() bool {
+	return pos.Line > 0
 }
 
 // LookupIdent Checks if a provided string is a keywords, if so returns its Type
@@ -31,13 +59,32 @@ func (token Token) IsEmpty() bool {
 	return token.Type == "" && token.Literal == ""
 }
 
+// Keywords Returns the reserved keywords of the language in sorted order
+func Keywords() []string {
+	words := make([]string, 0, len(keywords))
+	for word := range keywords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
 // keywords Map of keywords to token type
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETRUN,
-	"true":   TRUE,
-	"false":  FALSE,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"const":   CONST,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETRUN,
+	"true":    TRUE,
+	"false":   FALSE,
+	"import":  IMPORT,
+	"export":  EXPORT,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
+	"match":   MATCH,
+	"macro":   MACRO,
 }
@@ -3,10 +3,12 @@ package token
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT" // type of the comments recorded by the lexer, never returned by NextToken
 
 	// Indentifiers + literals
-	IDENT = "IDENT" // basically variable name
-	INT   = "INT"   // Integer type
+	IDENT  = "IDENT"  // basically variable name
+	INT    = "INT"    // Integer type
+	STRING = "STRING" // String type
 
 	// Operators
 	ASSIGN = "="
@@ -26,18 +28,33 @@ const (
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
-
-	LPAREN = "("
-	RPAREN = ")"
-	LBRACE = "{"
-	RBRACE = "}"
+	DOT       = "."
+	COLON     = ":"
+	ARROW     = "=>"
+	ELLIPSIS  = "..."
+
+	LPAREN   = "("
+	RPAREN   = ")"
+	LBRACE   = "{"
+	RBRACE   = "}"
+	LBRACKET = "["
+	RBRACKET = "]"
 
 	// Keywords
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETRUN   = "RETURN"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	MATCH    = "MATCH"
+	MACRO    = "MACRO"
 )
@@ -41,3 +41,17 @@ func TestIsEmpty(t *testing.T) {
 	}
 
 }
+
+func TestKeywords(t *testing.T) {
+	expected := []string{"catch", "const", "else", "export", "false", "finally", "fn", "if", "import",
+		"let", "macro", "match", "return", "throw", "true", "try"}
+	words := Keywords()
+	if len(words) != len(expected) {
+		t.Fatalf("Wrong number of keywords, expected=%d got=%d", len(expected), len(words))
+	}
+	for i, word := range expected {
+		if words[i] != word {
+			t.Fatalf("Test[%d] - Wrong keyword, expected=%q got=%q", i, word, words[i])
+		}
+	}
+}
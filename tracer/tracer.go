@@ -0,0 +1,126 @@
+package tracer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CzarSimon/monkey/ast"
+	"github.com/CzarSimon/monkey/evaluator"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/token"
+)
+
+// INDENT Indentation added for each call in progress in text traces
+const INDENT = "  "
+
+// Format Format events are written in
+type Format int
+
+const (
+	TEXT       Format = iota // one indented line per event
+	JSON_LINES               // one JSON object per line
+)
+
+// Event Evaluation of a node
+type Event struct {
+	Position string `json:"position"`
+	Kind     string `json:"kind"`     // type of the node, e.g. InfixExpression
+	Node     string `json:"node"`     // String() of the node
+	Value    string `json:"value"`    // Inspect() of the result, empty if there is none
+	Function string `json:"function"` // innermost function being called
+	Depth    int    `json:"depth"`    // number of calls in progress
+}
+
+// String Returns a text representation of the Event indented by its depth
+func (event Event) String() string {
+	return fmt.Sprintf("%s%s %s %s => %s", strings.Repeat(INDENT, event.Depth),
+		event.Position, event.Kind, event.Node, event.Value)
+}
+
+// Tracer evaluator.Hook writing an Event for each evaluated node once its
+// result is known, nodes are therefore written after the nodes below them
+type Tracer struct {
+	Function string // only nodes evaluated while a function with this name is called are traced, empty traces every node
+	out      io.Writer
+	format   Format
+	calls    []string // names of the functions being called, innermost last
+	matching int      // number of calls in progress to Function
+	err      error
+}
+
+// New Creates a Tracer writing events to out in the supplied format
+func New(out io.Writer, format Format) *Tracer {
+	return &Tracer{
+		out:    out,
+		format: format,
+		calls:  make([]string, 0),
+	}
+}
+
+// Run Evaluates a program in an environment while tracing it
+func (tracer *Tracer) Run(program ast.Node, env *object.Environment) object.Object {
+	defer evaluator.SetHook(evaluator.SetHook(tracer))
+	return evaluator.Eval(program, env)
+}
+
+// Err Returns the first error writing an event, no events are written after it
+func (tracer *Tracer) Err() error {
+	return tracer.err
+}
+
+// AfterNode Writes the event of an evaluated node
+func (tracer *Tracer) AfterNode(node ast.Node, result object.Object) {
+	if tracer.err != nil || (tracer.Function != "" && tracer.matching == 0) {
+		return
+	}
+	event := Event{
+		Position: node.Pos().String(),
+		Kind:     strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast."),
+		Node:     node.String(),
+		Function: object.MODULE_FRAME,
+		Depth:    len(tracer.calls),
+	}
+	if result != nil {
+		event.Value = result.Inspect()
+	}
+	if len(tracer.calls) > 0 {
+		event.Function = tracer.calls[len(tracer.calls)-1]
+	}
+	tracer.write(event)
+}
+
+// write Writes an event in the format of the Tracer
+func (tracer *Tracer) write(event Event) {
+	if tracer.format == JSON_LINES {
+		encoder := json.NewEncoder(tracer.out)
+		encoder.SetEscapeHTML(false)
+		tracer.err = encoder.Encode(event)
+		return
+	}
+	_, tracer.err = fmt.Fprintln(tracer.out, event)
+}
+
+// BeforeCall Enters a call
+func (tracer *Tracer) BeforeCall(fn object.Object, args []object.Object, pos token.Position) {
+	name := evaluator.FunctionName(fn)
+	tracer.calls = append(tracer.calls, name)
+	if name == tracer.Function {
+		tracer.matching++
+	}
+}
+
+// AfterCall Leaves a call
+func (tracer *Tracer) AfterCall(fn object.Object, result object.Object) {
+	name := tracer.calls[len(tracer.calls)-1]
+	tracer.calls = tracer.calls[:len(tracer.calls)-1]
+	if name == tracer.Function {
+		tracer.matching--
+	}
+}
+
+// BeforeStatement Does nothing, statements are traced as nodes
+func (tracer *Tracer) BeforeStatement(stmt ast.Statement, env *object.Environment) *object.Error {
+	return nil
+}
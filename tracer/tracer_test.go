@@ -0,0 +1,84 @@
+package tracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CzarSimon/monkey/lexer"
+	"github.com/CzarSimon/monkey/object"
+	"github.com/CzarSimon/monkey/parser"
+)
+
+const testSource = `let double = fn(x) { x * 2 };
+let quad = fn(x) { double(double(x)) };
+quad(1) < 5`
+
+// trace Evaluates the test source with a Tracer and returns its output
+func trace(t *testing.T, format Format, function string) string {
+	p := parser.New(lexer.New(testSource))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("Unexpected parse errors: %v", p.Errors())
+	}
+	var out bytes.Buffer
+	tracer := New(&out, format)
+	tracer.Function = function
+	if result := tracer.Run(program, object.NewEnvironment()); result.Inspect() != "true" {
+		t.Fatalf("Wrong result Expected=true Got=%s", result.Inspect())
+	}
+	if tracer.Err() != nil {
+		t.Fatalf("Unexpected error: %s", tracer.Err())
+	}
+	return out.String()
+}
+
+func TestTextTrace(t *testing.T) {
+	lines := strings.Split(trace(t, TEXT, ""), "\n")
+	expected := []string{
+		"1:14 FunctionLiteral fn(x) (x * 2) => fn double(x)",
+		"1:1 LetStatement let double = fn(x) (x * 2); => ",
+		"2:12 FunctionLiteral fn(x) double(double(x)) => fn quad(x)",
+		"2:1 LetStatement let quad = fn(x) double(double(x)); => ",
+		"3:1 Identifier quad => fn quad(x)",
+		"3:6 IntegerLiteral 1 => 1",
+		"  2:20 Identifier double => fn double(x)",
+		"  2:27 Identifier double => fn double(x)",
+		"  2:34 Identifier x => 1",
+		"    1:22 Identifier x => 1",
+		"    1:26 IntegerLiteral 2 => 2",
+		"    1:24 InfixExpression (x * 2) => 2",
+		"    1:22 ExpressionStatement (x * 2) => 2",
+		"    1:20 BlockStatement (x * 2) => 2",
+		"  2:33 CallExpression double(x) => 2",
+	}
+	for i, line := range expected {
+		if i >= len(lines) || lines[i] != line {
+			t.Fatalf("%d - Wrong line Expected=%q Got=%q", i, line, lines)
+		}
+	}
+	last := lines[len(lines)-2]
+	if last != "1:1 Program let double = fn(x) (x * 2);let quad = fn(x) double(double(x));(quad(1) < 5) => true" {
+		t.Errorf("Expected the program to be traced last Got=%q", last)
+	}
+}
+
+func TestJSONTraceFilter(t *testing.T) {
+	output := trace(t, JSON_LINES, "double")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("Expected 5 nodes traced for each of the 2 calls to double Got=%d", len(lines))
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+		t.Fatalf("Unexpected error decoding %q: %s", lines[2], err)
+	}
+	expected := Event{Position: "1:24", Kind: "InfixExpression", Node: "(x * 2)", Value: "2", Function: "double", Depth: 2}
+	if event != expected {
+		t.Errorf("Wrong event Expected=%+v Got=%+v", expected, event)
+	}
+	if !strings.Contains(lines[7], `"value":"4","function":"double","depth":2`) {
+		t.Errorf("Wrong event for the second call Got=%s", lines[7])
+	}
+}